@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	agendaWeek bool
+	agendaDays int
+)
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "Print a multi-day agenda in the terminal",
+	Long: `Print the events of the next --days (default 3) grouped by day.
+Use --week for Monday through Sunday of the current week.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAgenda(); err != nil {
+			fmt.Fprintf(os.Stderr, "Agenda failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runAgenda() error {
+	now := calendar.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end := start.AddDate(0, 0, agendaDays)
+	if agendaWeek {
+		// Go weeks start on Sunday; agendas start on Monday
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+		end = start.AddDate(0, 0, 7)
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events, err := calendarService.GetEventsBetween(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	fmt.Println(widget.RenderAgenda(events, start, end))
+	return nil
+}
+
+func init() {
+	agendaCmd.Flags().BoolVar(&agendaWeek, "week", false, "show the current week (Monday to Sunday)")
+	agendaCmd.Flags().IntVar(&agendaDays, "days", 3, "number of days to show, starting today")
+	agendaCmd.MarkFlagsMutuallyExclusive("week", "days")
+	addAtFlag(agendaCmd)
+	rootCmd.AddCommand(agendaCmd)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/selection"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	attachmentsDownload bool
+	attachmentsDir      string
+)
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments",
+	Short: "List or download the current or next meeting's attachments",
+	Long: `List the files attached to the current or next meeting, and the links to shared documents
+(SharePoint, OneDrive, Google Drive, ...) in its invite. With --download the attached files are
+saved to ~/Downloads, or to --dir. Attachments need Microsoft Graph; other providers only list
+document links.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAttachments(); err != nil {
+			fmt.Fprintf(os.Stderr, "Attachments failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runAttachments() error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+
+	links := event.DocumentLinks()
+	if !event.HasAttachments && len(links) == 0 {
+		return fmt.Errorf("%s has no attachments or document links", event.Subject)
+	}
+
+	fmt.Printf("📎 %s\n", event.Subject)
+	for _, link := range links {
+		fmt.Printf("🔗 %s\n", link)
+	}
+	if !event.HasAttachments {
+		return nil
+	}
+
+	fetcher, ok := calendarService.(calendar.AttachmentFetcher)
+	if !ok {
+		fmt.Println("This meeting has attachments, but listing them needs Microsoft Graph")
+		return nil
+	}
+
+	attachments, err := fetcher.ListAttachments(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	dir := attachmentsDir
+	if attachmentsDownload && dir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(homeDir, "Downloads")
+	}
+
+	for _, attachment := range attachments {
+		if attachment.Inline {
+			// Images embedded in the invite body
+			continue
+		}
+		fmt.Printf("📄 %s (%s)\n", attachment.Name, formatSize(attachment.Size))
+
+		if !attachmentsDownload {
+			continue
+		}
+		if attachment.Kind != calendar.AttachmentFile {
+			fmt.Printf("   skipped: only attached files can be downloaded\n")
+			continue
+		}
+		path, err := downloadAttachment(ctx, fetcher, event.ID, attachment, dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("   saved to %s\n", path)
+	}
+	return nil
+}
+
+// downloadAttachment saves an attachment to dir without overwriting
+// existing files, and returns the path it was saved to
+func downloadAttachment(ctx context.Context, fetcher calendar.AttachmentFetcher, eventID string, attachment calendar.Attachment, dir string) (string, error) {
+	content, err := fetcher.DownloadAttachment(ctx, eventID, attachment.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	// The name comes from whoever sent the invite, so keep it inside dir
+	name := filepath.Base(filepath.Clean("/" + attachment.Name))
+	if name == "/" || name == "." {
+		name = "attachment"
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, name)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if _, err := file.Write(content); err != nil {
+			file.Close()
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := file.Close(); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return path, nil
+	}
+}
+
+// formatSize renders a byte count as "512 B", "14 KB" or "2.3 MB"
+func formatSize(size int) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%d KB", size/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+func init() {
+	attachmentsCmd.Flags().BoolVar(&attachmentsDownload, "download", false, "download the attached files")
+	attachmentsCmd.Flags().StringVar(&attachmentsDir, "dir", "", "directory to download to (default ~/Downloads)")
+	rootCmd.AddCommand(attachmentsCmd)
+}
@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"calendar-widget/internal/cache"
 	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/notes"
+	"calendar-widget/internal/opener"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/snooze"
 	"calendar-widget/internal/widget"
 	"context"
 	"fmt"
@@ -13,36 +19,179 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// outlookCalendarURL is opened by the "outlook" click action
+const outlookCalendarURL = "https://outlook.office.com/calendar/view/day"
+
+var (
+	clickButton   string
+	clickPrintURL bool
+)
+
 var clickCmd = &cobra.Command{
 	Use:   "click",
 	Short: "Handle calendar widget clicks intelligently",
-	Long:  `Handle clicks on the calendar widget. If authentication is required, run reauth. Otherwise, open the current meeting.`,
+	Long: `Handle clicks on the calendar widget. Each --button is mapped to an action in settings.json.
+The default left-click action runs reauth if authentication is required, otherwise opens the current meeting.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runClick(); err != nil {
+		if err := runClickButton(clickButton); err != nil {
 			fmt.Printf("Click handler failed: %v\n", err)
 			os.Exit(1)
 		}
 	},
 }
 
-func runClick() error {
-	// First, check what's the current status by running waybar once
-	_, err := widget.NewWidgetWithOptions(&widget.Config{
-		RefreshInterval: 60,
-		Compact:         true,
-		Debug:           debug,
-	}, false) // Start non-interactive
+func runClickButton(button string) error {
+	settings := loadSettings()
+
+	// A chip always stands for one meeting, so every button opens it
+	if chipSlot >= 0 {
+		return openChip(chipSlot)
+	}
+
+	action, err := settings.Click.Action(button)
 	if err != nil {
-		fmt.Printf("Failed to create widget: %v\n", err)
-		return runReauth()
+		return err
+	}
+
+	switch action {
+	case config.ActionOpenMeeting:
+		return runClick()
+	case config.ActionTUI:
+		return openTUI(settings.Terminal)
+	case config.ActionRefresh:
+		return runClickRefresh(settings.Click.Signal)
+	case config.ActionOutlook:
+		return openMeetingLink(outlookCalendarURL)
+	case config.ActionSnooze:
+		return toggleSnooze(settings.Click.SnoozeFor, settings.Click.Signal)
+	case config.ActionNotes:
+		return openNotes(settings.Notes)
+	case config.ActionNone:
+		return nil
+	default:
+		return fmt.Errorf("unknown click action %q", action)
+	}
+}
+
+// openChip opens the meeting shown in the given chip of --display chips
+func openChip(slot int) error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	chips := widget.ChipEvents(upcomingEvents, chipsWithin)
+	if slot >= len(chips) {
+		return fmt.Errorf("no meeting in chip %d", slot)
+	}
+	link, err := widget.MeetingLink(chips[slot])
+	if err != nil {
+		return err
+	}
+	return openMeetingLink(link)
+}
+
+// openTUI launches the interactive widget in a terminal window
+func openTUI(terminal string) error {
+	if terminal == "" {
+		terminal = os.Getenv("TERMINAL")
+	}
+	if terminal == "" {
+		terminal = "xterm"
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	return exec.Command(terminal, "-e", self, "widget").Start()
+}
+
+// runClickRefresh refetches the event cache and asks waybar to redraw
+func runClickRefresh(signal int) error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := cache.Refresh(ctx, calendarService); err != nil {
+		return fmt.Errorf("failed to refresh events: %w", err)
+	}
+
+	signalWaybar(signal)
+
+	return nil
+}
+
+// openNotes opens (creating if needed) the notes for the current or next meeting
+func openNotes(notesConfig config.NotesConfig) error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+
+	open := widget.OpenURL
+	if clickPrintURL {
+		open = func(target string) error {
+			fmt.Println(target)
+			return nil
+		}
+	}
+	return notes.Open(*event, notesConfig, open)
+}
+
+// toggleSnooze clears an active snooze, or starts one for the given duration
+func toggleSnooze(duration string, signal int) error {
+	var err error
+	if _, snoozed := snooze.Active(); snoozed {
+		err = snooze.Clear()
+	} else {
+		var until time.Time
+		until, err = snooze.ParseUntil(duration, time.Now())
+		if err == nil {
+			err = snooze.Set(until)
+		}
+	}
+	if err != nil {
+		return err
 	}
 
+	signalWaybar(signal)
+	return nil
+}
+
+func runClick() error {
 	// Capture output to check for "Auth Required"
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Try to get upcoming events to see what the status is
-	calendarService, err := calendar.NewCalendarServiceWithOptions(false)
+	calendarService, err := newCalendarProvider(false, false)
 	if err != nil {
 		if isAuthError(err) {
 			fmt.Println("Authentication required, forcing token refresh...")
@@ -61,10 +210,9 @@ func runClick() error {
 	}
 
 	// Find the best event to open using the same prioritization as the widget
-	bestEvent := selectBestEventForClick(upcomingEvents)
+	bestEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
 	if bestEvent != nil {
-		status := bestEvent.GetStatus()
-		if status == "current" || status == "urgent" {
+		if bestEvent.IsImminent() {
 			if bestEvent.IsTeams && bestEvent.TeamsLink != "" {
 				return openMeetingLink(bestEvent.TeamsLink)
 			} else if bestEvent.WebLink != "" {
@@ -78,22 +226,11 @@ func runClick() error {
 }
 
 func runClickWithForceRefresh() error {
-	// Create widget with force refresh
-	_, err := widget.NewWidgetWithOptions(&widget.Config{
-		RefreshInterval: 60,
-		Compact:         true,
-		Debug:           debug,
-	}, true) // Allow interactive for force refresh
-	if err != nil {
-		fmt.Printf("Failed to create widget with refresh: %v\n", err)
-		return runReauth()
-	}
-
 	// Try with force refresh
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	calendarService, err := calendar.NewCalendarServiceWithRefresh(true, true) // Interactive + force refresh
+	calendarService, err := newCalendarProvider(true, true) // Interactive + force refresh
 	if err != nil {
 		fmt.Printf("Force refresh failed: %v\n", err)
 		return runReauth()
@@ -110,10 +247,9 @@ func runClickWithForceRefresh() error {
 	}
 
 	// Find the best event to open using the same prioritization as the widget
-	bestEvent := selectBestEventForClick(upcomingEvents)
+	bestEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
 	if bestEvent != nil {
-		status := bestEvent.GetStatus()
-		if status == "current" || status == "urgent" {
+		if bestEvent.IsImminent() {
 			if bestEvent.IsTeams && bestEvent.TeamsLink != "" {
 				return openMeetingLink(bestEvent.TeamsLink)
 			} else if bestEvent.WebLink != "" {
@@ -137,62 +273,19 @@ func isAuthError(err error) bool {
 		strings.Contains(errStr, "unauthorized")
 }
 
+// openMeetingLink opens url with the opener configured for it, or prints it
+// with --print-url
 func openMeetingLink(url string) error {
-	// Use the same logic as the widget's openMeeting function
-	var cmd string
-	switch {
-	case strings.Contains(url, "teams.microsoft.com"):
-		// Try to open in Teams app first, fallback to browser
-		cmd = fmt.Sprintf(`sh -c 'xdg-open "msteams://" 2>/dev/null && sleep 1 && xdg-open "%s" || xdg-open "%s"'`, url, url)
-	default:
-		cmd = fmt.Sprintf(`xdg-open "%s"`, url)
-	}
-
-	return runBashCommand(cmd)
-}
-
-func runBashCommand(command string) error {
-	// Execute the command using shell
-	exec := exec.Command("sh", "-c", command)
-	return exec.Run()
-}
-
-func selectBestEventForClick(events []calendar.Event) *calendar.Event {
-	if len(events) == 0 {
+	if clickPrintURL {
+		fmt.Println(url)
 		return nil
 	}
-
-	now := time.Now()
-	statusPriority := []string{"current", "urgent", "soon", "upcoming"}
-
-	// For each status level, first look for blocking events, then fall back to any event
-	for _, targetStatus := range statusPriority {
-		// First pass: find blocking events with this status
-		for _, event := range events {
-			status := event.GetStatus()
-			if status == targetStatus && event.IsBlockingEvent() {
-				if targetStatus == "upcoming" && !event.Start.After(now) {
-					continue
-				}
-				return &event
-			}
-		}
-
-		// Second pass: find any event with this status (fallback for all-day/long events)
-		for _, event := range events {
-			status := event.GetStatus()
-			if status == targetStatus {
-				if targetStatus == "upcoming" && !event.Start.After(now) {
-					continue
-				}
-				return &event
-			}
-		}
-	}
-
-	return nil
+	return opener.Open(url)
 }
 
 func init() {
+	clickCmd.Flags().StringVar(&clickButton, "button", "left", "mouse button that was used (left|middle|right|scroll-up|scroll-down)")
+	clickCmd.Flags().BoolVar(&clickPrintURL, "print-url", false, "print the link instead of opening it, e.g. for piping into another program")
+	addChipsFlags(clickCmd)
 	rootCmd.AddCommand(clickCmd)
 }
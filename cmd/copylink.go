@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/clipboard"
+	"calendar-widget/internal/selection"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var copyLinkCmd = &cobra.Command{
+	Use:   "copy-link",
+	Short: "Copy the current or next meeting's join link to the clipboard",
+	Long: `Put the join link of the current or next meeting on the clipboard for pasting into chats.
+Uses wl-copy under Wayland and falls back to the terminal's OSC 52 clipboard support.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCopyLink(); err != nil {
+			fmt.Fprintf(os.Stderr, "Copy link failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runCopyLink() error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+
+	link := event.GetJoinLink()
+	if link == "" {
+		return fmt.Errorf("%s has no join link", event.Subject)
+	}
+
+	if err := clipboard.Copy(link); err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied join link for %s\n", event.Subject)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(copyLinkCmd)
+}
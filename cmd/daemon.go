@@ -0,0 +1,634 @@
+package cmd
+
+import (
+	"calendar-widget/internal/alerts"
+	"calendar-widget/internal/autojoin"
+	"calendar-widget/internal/autostatus"
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/dismiss"
+	"calendar-widget/internal/homeassistant"
+	"calendar-widget/internal/hooks"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/idle"
+	"calendar-widget/internal/live"
+	"calendar-widget/internal/mqtt"
+	"calendar-widget/internal/notify"
+	"calendar-widget/internal/push"
+	"calendar-widget/internal/screenshare"
+	"calendar-widget/internal/snooze"
+	"calendar-widget/internal/sound"
+	"calendar-widget/internal/webhook"
+	"calendar-widget/internal/widget"
+	"calendar-widget/internal/xdg"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// screenShareInterval is how often the daemon checks for screen sharing when
+// auto privacy is on
+const screenShareInterval = 5 * time.Second
+
+var (
+	daemonRefresh    int
+	waybarSignal     int
+	daemonAutojoin   bool
+	daemonSounds     bool
+	daemonPresence   bool
+	daemonAutoStatus bool
+	daemonMQTT       bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep the local event cache fresh",
+	Long: `Run in the background and periodically write your calendar to a local cache.
+Cache-backed display modes such as 'waybar --display countdown' read from it instead of calling Microsoft Graph.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDaemon(); err != nil {
+			fmt.Printf("Daemon failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runDaemon() error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := writeDaemonPID(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	defer os.Remove(getDaemonPIDPath())
+
+	settings := loadSettings()
+
+	var joiner *autojoin.Joiner
+	if daemonAutojoin || settings.Autojoin.Enabled {
+		joiner = autojoin.NewJoiner(
+			time.Duration(settings.Autojoin.LeadSeconds)*time.Second,
+			time.Duration(settings.Autojoin.CancelSeconds)*time.Second,
+			widget.OpenMeeting,
+			notificationEvent,
+		)
+		go joiner.Run(ctx)
+	}
+
+	var webhooks *webhook.Dispatcher
+	if len(settings.Webhooks) > 0 {
+		if err := webhook.Validate(settings.Webhooks); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: webhooks disabled: %v\n", err)
+		} else {
+			webhooks = webhook.NewDispatcher(settings.Webhooks, func(err error) {
+				fmt.Fprintf(os.Stderr, "Webhook failed: %v\n", err)
+			})
+		}
+	}
+
+	var commands *hooks.Runner
+	if len(settings.Hooks) > 0 {
+		if err := hooks.Validate(settings.Hooks); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: hooks disabled: %v\n", err)
+		} else {
+			commands = hooks.NewRunner(settings.Hooks, func(err error) {
+				fmt.Fprintf(os.Stderr, "Hook failed: %v\n", err)
+			})
+		}
+	}
+
+	var watcher *alerts.Watcher
+	soundsEnabled := daemonSounds || settings.Sounds.Enabled
+	presenceEnabled := daemonPresence || settings.Presence.Enabled
+	if soundsEnabled || presenceEnabled || webhooks != nil || commands != nil {
+		var alerter *meetingAlerter
+		if soundsEnabled || presenceEnabled {
+			alerter = newMeetingAlerter(ctx, settings, soundsEnabled, presenceEnabled)
+		}
+		watcher = alerts.NewWatcher(func(transition alerts.Transition) {
+			if debug {
+				fmt.Printf("%q: %s -> %s\n", transition.Event.Subject, transition.From, transition.To)
+			}
+			if alerter != nil {
+				go alerter.alert(ctx, transition.Event, transition.To)
+			}
+			if name := webhook.ForStatus(transition.To); webhooks != nil && name != "" {
+				go webhooks.Fire(ctx, webhook.Payload{Event: name, Meeting: webhook.NewMeeting(transition.Event)})
+			}
+			if commands != nil {
+				go commands.Run(ctx, transition)
+			}
+		})
+		go watcher.Run(ctx)
+	}
+
+	var publisher *autostatus.Publisher
+	if daemonAutoStatus || settings.AutoStatus.Enabled {
+		publisher = autostatus.NewPublisher(statusSinks(settings.AutoStatus), func(err error) {
+			fmt.Fprintf(os.Stderr, "Meeting status update failed: %v\n", err)
+		})
+		go publisher.Run(ctx)
+	}
+
+	var shutdown sync.WaitGroup
+	var mqttPublisher *homeassistant.Publisher
+	if daemonMQTT || settings.MQTT.Enabled {
+		mqttPublisher = homeassistant.NewPublisher(mqttOptions(settings.MQTT), settings.MQTT.Topic, settings.MQTT.DiscoveryPrefix, func(err error) {
+			fmt.Fprintf(os.Stderr, "MQTT publish failed: %v\n", err)
+		})
+		// Waited for on exit so the sensors are marked unavailable
+		shutdown.Add(1)
+		go func() {
+			defer shutdown.Done()
+			mqttPublisher.Run(ctx)
+		}()
+	}
+
+	if settings.AutoPrivacy.Enabled {
+		go screenshare.Watch(ctx, screenShareInterval, settings.AutoPrivacy.Command, func(sharing bool) {
+			if debug {
+				fmt.Printf("Screen sharing: %v\n", sharing)
+			}
+			signalWaybar(waybarSignal)
+		})
+	}
+
+	// wake refreshes ahead of schedule when Graph reports a change; bursts
+	// of notifications queue a single refresh
+	wake := make(chan struct{}, 1)
+	if live.Enabled(settings.Live) {
+		startLiveListener(ctx, calendarService, settings.Live, wake, &shutdown)
+	}
+
+	intervals := cache.Intervals{
+		Base: time.Duration(daemonRefresh) * time.Second,
+		Min:  settingDuration("refresh.min_interval", settings.Refresh.MinInterval, 30*time.Second),
+		Max:  settingDuration("refresh.max_interval", settings.Refresh.MaxInterval, 5*time.Minute),
+	}
+
+	authRequired := false
+	var failures refreshFailures
+	for {
+		snapshot, err := refreshCache(ctx, calendarService, &failures)
+		// Network trouble says nothing about sign-in, so only a success or an
+		// auth failure changes authRequired
+		if err == nil || isAuthError(err) {
+			if err != nil && !authRequired && webhooks != nil {
+				go webhooks.Fire(ctx, webhook.Payload{Event: webhook.AuthRequired, Error: err.Error()})
+			}
+			authRequired = err != nil
+		}
+		if settings.Changes.NotifyMoved && snapshot != nil {
+			notifyMoved(snapshot.Moved)
+		}
+		if settings.Changes.NotifyAdded && snapshot != nil {
+			notifyAdded(snapshot.Added)
+		}
+		if joiner != nil && snapshot != nil {
+			joiner.Update(snapshot.UpcomingEvents)
+		}
+		if watcher != nil && snapshot != nil {
+			watcher.Update(snapshot.UpcomingEvents)
+		}
+		if publisher != nil && snapshot != nil {
+			publisher.Update(snapshot.UpcomingEvents)
+		}
+		if mqttPublisher != nil && snapshot != nil {
+			mqttPublisher.Update(snapshot.UpcomingEvents)
+		}
+
+		delay := snapshot.NextRefresh(time.Now(), intervals)
+		if debug {
+			fmt.Printf("Next refresh in %s\n", delay)
+		}
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if publisher != nil {
+				clearCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				publisher.Clear(clearCtx)
+				cancel()
+			}
+			shutdown.Wait()
+			return nil
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+			if debug {
+				fmt.Println("Calendar changed, refreshing")
+			}
+		}
+	}
+}
+
+// startLiveListener subscribes to change notifications through the relay in
+// settings, which signal wake. Scheduled refreshes continue as a fallback.
+// The listener is added to shutdown, so the daemon waits on exit until it
+// has deleted its subscription.
+func startLiveListener(ctx context.Context, provider calendar.CalendarProvider, settings config.LiveConfig, wake chan<- struct{}, shutdown *sync.WaitGroup) {
+	if err := live.Validate(settings); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: live updates disabled: %v\n", err)
+		return
+	}
+	subscriber, ok := provider.(calendar.ChangeSubscriber)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Warning: live updates disabled: they need a Microsoft Graph account")
+		return
+	}
+
+	listener, err := live.NewListener(subscriber, settings, func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}, func(err error) {
+		fmt.Fprintf(os.Stderr, "Live updates: %v\n", err)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: live updates disabled: %v\n", err)
+		return
+	}
+	shutdown.Add(1)
+	go func() {
+		defer shutdown.Done()
+		listener.Run(ctx)
+	}()
+}
+
+// statusSinks returns where the meeting status is published: always the
+// state file, and Slack when a token or webhook is configured
+func statusSinks(settings config.AutoStatusConfig) []autostatus.Sink {
+	path := settings.File
+	if path == "" {
+		path = xdg.StatePath("meeting-status.json")
+	}
+
+	sinks := []autostatus.Sink{autostatus.FileSink{Path: path}}
+	if settings.SlackToken != "" || settings.SlackWebhook != "" {
+		sinks = append(sinks, &autostatus.SlackSink{
+			Token:   settings.SlackToken,
+			Webhook: settings.SlackWebhook,
+			Emoji:   settings.SlackEmoji,
+		})
+	}
+	return sinks
+}
+
+func mqttOptions(settings config.MQTTConfig) mqtt.Options {
+	clientID := settings.ClientID
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = "calendar-widget-" + hostname
+	}
+	return mqtt.Options{
+		Broker:   settings.Broker,
+		ClientID: clientID,
+		Username: settings.Username,
+		Password: settings.Password,
+	}
+}
+
+// meetingAlerter plays alert sounds as meetings change status and, with
+// presence on, announces meeting starts according to whether the user is idle
+type meetingAlerter struct {
+	sounds        config.SoundsConfig
+	soundsEnabled bool
+	quietHours    sound.QuietHours
+	// escalation is nil when presence is off
+	escalation *alerts.Escalation
+	// push sends the first escalated start notification of each meeting
+	// to the phone
+	push   config.PushConfig
+	pushMu sync.Mutex
+	pushed map[string]bool
+}
+
+func newMeetingAlerter(ctx context.Context, settings *config.Config, soundsEnabled, presenceEnabled bool) *meetingAlerter {
+	quietHours, err := sound.ParseQuietHours(settings.Sounds.QuietHours)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	alerter := &meetingAlerter{
+		sounds:        settings.Sounds,
+		soundsEnabled: soundsEnabled,
+		quietHours:    quietHours,
+		pushed:        map[string]bool{},
+	}
+	if err := push.Validate(settings.Push); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: push notifications disabled: %v\n", err)
+	} else {
+		alerter.push = settings.Push
+	}
+	if !presenceEnabled {
+		return alerter
+	}
+
+	monitor := idle.NewMonitor(settingDuration("presence.idle_after", settings.Presence.IdleAfter, 2*time.Minute))
+	go func() {
+		if err := monitor.Run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: idle detection stopped, start notifications stay silent: %v\n", err)
+		}
+	}()
+
+	alerter.escalation = &alerts.Escalation{
+		Idle:        monitor.Idle,
+		Notify:      alerter.notifyStart,
+		Sound:       func(event calendar.Event) { alerter.play(event, calendar.StatusCurrent) },
+		Dismissed:   dismiss.Active,
+		RepeatEvery: settingDuration("presence.repeat_every", settings.Presence.RepeatEvery, time.Minute),
+		RepeatFor:   settingDuration("presence.repeat_for", settings.Presence.RepeatFor, 10*time.Minute),
+	}
+	return alerter
+}
+
+// alert handles a meeting moving to status. Nothing is announced while
+// snoozed or for meetings whose reminder was dismissed.
+func (a *meetingAlerter) alert(ctx context.Context, event calendar.Event, status string) {
+	if _, snoozed := snooze.Active(); snoozed {
+		return
+	}
+	if dismiss.Active(event) {
+		return
+	}
+
+	if status == calendar.StatusCurrent && a.escalation != nil {
+		a.escalation.Start(ctx, event)
+		return
+	}
+	if a.soundsEnabled {
+		a.play(event, status)
+	}
+}
+
+// play plays the sound configured for status, except within quiet hours
+func (a *meetingAlerter) play(event calendar.Event, status string) {
+	file := a.sounds.Files[status]
+	if file == "" || a.quietHours.Contains(time.Now()) {
+		return
+	}
+
+	if debug {
+		fmt.Printf("Playing %s for %q (%s)\n", file, event.Subject, status)
+	}
+	if err := sound.Play(a.sounds.Command, file); err != nil {
+		fmt.Fprintf(os.Stderr, "Alert sound failed: %v\n", err)
+	}
+}
+
+// notifyStart announces a meeting that has started: quietly at the
+// keyboard, as a critical notification while escalating
+func notifyStart(event calendar.Event, loud bool) {
+	event = notificationEvent(event)
+	urgency := notify.UrgencyNormal
+	if loud {
+		urgency = notify.UrgencyCritical
+	}
+	body := i18n.T("Started at %s", i18n.FormatTime(event.Start))
+	if event.Location != "" {
+		body += "\n" + event.Location
+	}
+	if err := notify.SendUrgent(event.Subject, body, urgency, !loud); err != nil {
+		fmt.Fprintf(os.Stderr, "Start notification failed: %v\n", err)
+	}
+}
+
+// notifyStart shows the start notification and, the first time a meeting
+// escalates, pushes it to the phone since the user is away from the desk
+func (a *meetingAlerter) notifyStart(event calendar.Event, loud bool) {
+	notifyStart(event, loud)
+	if !loud || !push.Enabled(a.push) {
+		return
+	}
+
+	key := event.ID + "@" + event.Start.Format(time.RFC3339)
+	a.pushMu.Lock()
+	done := a.pushed[key]
+	a.pushed[key] = true
+	a.pushMu.Unlock()
+	if done {
+		return
+	}
+
+	message := push.NewMessage(event, i18n.T("Started at %s", i18n.FormatTime(event.Start)), true)
+	if err := push.Send(context.Background(), a.push, message); err != nil {
+		fmt.Fprintf(os.Stderr, "Push notification failed: %v\n", err)
+	}
+}
+
+// notifyMoved announces meetings that got another time or location, e.g.
+// "Standup moved to 10:30", with where they were in the body. Nothing is
+// announced while snoozed or for meetings that are over.
+func notifyMoved(events []calendar.Event) {
+	if _, snoozed := snooze.Active(); snoozed {
+		return
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		if event.Change == nil || event.IsCancelled || !event.End.After(now) {
+			continue
+		}
+		event = notificationEvent(event)
+		change := event.Change
+		timeChanged := change.Rescheduled(event)
+		locationChanged := event.Location != change.PreviousLocation
+		if !timeChanged && !locationChanged {
+			// Only the location of a redacted meeting changed
+			continue
+		}
+		to := movedPlace(event.Start, event.Location, timeChanged, locationChanged, now)
+		from := movedPlace(change.PreviousStart, change.PreviousLocation, timeChanged, locationChanged, now)
+
+		if err := notify.Send(i18n.T("%s moved to %s", event.Subject, to), i18n.T("Was %s", from)); err != nil {
+			fmt.Fprintf(os.Stderr, "Change notification failed: %v\n", err)
+		}
+	}
+}
+
+// newMeetingWithin is how soon a meeting that shows up on the calendar must
+// start for notifyAdded to announce it
+const newMeetingWithin = 24 * time.Hour
+
+// notifyAdded announces meetings that appeared on the calendar and start
+// within newMeetingWithin, e.g. "New meeting added: Incident review".
+// Meetings you organize yourself aren't announced, and nothing is while
+// snoozed.
+func notifyAdded(events []calendar.Event) {
+	if _, snoozed := snooze.Active(); snoozed {
+		return
+	}
+
+	now := time.Now()
+	for _, event := range events {
+		if event.IsCancelled || event.ResponseStatus == "organizer" || !event.End.After(now) || event.Start.Sub(now) > newMeetingWithin {
+			continue
+		}
+
+		event = notificationEvent(event)
+		body := i18n.T("Starts at %s", meetingWhen(event.Start, now))
+		if event.Location != "" {
+			body += "\n" + event.Location
+		}
+		if err := notify.Send(i18n.T("New meeting added: %s", event.Subject), body); err != nil {
+			fmt.Fprintf(os.Stderr, "New meeting notification failed: %v\n", err)
+		}
+	}
+}
+
+// movedPlace describes where a meeting is in terms of what changed: its
+// start time, its location, or both
+func movedPlace(start time.Time, location string, timeChanged, locationChanged bool, now time.Time) string {
+	var parts []string
+	if timeChanged {
+		parts = append(parts, meetingWhen(start, now))
+	}
+	if locationChanged {
+		if location == "" {
+			location = i18n.T("no location")
+		}
+		parts = append(parts, location)
+	}
+	return strings.Join(parts, " @ ")
+}
+
+// meetingWhen formats a start time for notifications, with the day unless
+// it is today
+func meetingWhen(start, now time.Time) string {
+	when := i18n.FormatTime(start)
+	if start.Format("2006-01-02") != now.Format("2006-01-02") {
+		when = i18n.FormatDate(start) + " " + when
+	}
+	return when
+}
+
+// settingDuration parses a duration setting, warning and falling back to
+// the default when it is missing or invalid
+func settingDuration(name, value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		if value != "" {
+			fmt.Fprintf(os.Stderr, "Warning: invalid %s %q, using %s\n", name, value, fallback)
+		}
+		return fallback
+	}
+	return d
+}
+
+// refreshFailureLogEvery is how many failed refreshes in a row are logged
+// as one line after the first, so an outage doesn't flood the journal
+const refreshFailureLogEvery = 10
+
+// refreshFailures counts failed refreshes in a row for logging
+type refreshFailures struct {
+	count int
+}
+
+func (f *refreshFailures) failed(err error) {
+	f.count++
+	switch {
+	case f.count == 1:
+		fmt.Fprintf(os.Stderr, "Refresh failed: %v\n", err)
+	case f.count%refreshFailureLogEvery == 0:
+		fmt.Fprintf(os.Stderr, "Refresh failed %d times in a row, last: %v\n", f.count, err)
+	}
+}
+
+func (f *refreshFailures) succeeded() {
+	if f.count > 1 {
+		fmt.Fprintf(os.Stderr, "Refresh recovered after %d failures\n", f.count)
+	}
+	f.count = 0
+}
+
+func refreshCache(ctx context.Context, calendarService calendar.CalendarProvider, failures *refreshFailures) (*cache.Snapshot, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	snapshot, err := cache.Refresh(fetchCtx, calendarService)
+	if err != nil {
+		failures.failed(err)
+		// The tooltip explains the stale data from the next render on
+		if _, recordErr := cache.RecordFailure(calendar.ErrorSummary(err), time.Now()); recordErr == nil {
+			signalWaybar(waybarSignal)
+		}
+		return nil, err
+	}
+	failures.succeeded()
+
+	if debug {
+		fmt.Printf("Cached %d upcoming events at %s\n", len(snapshot.UpcomingEvents), snapshot.UpdatedAt.Format(time.RFC3339))
+	}
+
+	signalWaybar(waybarSignal)
+
+	return snapshot, nil
+}
+
+func init() {
+	daemonCmd.Flags().IntVar(&daemonRefresh, "refresh", 60, "refresh interval in seconds while refreshes fail; otherwise it adapts between refresh.min_interval and refresh.max_interval")
+	daemonCmd.Flags().IntVar(&waybarSignal, "signal", 0, "signal waybar with RTMIN+N after each refresh (0 to disable)")
+	daemonCmd.Flags().BoolVar(&daemonAutojoin, "autojoin", false, "automatically open join links before meetings start")
+	daemonCmd.Flags().BoolVar(&daemonSounds, "sounds", false, "play alert sounds as meetings turn urgent and start")
+	daemonCmd.Flags().BoolVar(&daemonAutoStatus, "auto-status", false, "publish \"In a meeting until ...\" to Slack and a status file")
+	daemonCmd.Flags().BoolVar(&daemonMQTT, "mqtt", false, "publish the next meeting to MQTT with Home Assistant discovery")
+	daemonCmd.Flags().BoolVar(&daemonPresence, "presence", false, "announce meeting starts louder and repeatedly while you are idle")
+	addLookaheadFlag(daemonCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
+
+// signalWaybar asks waybar to re-run modules listening on RTMIN+signal so new
+// data shows immediately. A signal of 0 disables it.
+func signalWaybar(signal int) {
+	if signal <= 0 {
+		return
+	}
+	_ = exec.Command("pkill", fmt.Sprintf("-RTMIN+%d", signal), "waybar").Run()
+}
+
+func getDaemonPIDPath() string {
+	return xdg.StatePath("daemon.pid")
+}
+
+// writeDaemonPID records the daemon's PID so `status` can check it is alive
+func writeDaemonPID() error {
+	if err := os.WriteFile(getDaemonPIDPath(), []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return nil
+}
+
+// daemonPID returns the PID of a running daemon, or 0 if none is running
+func daemonPID() int {
+	data, err := os.ReadFile(getDaemonPIDPath())
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+
+	if !processAlive(pid) {
+		return 0
+	}
+	return pid
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/selection"
 	"context"
 	"fmt"
 	"os"
@@ -10,10 +11,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var debugTrace bool
+
 var debugCmd = &cobra.Command{
 	Use:   "debug",
 	Short: "Debug calendar access",
-	Long:  `Debug command to test calendar access and show detailed information.`,
+	Long: `Debug command to test calendar access and show detailed information.
+With --trace, every Graph request is printed with its status, latency and item count.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runDebug(); err != nil {
 			fmt.Printf("Debug failed: %v\n", err)
@@ -26,7 +30,11 @@ func runDebug() error {
 	fmt.Println("🔍 Debug Calendar Access")
 	fmt.Println("========================")
 
-	calendarService, err := calendar.NewCalendarService()
+	if debugTrace {
+		calendar.SetTrace(os.Stdout)
+	}
+
+	calendarService, err := newCalendarProvider(true, false)
 	if err != nil {
 		return fmt.Errorf("failed to create calendar service: %w", err)
 	}
@@ -34,8 +42,8 @@ func runDebug() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	fmt.Printf("📅 Current time: %s\n", time.Now().Format(time.RFC3339))
-	fmt.Printf("🌍 Timezone: %s\n", time.Now().Location())
+	fmt.Printf("📅 Current time: %s\n", calendar.Now().Format(time.RFC3339))
+	fmt.Printf("🌍 Timezone: %s\n", calendar.Now().Location())
 	fmt.Println()
 
 	fmt.Println("📋 Getting today's events...")
@@ -86,7 +94,7 @@ func runDebug() error {
 		fmt.Printf("  📊 Status: %s\n", event.GetStatus())
 
 		// Show only upcoming events to reduce noise
-		if event.GetStatus() != "past" {
+		if event.GetStatus() != calendar.StatusPast {
 			fmt.Printf("  ⏰ Time until: %v\n", event.GetTimeUntil())
 		}
 		fmt.Println()
@@ -99,7 +107,7 @@ func runDebug() error {
 	}
 
 	fmt.Println("🔍 Getting next meeting...")
-	nextMeeting, err := calendarService.GetNextMeeting(ctx)
+	nextMeeting, err := calendarService.GetNextMeeting(ctx, selection.Meetings)
 	if err != nil {
 		return fmt.Errorf("failed to get next meeting: %w", err)
 	}
@@ -120,5 +128,8 @@ func runDebug() error {
 }
 
 func init() {
+	debugCmd.Flags().BoolVar(&debugTrace, "trace", false, "print each Graph request URL, status, latency and item count")
+	addAtFlag(debugCmd)
+	addLookaheadFlag(debugCmd)
 	rootCmd.AddCommand(debugCmd)
 }
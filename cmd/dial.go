@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var dialPrint bool
+
+var dialCmd = &cobra.Command{
+	Use:   "dial",
+	Short: "Call into the current or next meeting by phone",
+	Long: `Open a tel: URI for the dial-in number of the current or next Teams or Zoom meeting, with the
+conference ID (and Zoom passcode) entered after a pause. Use --print to output the URI instead,
+e.g. to send it to a phone with KDE Connect.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDial(); err != nil {
+			fmt.Fprintf(os.Stderr, "Dial failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runDial() error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+	if event.DialIn == nil {
+		return fmt.Errorf("%s has no dial-in number", event.Subject)
+	}
+
+	uri := event.DialIn.TelURI()
+	if dialPrint {
+		fmt.Println(uri)
+		return nil
+	}
+
+	return widget.OpenURL(uri)
+}
+
+func init() {
+	dialCmd.Flags().BoolVar(&dialPrint, "print", false, "print the tel: URI instead of opening it")
+	rootCmd.AddCommand(dialCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/notify"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestPrint   bool
+	digestWorkday string
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize today's meetings in one notification",
+	Long: `Send a morning notification summarizing today's blocking meetings: how many, when the
+first starts and the last ends, total time in meetings and the largest free block within --workday.
+Meant to run once a day from a timer, see 'install-service --digest'. Use --print to print the
+summary in the terminal instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDigest(); err != nil {
+			fmt.Fprintf(os.Stderr, "Digest failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runDigest() error {
+	now := calendar.Now()
+	from, until, err := parseWorkday(digestWorkday, now)
+	if err != nil {
+		return err
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events, err := calendarService.GetTodaysEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	title, body := widget.RenderDigest(calendar.Summarize(events, from, until))
+	if digestPrint {
+		fmt.Println(title)
+		if body != "" {
+			fmt.Println(body)
+		}
+		return nil
+	}
+	return notify.Send(title, body)
+}
+
+// parseWorkday turns a range such as "09:00-17:00" into times on day
+func parseWorkday(value string, day time.Time) (time.Time, time.Time, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid workday %q, expected e.g. 09:00-17:00", value)
+	}
+
+	var bounds [2]time.Time
+	for i, part := range []string{from, to} {
+		clock, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid workday %q: %w", value, err)
+		}
+		bounds[i] = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, day.Location())
+	}
+	if !bounds[1].After(bounds[0]) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid workday %q: it must end after it starts", value)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+func init() {
+	digestCmd.Flags().BoolVar(&digestPrint, "print", false, "print the summary instead of sending a notification")
+	digestCmd.Flags().StringVar(&digestWorkday, "workday", "09:00-17:00", "working hours searched for the largest free block")
+	addAtFlag(digestCmd)
+	rootCmd.AddCommand(digestCmd)
+}
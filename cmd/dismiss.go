@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/dismiss"
+	"calendar-widget/internal/selection"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var dismissLocal bool
+
+var dismissCmd = &cobra.Command{
+	Use:   "dismiss",
+	Short: "Dismiss the current or next meeting's reminder",
+	Long: `Stop notifications and alert sounds for the current or next meeting. The dismissal is kept in
+~/.local/state/calendar-widget/dismissed.json for the notify timer and the daemon, and the reminder is also
+dismissed in Outlook so other devices stop reminding. Use --local to skip Outlook.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDismiss(); err != nil {
+			fmt.Fprintf(os.Stderr, "Dismiss failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runDismiss() error {
+	// Dismissing in Outlook needs Calendars.ReadWrite; sign-in asks for
+	// consent the first time
+	if !dismissLocal {
+		auth.AddScope(calendar.WriteScope)
+	}
+	calendarService, err := newCalendarProvider(!dismissLocal, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+
+	if err := dismiss.Add(*event); err != nil {
+		return err
+	}
+	fmt.Printf("🔕 Dismissed reminder for %s\n", event.Subject)
+
+	if dismissLocal {
+		return nil
+	}
+	dismisser, ok := calendarService.(calendar.ReminderDismisser)
+	if !ok {
+		return nil
+	}
+	// The local dismissal already stops our own reminders, so Outlook
+	// failing is only worth a warning
+	if err := dismisser.DismissReminder(ctx, event.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		if strings.Contains(strings.ToLower(err.Error()), "access") {
+			fmt.Fprintln(os.Stderr, "Hint: run 'calendar-widget reauth' to grant calendar write access, or use --local")
+		}
+	}
+	return nil
+}
+
+func init() {
+	dismissCmd.Flags().BoolVar(&dismissLocal, "local", false, "only dismiss locally, not in Outlook")
+	rootCmd.AddCommand(dismissCmd)
+}
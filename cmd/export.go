@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"calendar-widget/internal/ics"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDays int
+	exportOut  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export events to an iCalendar (.ics) file",
+	Long: `Export the events of the next --days (starting today) as an iCalendar file.
+Recurring meetings are written once with their recurrence rule. Writes to stdout unless --out is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runExport(); err != nil {
+			fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runExport() error {
+	calendarService, err := newCalendarService(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := calendarService.GetEventsBetween(ctx, start, start.AddDate(0, 0, exportDays))
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+	events = calendarService.CollapseRecurring(ctx, events)
+
+	if exportOut == "" || exportOut == "-" {
+		return ics.Write(os.Stdout, events)
+	}
+
+	file, err := os.Create(exportOut)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", exportOut, err)
+	}
+	defer file.Close()
+
+	if err := ics.Write(file, events); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d events to %s\n", len(events), exportOut)
+	return nil
+}
+
+func init() {
+	exportCmd.Flags().IntVar(&exportDays, "days", 7, "number of days to export, starting today")
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "output file (default stdout)")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// slotStep is the boundary proposed slots start on, so meetings are
+// suggested at :00, :15, :30 and :45
+const slotStep = 15 * time.Minute
+
+var (
+	findSlotWith     []string
+	findSlotDuration time.Duration
+	findSlotWorkday  string
+	findSlotDays     int
+	findSlotCount    int
+	findSlotJSON     bool
+)
+
+var findSlotCmd = &cobra.Command{
+	Use:   "find-slot",
+	Short: "Propose times when you and the given attendees are all free",
+	Long: `Read the free/busy of the attendees with getSchedule, combine it with your own calendar and propose
+the earliest slots of --duration within --workday on weekdays when everyone is free. By default the rest of
+this week is searched (next week from the weekend on); --days searches that many days from now instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runFindSlot(); err != nil {
+			fmt.Fprintf(os.Stderr, "Find slot failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// proposedSlot is the JSON shape printed by `find-slot --json`
+type proposedSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// FreeUntil is when the common free time around the slot ends
+	FreeUntil time.Time `json:"free_until"`
+}
+
+func runFindSlot() error {
+	if findSlotDuration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	var attendees []string
+	for _, attendee := range findSlotWith {
+		if attendee = strings.TrimSpace(attendee); attendee != "" {
+			attendees = append(attendees, attendee)
+		}
+	}
+	if len(attendees) == 0 {
+		return fmt.Errorf("--with needs at least one email address")
+	}
+
+	now := calendar.Now()
+	until := searchWeekEnd(now)
+	if findSlotDays > 0 {
+		until = now.AddDate(0, 0, findSlotDays)
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+	reader, ok := calendarService.(calendar.ScheduleReader)
+	if !ok {
+		return fmt.Errorf("this calendar provider can't read other people's free/busy")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	myEvents, err := calendarService.GetEventsBetween(ctx, now, until)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+	schedules, err := reader.GetSchedules(ctx, attendees, now, until)
+	if err != nil {
+		return err
+	}
+
+	busy := calendar.BusyPeriods(myEvents)
+	for _, schedule := range schedules {
+		if schedule.Error != "" {
+			fmt.Fprintf(os.Stderr, "Warning: no free/busy for %s: %s\n", schedule.Email, schedule.Error)
+			continue
+		}
+		busy = append(busy, schedule.Busy...)
+	}
+
+	var proposals []proposedSlot
+	for day := now; day.Before(until) && len(proposals) < findSlotCount; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		from, to, err := parseWorkday(findSlotWorkday, day)
+		if err != nil {
+			return err
+		}
+		from = latest(from, now)
+		to = earliest(to, until)
+
+		for _, gap := range calendar.FreeSlots(busy, from, to, findSlotDuration) {
+			start := roundUp(gap.Start, slotStep)
+			if start.Add(findSlotDuration).After(gap.End) {
+				continue
+			}
+			proposals = append(proposals, proposedSlot{Start: start, End: start.Add(findSlotDuration), FreeUntil: gap.End})
+			if len(proposals) == findSlotCount {
+				break
+			}
+		}
+	}
+
+	if findSlotJSON {
+		if proposals == nil {
+			proposals = []proposedSlot{}
+		}
+		data, err := json.MarshalIndent(proposals, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal slots: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(proposals) == 0 {
+		fmt.Println(i18n.T("No common free time found"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, slot := range proposals {
+		fmt.Fprintf(w, "%s\t%s-%s\t%s\n",
+			i18n.FormatDate(slot.Start),
+			i18n.FormatTime(slot.Start),
+			i18n.FormatTime(slot.End),
+			i18n.T("free until %s", i18n.FormatTime(slot.FreeUntil)))
+	}
+	return w.Flush()
+}
+
+// searchWeekEnd is the end of the working week to search from now: the
+// coming Saturday, which on a weekend is the end of next week
+func searchWeekEnd(now time.Time) time.Time {
+	daysToSaturday := (int(time.Saturday) - int(now.Weekday()) + 7) % 7
+	if daysToSaturday == 0 {
+		daysToSaturday = 7
+	}
+	saturday := now.AddDate(0, 0, daysToSaturday)
+	return time.Date(saturday.Year(), saturday.Month(), saturday.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// roundUp rounds t up to the next multiple of step on the local clock
+func roundUp(t time.Time, step time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	steps := (t.Sub(midnight) + step - 1) / step
+	return midnight.Add(steps * step)
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func init() {
+	findSlotCmd.Flags().StringSliceVar(&findSlotWith, "with", nil, "attendee email addresses, comma separated")
+	findSlotCmd.Flags().DurationVar(&findSlotDuration, "duration", 30*time.Minute, "meeting length")
+	findSlotCmd.Flags().StringVar(&findSlotWorkday, "workday", "09:00-17:00", "working hours to search")
+	findSlotCmd.Flags().IntVar(&findSlotDays, "days", 0, "days ahead to search (default the rest of this week)")
+	findSlotCmd.Flags().IntVar(&findSlotCount, "count", 5, "number of slots to propose")
+	findSlotCmd.Flags().BoolVar(&findSlotJSON, "json", false, "print the slots as JSON")
+	findSlotCmd.MarkFlagRequired("with")
+	rootCmd.AddCommand(findSlotCmd)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var focusTitle string
+
+var focusCmd = &cobra.Command{
+	Use:   "focus [duration]",
+	Short: "Block the next stretch of time as focus time",
+	Long: `Create a private, busy "Focus time" event starting now (default 30m) and refresh the bar
+so it shows up immediately.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runFocus(args); err != nil {
+			fmt.Printf("Focus failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runFocus(args []string) error {
+	duration := 30 * time.Minute
+	if len(args) == 1 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q", args[0])
+		}
+		duration = d
+	}
+
+	// Writing needs Calendars.ReadWrite; sign-in asks for consent the first time
+	auth.AddScope(calendar.WriteScope)
+	calendarService, err := newCalendarService(true, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	event, err := calendarService.CreateEvent(ctx, calendar.NewEvent{
+		Subject: focusTitle,
+		Start:   now,
+		End:     now.Add(duration),
+		Private: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🎯 %s until %s\n", event.Subject, i18n.FormatTime(event.End))
+
+	// Refresh the cache so cache-backed modes pick up the new event, then redraw
+	if _, err := cache.Refresh(ctx, calendarService); err != nil {
+		fmt.Printf("Warning: failed to refresh event cache: %v\n", err)
+	}
+	signalWaybar(loadSettings().Click.Signal)
+
+	return nil
+}
+
+func init() {
+	focusCmd.Flags().StringVar(&focusTitle, "title", "Focus time", "event subject")
+	rootCmd.AddCommand(focusCmd)
+}
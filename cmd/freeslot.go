@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"calendar-widget/internal/i18n"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var minFreeSlot time.Duration
+
+var freeslotCmd = &cobra.Command{
+	Use:   "freeslot",
+	Short: "Show the next free slot in today's schedule",
+	Long:  `Find the next gap between today's blocking meetings that is at least --min long.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runFreeSlot(); err != nil {
+			fmt.Printf("Free slot lookup failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runFreeSlot() error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	slot, err := calendarService.FindNextFreeSlot(ctx, minFreeSlot)
+	if err != nil {
+		return fmt.Errorf("failed to find free slot: %w", err)
+	}
+
+	if slot == nil {
+		fmt.Println("No free time left today")
+		return nil
+	}
+
+	if slot.IsNow() {
+		fmt.Printf("Free until %s (%s)\n", i18n.FormatTime(slot.End), slot.GetDuration().Round(time.Minute))
+	} else {
+		fmt.Printf("Next free: %s–%s (%s)\n", i18n.FormatTime(slot.Start), i18n.FormatTime(slot.End), slot.GetDuration().Round(time.Minute))
+	}
+
+	return nil
+}
+
+func init() {
+	freeslotCmd.Flags().DurationVar(&minFreeSlot, "min", 15*time.Minute, "minimum length of a free slot")
+	rootCmd.AddCommand(freeslotCmd)
+}
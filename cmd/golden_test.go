@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"bytes"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/widget"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// goldenCase is one rendering checked for every fixture
+type goldenCase struct {
+	name        string
+	display     string
+	style       string
+	private     bool
+	singleClass bool
+	// showCancelled keeps cancelled meetings instead of dropping them
+	showCancelled bool
+}
+
+var goldenCases = []goldenCase{
+	{name: "waybar --display next", display: widget.DisplayNext, style: config.TooltipList},
+	{name: "waybar --display next (table tooltip)", display: widget.DisplayNext, style: config.TooltipTable},
+	{name: "waybar --display remaining", display: widget.DisplayRemaining, style: config.TooltipList},
+	{name: "waybar --display freeslot", display: widget.DisplayFreeSlot, style: config.TooltipList},
+	{name: "waybar --display multi", display: widget.DisplayMulti, style: config.TooltipList},
+	{name: "waybar --display count", display: widget.DisplayCount, style: config.TooltipList},
+	{name: "waybar --display next --private", display: widget.DisplayNext, style: config.TooltipList, private: true},
+	{name: "waybar --display next --single-class", display: widget.DisplayNext, style: config.TooltipList, singleClass: true},
+	{name: "waybar --display next (cancelled shown)", display: widget.DisplayNext, style: config.TooltipTable, showCancelled: true},
+}
+
+// goldenSelections are the selection presets whose pick is listed for every fixture
+var goldenSelections = []struct {
+	name    string
+	options selection.Options
+}{
+	{name: "display", options: selection.Display},
+	{name: "meetings", options: selection.Meetings},
+	{name: "display, declined included", options: selection.Options{IncludeNonBlocking: true, IncludeDeclined: true}},
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files from the current output")
+
+// goldenDir holds the fixtures, relative to the cmd package
+var goldenDir = filepath.Join("..", "testdata", "golden")
+
+// TestGolden renders every fixture at the fixture's "now" and compares the
+// waybar JSON and tooltip with the matching .golden file. Recorded Graph
+// calendarView responses in graph/ are converted for their account type and
+// compared the same way. Run with -update after an intended output change
+// and review the diff.
+func TestGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	recordings, err := filepath.Glob(filepath.Join(goldenDir, "graph", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list recorded responses: %v", err)
+	}
+	fixtures = append(fixtures, recordings...)
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures in %s", goldenDir)
+	}
+
+	freezeRendering(t)
+
+	for _, fixturePath := range fixtures {
+		name, _ := filepath.Rel(goldenDir, fixturePath)
+		t.Run(strings.TrimSuffix(name, ".json"), func(t *testing.T) {
+			renderFixture := renderGolden
+			if filepath.Base(filepath.Dir(fixturePath)) == "graph" {
+				renderFixture = renderGraphGolden
+			}
+			rendered, err := renderFixture(fixturePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPath := strings.TrimSuffix(fixturePath, ".json") + ".golden"
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, rendered, 0644); err != nil {
+					t.Fatalf("failed to write golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if line, ok := firstDifference(want, rendered); !ok {
+				t.Errorf("%s differs\n%s", goldenPath, line)
+			}
+		})
+	}
+}
+
+// freezeRendering pins everything that output depends on besides the
+// fixture itself, so golden files don't change with the machine they run on.
+// The other settings are pinned to their package defaults; the ones changed
+// from them are put back when the test ends, so later tests in the package
+// start from the same state.
+func freezeRendering(t *testing.T) {
+	local, renderer, lookaheadDays := time.Local, lipgloss.DefaultRenderer(), calendar.LookaheadDays()
+	locale, timeFormat := i18n.Current().Tag, i18n.TimeFormat()
+	t.Cleanup(func() {
+		time.Local = local
+		lipgloss.SetDefaultRenderer(renderer)
+		i18n.SetLocale(locale)
+		i18n.SetTimeFormat(timeFormat)
+		calendar.SetLookaheadDays(lookaheadDays)
+	})
+
+	time.Local = time.UTC
+	// A renderer without a terminal and NO_COLOR set renders plain text
+	t.Setenv("NO_COLOR", "1")
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(io.Discard))
+	i18n.SetLocale("en-US")
+	i18n.SetTimeFormat("24h")
+	render.SetIcons(nil)
+	render.SetPango(false)
+	widget.SetSingleClass(false)
+	widget.SetPrivacy(false)
+	render.SetShowAttendees(false)
+	widget.SetTruncation(0, widget.DefaultEllipsis)
+	widget.SetTheme(config.ThemeConfig{})
+	widget.SetWaybarLayout("")
+	widget.SetClassRules(nil)
+	widget.SetPercentageHorizon(0)
+	calendar.SetBlockingShowAs(nil)
+	calendar.SetCalendarReminders(false)
+	calendar.SetShowCancelled(false)
+	calendar.SetTravel(0, "")
+	calendar.SetLookaheadDays(0)
+}
+
+// renderGolden renders all golden cases for one fixture
+func renderGolden(fixturePath string) ([]byte, error) {
+	fixture, err := calendar.ReadFixture(fixturePath)
+	if err != nil {
+		return nil, err
+	}
+	if fixture.Now.IsZero() {
+		return nil, fmt.Errorf("%s: golden fixtures must set \"now\"", fixturePath)
+	}
+
+	calendar.SetClock(calendar.FixedClock(fixture.Now.Local()))
+	defer calendar.SetClock(nil)
+
+	provider := calendar.NewFakeProvider(fixture.Events)
+	ctx := context.Background()
+	todaysEvents, _ := provider.GetTodaysEvents(ctx)
+	upcomingEvents, _ := provider.GetUpcomingEvents(ctx)
+
+	var out bytes.Buffer
+	for _, c := range goldenCases {
+		settings := config.Default()
+		settings.Tooltip.Style = c.style
+		widget.SetPrivacy(c.private)
+		widget.SetSingleClass(c.singleClass)
+
+		today, upcoming := todaysEvents, upcomingEvents
+		if c.showCancelled {
+			calendar.SetShowCancelled(true)
+			today, _ = provider.GetTodaysEvents(ctx)
+			upcoming, _ = provider.GetUpcomingEvents(ctx)
+			calendar.SetShowCancelled(false)
+		}
+
+		output := widget.RenderWaybar(&widget.Config{
+			Display:     c.display,
+			MinFreeSlot: 15 * time.Minute,
+			Settings:    settings,
+		}, today, upcoming)
+
+		fmt.Fprintf(&out, "== %s ==\n", c.name)
+		encoder := json.NewEncoder(&out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return nil, fmt.Errorf("failed to encode waybar output: %w", err)
+		}
+		fmt.Fprintf(&out, "-- tooltip --\n%s\n\n", output.Tooltip)
+	}
+
+	widget.SetPrivacy(false)
+	widget.SetSingleClass(false)
+	fmt.Fprintf(&out, "== waybar --display chips ==\n")
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(widget.RenderChips(upcomingEvents, widget.DefaultChipsWithin)); err != nil {
+		return nil, fmt.Errorf("failed to encode chips: %w", err)
+	}
+
+	monthStart, nextMonth := widget.MonthRange(calendar.Now())
+	monthEvents, _ := provider.GetEventsBetween(ctx, monthStart, nextMonth)
+	date := widget.RenderDateModule(monthEvents, calendar.Now())
+	fmt.Fprintf(&out, "\n== waybar --module date ==\n%s\n%s\n", date.Text, date.Tooltip)
+
+	fmt.Fprintf(&out, "\n== selection ==\n")
+	for _, preset := range goldenSelections {
+		picked := "(none)"
+		if event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), preset.options); event != nil {
+			picked = event.Subject
+		}
+		fmt.Fprintf(&out, "%s: %s\n", preset.name, picked)
+	}
+	fmt.Fprintf(&out, "\n== tooltip ==\n%s\n", widget.RenderTooltip(todaysEvents, upcomingEvents))
+
+	from, until, err := parseWorkday("09:00-17:00", calendar.Now())
+	if err != nil {
+		return nil, err
+	}
+	title, body := widget.RenderDigest(calendar.Summarize(todaysEvents, from, until))
+	fmt.Fprintf(&out, "\n== digest ==\n%s\n%s\n", title, body)
+	return out.Bytes(), nil
+}
+
+// graphRecording is a calendarView response recorded from an account
+type graphRecording struct {
+	AccountType string          `json:"account_type"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// renderGraphGolden converts a recorded Graph response and lists the fields
+// the widget relies on, so account type quirks are pinned down
+func renderGraphGolden(recordingPath string) ([]byte, error) {
+	data, err := os.ReadFile(recordingPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded response: %w", err)
+	}
+
+	var recording graphRecording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("%s: failed to parse recording: %w", recordingPath, err)
+	}
+
+	events, err := calendar.ParseCalendarView(recording.Response, recording.AccountType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", recordingPath, err)
+	}
+
+	var out bytes.Buffer
+	for _, event := range events {
+		fmt.Fprintf(&out, "== %s ==\n", event.Subject)
+		fmt.Fprintf(&out, "start:     %s\n", event.Start.Format(time.RFC3339))
+		fmt.Fprintf(&out, "end:       %s\n", event.End.Format(time.RFC3339))
+		fmt.Fprintf(&out, "all day:   %v\n", event.IsAllDay)
+		fmt.Fprintf(&out, "type:      %s\n", event.Type)
+		fmt.Fprintf(&out, "show as:   %s\n", event.ShowAs)
+		fmt.Fprintf(&out, "response:  %s\n", event.ResponseStatus)
+		fmt.Fprintf(&out, "private:   %v\n", event.IsPrivate())
+		fmt.Fprintf(&out, "organizer: %s\n", event.Organizer)
+		fmt.Fprintf(&out, "location:  %s\n", event.Location)
+		fmt.Fprintf(&out, "teams:     %v\n", event.IsTeams)
+		fmt.Fprintf(&out, "join:      %s\n\n", event.GetJoinLink())
+	}
+	return out.Bytes(), nil
+}
+
+// firstDifference compares two renderings line by line and describes the
+// first line that differs
+func firstDifference(want, got []byte) (string, bool) {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("  line %d\n  want: %q\n  got:  %q", i+1, w, g), false
+		}
+	}
+	return "", true
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listJSON  bool
+	listToday bool
+	listDays  int
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List upcoming events",
+	Long: `List events from now until --days ahead (or today's events with --today).
+Use --json for machine-readable output in scripts and menus.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runList(); err != nil {
+			fmt.Fprintf(os.Stderr, "List failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// listedEvent is the stable JSON shape printed by `list --json`
+type listedEvent struct {
+	ID         string    `json:"id"`
+	Subject    string    `json:"subject"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	AllDay     bool      `json:"all_day"`
+	Location   string    `json:"location,omitempty"`
+	Organizer  string    `json:"organizer,omitempty"`
+	Attendees  []string  `json:"attendees,omitempty"`
+	Response   string    `json:"response,omitempty"`
+	Categories []string  `json:"categories,omitempty"`
+	ShowAs     string    `json:"show_as,omitempty"`
+	Blocking   bool      `json:"blocking"`
+	Status     string    `json:"status"`
+	Provider   string    `json:"provider,omitempty"`
+	JoinLink   string    `json:"join_link,omitempty"`
+	WebLink    string    `json:"web_link,omitempty"`
+	// DialIn is the audio conferencing number, ID and passcode
+	DialIn *calendar.DialIn `json:"dial_in,omitempty"`
+	// Tenant is the guest tenant the event comes from, if any
+	Tenant string `json:"tenant,omitempty"`
+	// Group is the group calendar the event comes from, if any
+	Group string `json:"group,omitempty"`
+}
+
+func newListedEvent(event calendar.Event) listedEvent {
+	return listedEvent{
+		ID:         event.ID,
+		Subject:    event.Subject,
+		Start:      event.Start,
+		End:        event.End,
+		AllDay:     event.IsAllDay,
+		Location:   event.Location,
+		Organizer:  event.Organizer,
+		Attendees:  event.Attendees,
+		Response:   event.ResponseStatus,
+		Categories: event.Categories,
+		ShowAs:     event.ShowAs,
+		Blocking:   event.IsBlockingEvent(),
+		Status:     event.GetStatus(),
+		Provider:   event.GetProvider(),
+		JoinLink:   event.GetJoinLink(),
+		WebLink:    event.WebLink,
+		DialIn:     event.DialIn,
+		Tenant:     event.Tenant,
+		Group:      event.Group,
+	}
+}
+
+func runList() error {
+	events, err := fetchListEvents()
+	if err != nil {
+		return err
+	}
+
+	if listJSON {
+		listed := make([]listedEvent, 0, len(events))
+		for _, event := range events {
+			listed = append(listed, newListedEvent(event))
+		}
+
+		data, err := json.MarshalIndent(listed, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Println(i18n.T("No upcoming meetings"))
+		return nil
+	}
+
+	for _, event := range events {
+		fmt.Printf("%s %s-%s  %s\n",
+			i18n.FormatDate(event.Start),
+			i18n.FormatTime(event.Start),
+			i18n.FormatTime(event.End),
+			event.Subject)
+	}
+
+	return nil
+}
+
+// fetchListEvents returns today's events or the events in the next --days
+func fetchListEvents() ([]calendar.Event, error) {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var events []calendar.Event
+	if listToday {
+		events, err = calendarService.GetTodaysEvents(ctx)
+	} else {
+		now := calendar.Now()
+		events, err = calendarService.GetEventsBetween(ctx, now, now.AddDate(0, 0, listDays))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return events, nil
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "print events as JSON")
+	listCmd.Flags().BoolVar(&listToday, "today", false, "list today's events only")
+	listCmd.Flags().IntVar(&listDays, "days", 7, "number of days ahead to list")
+	listCmd.MarkFlagsMutuallyExclusive("today", "days")
+	addAtFlag(listCmd)
+	rootCmd.AddCommand(listCmd)
+}
@@ -23,10 +23,9 @@ var logoutCmd = &cobra.Command{
 func runLogout() error {
 	fmt.Println("Logging out...")
 
-	// Remove token file
-	tokenPath := auth.GetTokenPath()
-	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove token file: %w", err)
+	// Remove token files
+	if err := auth.ClearTokens(); err != nil {
+		return err
 	}
 
 	// Remove config file
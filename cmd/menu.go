@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	menuDmenu    bool
+	menuLauncher string
+	menuDays     int
+)
+
+var menuCmd = &cobra.Command{
+	Use:   "menu [selected line]",
+	Short: "Pick a meeting to join from rofi or wofi",
+	Long: `Print one line per upcoming meeting for a launcher menu. Given a selected line back
+as the argument it opens that meeting, so it works as a rofi script mode:
+
+  rofi -show meetings -modi "meetings:calendar-widget menu"
+
+With --dmenu the launcher is spawned directly (default "rofi -dmenu -i -p meetings"),
+which suits a keybinding, e.g. --launcher "wofi --dmenu".`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runMenu(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Menu failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runMenu(args []string) error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := calendar.Now()
+	events, err := calendarService.GetEventsBetween(ctx, now, now.AddDate(0, 0, menuDays))
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = menuLine(event)
+	}
+
+	var selected string
+	switch {
+	case len(args) == 1:
+		selected = args[0]
+	case menuDmenu:
+		selected, err = runLauncher(menuLauncher, lines)
+		if err != nil {
+			return err
+		}
+	default:
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	if selected == "" {
+		return nil
+	}
+
+	for i, line := range lines {
+		if line == selected {
+			return widget.OpenMeeting(events[i])
+		}
+	}
+
+	return fmt.Errorf("no meeting matches %q", selected)
+}
+
+// menuLine renders an event as a single launcher entry
+func menuLine(event calendar.Event) string {
+	subject := strings.Join(strings.Fields(event.Subject), " ")
+
+	line := fmt.Sprintf("%s %s-%s  %s",
+		i18n.FormatDate(event.Start),
+		i18n.FormatTime(event.Start),
+		i18n.FormatTime(event.End),
+		subject)
+
+	switch event.GetProvider() {
+	case "teams":
+		line += " (Teams)"
+	case "zoom":
+		line += " (Zoom)"
+	}
+
+	return line
+}
+
+// runLauncher pipes lines into a dmenu-style launcher and returns the chosen
+// line. Dismissing the launcher returns an empty selection.
+func runLauncher(launcher string, lines []string) (string, error) {
+	fields := strings.Fields(launcher)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no launcher configured")
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n"))
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		// rofi and wofi exit non-zero when the menu is dismissed
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to run launcher: %w", err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+func init() {
+	menuCmd.Flags().BoolVar(&menuDmenu, "dmenu", false, "spawn the launcher and open the selected meeting")
+	menuCmd.Flags().StringVar(&menuLauncher, "launcher", "rofi -dmenu -i -p meetings", "dmenu-compatible launcher command used with --dmenu")
+	menuCmd.Flags().IntVar(&menuDays, "days", 1, "number of days ahead to include")
+	addAtFlag(menuCmd)
+	rootCmd.AddCommand(menuCmd)
+}
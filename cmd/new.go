@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	newAt       string
+	newDuration time.Duration
+	newTeams    bool
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <title>",
+	Short: "Create a calendar event",
+	Long: `Create an event on your Microsoft 365 calendar, optionally with a Teams meeting.
+Requires calendar write access; run 'calendar-widget reauth' if you set up before write access was requested.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runNew(args[0]); err != nil {
+			fmt.Printf("Creating event failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runNew(title string) error {
+	start, err := parseStartTime(newAt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Writing needs Calendars.ReadWrite; sign-in asks for consent the first time
+	auth.AddScope(calendar.WriteScope)
+	calendarService, err := newCalendarService(true, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	event, err := calendarService.CreateEvent(ctx, calendar.NewEvent{
+		Subject: title,
+		Start:   start,
+		End:     start.Add(newDuration),
+		Teams:   newTeams,
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "access") {
+			fmt.Println("Hint: run 'calendar-widget reauth' to grant calendar write access")
+		}
+		return err
+	}
+
+	fmt.Printf("✅ Created %q %s-%s\n", event.Subject, i18n.FormatTime(event.Start), i18n.FormatTime(event.End))
+	if event.TeamsLink != "" {
+		fmt.Printf("🔗 Teams Link: %s\n", event.TeamsLink)
+	}
+
+	return nil
+}
+
+// parseStartTime accepts "now", a clock time today ("14:00") or a full
+// "2006-01-02 15:04" timestamp
+func parseStartTime(value string, now time.Time) (time.Time, error) {
+	if value == "" || value == "now" {
+		return now, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start time %q: use 14:00 or \"2006-01-02 14:00\"", value)
+	}
+
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+}
+
+func init() {
+	newCmd.Flags().StringVar(&newAt, "at", "now", "start time (14:00, \"2006-01-02 14:00\" or now)")
+	newCmd.Flags().DurationVar(&newDuration, "duration", 30*time.Minute, "event length")
+	newCmd.Flags().BoolVar(&newTeams, "teams", false, "create a Teams online meeting")
+	rootCmd.AddCommand(newCmd)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/dismiss"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/notify"
+	"calendar-widget/internal/push"
+	"calendar-widget/internal/snooze"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// notifyWindow is how often the notification timer runs; each run covers
+// meetings whose reminder falls inside the last window
+const notifyWindow = time.Minute
+
+var notifyLead time.Duration
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send a desktop notification for meetings about to start",
+	Long: `Notify about blocking meetings that start in --lead (default 5m), or at their own Outlook
+reminder when calendar_reminders is on in settings.json. Meant to run once a minute from a timer,
+see 'install-service'. Uses the daemon's cache when it is fresh.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runNotify(); err != nil {
+			fmt.Fprintf(os.Stderr, "Notify failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runNotify() error {
+	if _, snoozed := snooze.Active(); snoozed {
+		return nil
+	}
+
+	events, err := notifyEvents()
+	if err != nil {
+		return err
+	}
+
+	pushSettings := loadSettings().Push
+	if err := push.Validate(pushSettings); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: push notifications disabled: %v\n", err)
+		pushSettings = config.PushConfig{}
+	}
+
+	for _, event := range events {
+		lead := notifyLead
+		if reminder, ok := event.Reminder(); ok {
+			lead = reminder
+		}
+		until := time.Until(event.Start)
+		if !event.IsBlockingEvent() || until <= lead-notifyWindow || until > lead {
+			continue
+		}
+		if dismiss.Active(event) {
+			continue
+		}
+
+		starts := i18n.T("Starts at %s", i18n.FormatTime(event.Start))
+		shown := notificationEvent(event)
+		body := starts
+		if shown.Location != "" {
+			body += "\n" + shown.Location
+		}
+		if err := notify.Send(shown.Subject, body); err != nil {
+			return err
+		}
+		if push.Enabled(pushSettings) && !pushSettings.IdleOnly {
+			if err := push.Send(context.Background(), pushSettings, push.NewMessage(event, starts, false)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// notificationEvent returns event as a desktop notification may show it,
+// redacted while privacy mode is on
+func notificationEvent(event calendar.Event) calendar.Event {
+	if privacyEnabled() {
+		return widget.RedactEvent(event)
+	}
+	return event
+}
+
+// notifyEvents reads upcoming events from the cache, falling back to Graph
+// when the daemon isn't running
+func notifyEvents() ([]calendar.Event, error) {
+	if snapshot, err := cache.Load(); err == nil && snapshot != nil && !snapshot.IsStale(10*time.Minute) {
+		return snapshot.UpcomingEvents, nil
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	events, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
+}
+
+func init() {
+	notifyCmd.Flags().DurationVar(&notifyLead, "lead", 5*time.Minute, "how long before the start to notify")
+	addLookaheadFlag(notifyCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/widget"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Where open sends the meeting
+const (
+	openInJoin    = "join"
+	openInOutlook = "outlook"
+)
+
+var openIn string
+
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the current or next meeting",
+	Long: `Open the current or next meeting. By default its join link is opened, like a left click;
+--in outlook opens the event itself in Outlook on the web instead, for editing it or replying to the invite.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runOpen(); err != nil {
+			fmt.Fprintf(os.Stderr, "Open failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runOpen() error {
+	if openIn != openInJoin && openIn != openInOutlook {
+		return fmt.Errorf("unknown --in %q, expected join or outlook", openIn)
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	upcomingEvents, err := calendarService.GetUpcomingEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if event == nil {
+		return fmt.Errorf("no current or upcoming meeting")
+	}
+
+	if openIn == openInOutlook {
+		link := event.OutlookLink()
+		if link == "" {
+			return fmt.Errorf("%s has no Outlook link", event.Subject)
+		}
+		return openMeetingLink(link)
+	}
+
+	link, err := widget.MeetingLink(*event)
+	if err != nil {
+		return err
+	}
+	return openMeetingLink(link)
+}
+
+func init() {
+	openCmd.Flags().StringVar(&openIn, "in", openInJoin, "where to open the meeting: join (its join link) or outlook (the event in Outlook on the web)")
+	openCmd.Flags().BoolVar(&clickPrintURL, "print-url", false, "print the link instead of opening it")
+	rootCmd.AddCommand(openCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/widget"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptWithin time.Duration
+	promptWidth  int
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the next meeting as a shell prompt segment",
+	Long: `Print a terse segment for a shell prompt or starship, e.g. "⏰ 12m→Standup", or
+"⏰ 25m left→Standup" during a meeting.
+
+It only reads the cache daemon's event cache, so it is fast and never
+touches the network or asks to sign in. Without a recent cache, or when no
+meeting starts within --within, it prints nothing.`,
+	// The prompt runs before every shell prompt, so it skips the root
+	// setup and only applies the settings the segment depends on
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		settings := loadSettings()
+		i18n.SetLocale(settings.Locale)
+		widget.SetTruncation(settings.Truncate.MaxWidth, settings.Truncate.Ellipsis)
+		privacyConfigured = settings.Privacy || private
+		autoPrivacy = settings.AutoPrivacy.Enabled
+		widget.SetPrivacy(privacyEnabled())
+		calendar.SetBlockingShowAs(settings.BlockingShowAs)
+		calendar.SetCalendarReminders(settings.CalendarReminders)
+		// The buffer still counts toward leaving, but the travel command is
+		// too slow to run for every prompt
+		if settings.LeaveBy.Buffer != "" {
+			calendar.SetTravel(settingDuration("leave_by.buffer", settings.LeaveBy.Buffer, 0), "")
+		}
+		applyRenderAt()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		widget.RunPrompt(promptWithin, promptWidth)
+	},
+}
+
+func init() {
+	promptCmd.Flags().DurationVar(&promptWithin, "within", widget.DefaultPromptWithin, "only show meetings starting within this long")
+	promptCmd.Flags().IntVar(&promptWidth, "width", widget.DefaultPromptWidth, "maximum width of the meeting subject")
+	addAtFlag(promptCmd)
+	rootCmd.AddCommand(promptCmd)
+}
@@ -26,6 +26,11 @@ func runReauth() error {
 		fmt.Printf("Warning: failed to clear tokens: %v\n", err)
 	}
 
+	// Keep signing in the same way as the original setup
+	if config, err := auth.LoadConfig(); err == nil && config.UseDeviceCode {
+		setupDeviceCode = true
+	}
+
 	fmt.Println("🔄 Re-authenticating...")
 	fmt.Println("Starting fresh authentication process...")
 
@@ -34,5 +39,6 @@ func runReauth() error {
 }
 
 func init() {
+	reauthCmd.Flags().BoolVar(&setupDeviceCode, "device-code", false, "sign in with a device code instead of a local browser")
 	rootCmd.AddCommand(reauthCmd)
 }
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	roomsAt       string
+	roomsDuration time.Duration
+	roomsBuilding string
+	roomsCapacity int
+	roomsFree     bool
+	roomsJSON     bool
+)
+
+var roomsCmd = &cobra.Command{
+	Use:   "rooms",
+	Short: "List meeting rooms and whether they are free",
+	Long: `List meeting rooms and their availability for a slot, by default the next 30 minutes.
+Rooms come from "rooms" in settings.json or, when that is empty, from the organization's directory
+(the places API, which needs the Place.Read.All permission). Availability is read with getSchedule.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runRooms(); err != nil {
+			fmt.Fprintf(os.Stderr, "Rooms failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// roomAvailability is a room with its availability for the slot, also the
+// JSON shape printed by `rooms --json`
+type roomAvailability struct {
+	calendar.Room
+	Free bool `json:"free"`
+	// FreeUntil is when a room that is free at the start gets booked
+	// during the slot
+	FreeUntil time.Time `json:"free_until,omitzero"`
+	// BusyUntil is when a room that is booked at the start frees up
+	BusyUntil time.Time `json:"busy_until,omitzero"`
+	// Error is set when the room's schedule couldn't be read
+	Error string `json:"error,omitempty"`
+}
+
+func runRooms() error {
+	start, err := parseStartTime(roomsAt, calendar.Now())
+	if err != nil {
+		return err
+	}
+	end := start.Add(roomsDuration)
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+	reader, ok := calendarService.(calendar.ScheduleReader)
+	if !ok {
+		return fmt.Errorf("this calendar provider can't read room availability")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	rooms, err := listRooms(ctx, calendarService, loadSettings().Rooms)
+	if err != nil {
+		return err
+	}
+	rooms = filterRooms(rooms, roomsBuilding, roomsCapacity)
+	if len(rooms) == 0 {
+		return fmt.Errorf("no rooms match")
+	}
+
+	emails := make([]string, len(rooms))
+	for i, room := range rooms {
+		emails[i] = room.Email
+	}
+	schedules, err := reader.GetSchedules(ctx, emails, start, end)
+	if err != nil {
+		return err
+	}
+	byEmail := make(map[string]calendar.Schedule, len(schedules))
+	for _, schedule := range schedules {
+		byEmail[strings.ToLower(schedule.Email)] = schedule
+	}
+
+	var available []roomAvailability
+	for _, room := range rooms {
+		availability := roomAvailability{Room: room}
+		schedule, found := byEmail[strings.ToLower(room.Email)]
+		switch {
+		case !found:
+			availability.Error = "no schedule returned"
+		case schedule.Error != "":
+			availability.Error = schedule.Error
+		case schedule.FreeBetween(start, end):
+			availability.Free = true
+		default:
+			if next := schedule.NextBusy(start); next.After(start) {
+				availability.FreeUntil = next
+			} else {
+				availability.BusyUntil = schedule.BusyUntil(start)
+			}
+		}
+		if roomsFree && !availability.Free {
+			continue
+		}
+		available = append(available, availability)
+	}
+
+	// Free rooms first, then the ones free the longest, then the ones
+	// freeing up soonest
+	sort.SliceStable(available, func(i, j int) bool {
+		a, b := available[i], available[j]
+		if a.Free != b.Free {
+			return a.Free
+		}
+		if a.FreeUntil.IsZero() != b.FreeUntil.IsZero() {
+			return !a.FreeUntil.IsZero()
+		}
+		if !a.FreeUntil.Equal(b.FreeUntil) {
+			return a.FreeUntil.After(b.FreeUntil)
+		}
+		if !a.BusyUntil.Equal(b.BusyUntil) {
+			return a.BusyUntil.Before(b.BusyUntil)
+		}
+		return a.Name < b.Name
+	})
+
+	if roomsJSON {
+		data, err := json.MarshalIndent(available, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal rooms: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s %s-%s\n\n", i18n.FormatDate(start), i18n.FormatTime(start), i18n.FormatTime(end))
+	if len(available) == 0 {
+		fmt.Println(i18n.T("No free rooms"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, room := range available {
+		capacity := ""
+		if room.Capacity > 0 {
+			capacity = fmt.Sprint(room.Capacity)
+		}
+		place := room.Building
+		if room.Floor != "" {
+			place = strings.TrimSpace(place + " " + i18n.T("floor %s", room.Floor))
+		}
+
+		var status string
+		switch {
+		case room.Error != "":
+			status = "⚠️ " + room.Error
+		case room.Free:
+			status = "🟢 " + i18n.T("Free")
+		case !room.FreeUntil.IsZero():
+			status = "🟡 " + i18n.T("Free until %s", i18n.FormatTime(room.FreeUntil))
+		default:
+			status = "🔴 " + i18n.T("Busy until %s", i18n.FormatTime(room.BusyUntil))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", room.Name, capacity, place, status)
+	}
+	return w.Flush()
+}
+
+// listRooms returns the rooms from settings, or the directory's when
+// settings has none
+func listRooms(ctx context.Context, calendarService calendar.CalendarProvider, configured []string) ([]calendar.Room, error) {
+	if len(configured) > 0 {
+		rooms := make([]calendar.Room, len(configured))
+		for i, email := range configured {
+			rooms[i] = calendar.Room{Name: email, Email: email}
+		}
+		return rooms, nil
+	}
+
+	finder, ok := calendarService.(calendar.RoomFinder)
+	if !ok {
+		return nil, fmt.Errorf("this calendar provider can't list rooms; list them under \"rooms\" in settings.json")
+	}
+	rooms, err := finder.FindRooms(ctx)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "access") || strings.Contains(strings.ToLower(err.Error()), "forbidden") {
+			fmt.Fprintln(os.Stderr, "Hint: listing rooms needs the Place.Read.All permission; or list room addresses under \"rooms\" in settings.json")
+		}
+		return nil, err
+	}
+	return rooms, nil
+}
+
+// filterRooms keeps the rooms in building (matched loosely) that seat at
+// least capacity people
+func filterRooms(rooms []calendar.Room, building string, capacity int) []calendar.Room {
+	var kept []calendar.Room
+	for _, room := range rooms {
+		if building != "" && !strings.Contains(strings.ToLower(room.Building), strings.ToLower(building)) {
+			continue
+		}
+		if capacity > 0 && room.Capacity < capacity {
+			continue
+		}
+		kept = append(kept, room)
+	}
+	return kept
+}
+
+func init() {
+	roomsCmd.Flags().StringVar(&roomsAt, "at", "now", "start of the slot (14:00, \"2006-01-02 14:00\" or now)")
+	roomsCmd.Flags().DurationVar(&roomsDuration, "duration", 30*time.Minute, "length of the slot")
+	roomsCmd.Flags().StringVar(&roomsBuilding, "building", "", "only rooms in this building")
+	roomsCmd.Flags().IntVar(&roomsCapacity, "capacity", 0, "only rooms seating at least this many people")
+	roomsCmd.Flags().BoolVar(&roomsFree, "free", false, "only list rooms that are free for the whole slot")
+	roomsCmd.Flags().BoolVar(&roomsJSON, "json", false, "print rooms as JSON")
+	rootCmd.AddCommand(roomsCmd)
+}
@@ -1,8 +1,18 @@
 package cmd
 
 import (
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/network"
+	"calendar-widget/internal/opener"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/screenshare"
+	"calendar-widget/internal/widget"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -10,6 +20,10 @@ import (
 var (
 	configFile string
 	debug      bool
+	insecure   bool
+	renderAt   string
+	private    bool
+	lookahead  int
 )
 
 var rootCmd = &cobra.Command{
@@ -17,12 +31,99 @@ var rootCmd = &cobra.Command{
 	Short: "A calendar widget for waybar",
 	Long: `A calendar widget for waybar that shows your next Microsoft 365 meeting
 with visual indicators for urgency and click-to-join functionality for Teams meetings.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		settings := loadSettings()
+		i18n.SetLocale(settings.Locale)
+		if err := i18n.SetTimeFormat(settings.TimeFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		render.SetIcons(settings.Icons)
+		render.SetPango(settings.Pango)
+		widget.SetSingleClass(settings.SingleClass || singleClass)
+		render.SetShowAttendees(settings.Tooltip.ShowAttendees)
+		if err := widget.SetTheme(settings.Theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		widget.SetTruncation(settings.Truncate.MaxWidth, settings.Truncate.Ellipsis)
+		if err := widget.SetClassRules(settings.ClassRules); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if err := opener.SetCommands(settings.Openers); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		privacyConfigured = settings.Privacy || private
+		autoPrivacy = settings.AutoPrivacy.Enabled
+		widget.SetPrivacy(privacyEnabled())
+		calendar.SetBlockingShowAs(settings.BlockingShowAs)
+		calendar.SetCalendarReminders(settings.CalendarReminders)
+		calendar.SetCategoryColors(settings.CategoryColors)
+		if settings.CategoryColors {
+			auth.AddScope(calendar.CategoriesScope)
+		}
+		calendar.SetGroupCalendars(settings.GroupCalendars)
+		if len(settings.GroupCalendars) > 0 {
+			auth.AddScope(calendar.GroupScope)
+		}
+		switch settings.Cancelled {
+		case "", config.CancelledHide:
+			calendar.SetShowCancelled(false)
+		case config.CancelledShow:
+			calendar.SetShowCancelled(true)
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: invalid cancelled %q, expected hide or show\n", settings.Cancelled)
+		}
+		if lookahead > 0 {
+			calendar.SetLookaheadDays(lookahead)
+		} else {
+			calendar.SetLookaheadDays(settings.LookaheadDays)
+		}
+		var travelBuffer time.Duration
+		if settings.LeaveBy.Buffer != "" {
+			var err error
+			if travelBuffer, err = time.ParseDuration(settings.LeaveBy.Buffer); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: invalid leave_by.buffer: %v\n", err)
+			}
+		}
+		calendar.SetTravel(travelBuffer, settings.LeaveBy.Command)
+		var percentageHorizon time.Duration
+		if settings.PercentageHorizon != "" {
+			var err error
+			if percentageHorizon, err = time.ParseDuration(settings.PercentageHorizon); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: invalid percentage_horizon: %v\n", err)
+			}
+		}
+		widget.SetPercentageHorizon(percentageHorizon)
+		if insecure {
+			fmt.Fprintln(os.Stderr, "Warning: TLS certificate verification is disabled")
+		}
+		if err := network.Configure(network.Options{
+			Proxy:      settings.Proxy,
+			CACertPath: settings.CACertPath,
+			Insecure:   insecure,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		applyRenderAt()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Run the widget by default
 		widgetCmd.Run(cmd, args)
 	},
 }
 
+// applyRenderAt stops the clock at --at, exiting when it doesn't parse
+func applyRenderAt() {
+	if renderAt == "" {
+		return
+	}
+	at, err := parseRenderAt(renderAt, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	calendar.SetClock(calendar.FixedClock(at))
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -30,11 +131,86 @@ func Execute() {
 	}
 }
 
+// loadSettings reads the display settings file, falling back to defaults so a
+// broken file never takes the bar down
+// privacyConfigured and autoPrivacy are the privacy settings the command
+// started with
+var privacyConfigured, autoPrivacy bool
+
+// privacyEnabled reports whether private events must be redacted: always
+// with privacy or --private, and with auto privacy while the daemon sees the
+// screen being shared. Long-running commands ask again before showing
+// anything, since sharing can start at any time.
+func privacyEnabled() bool {
+	return privacyConfigured || (autoPrivacy && screenshare.Active())
+}
+
+func loadSettings() *config.Config {
+	settings, err := config.Load(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using default settings\n", err)
+		return config.Default()
+	}
+	return settings
+}
+
+// newCalendarService creates a calendar service configured from settings
+func newCalendarService(allowInteractive bool, forceRefresh bool) (*calendar.CalendarService, error) {
+	calendarService, err := calendar.NewCalendarServiceWithRefresh(allowInteractive, forceRefresh)
+	if err != nil {
+		return nil, err
+	}
+	calendarService.SetMaxEvents(loadSettings().MaxEvents)
+	return calendarService, nil
+}
+
+// newCalendarProvider creates the calendar backend for read-only commands.
+// It honors CALENDAR_WIDGET_FAKE_DATA, so these commands work without an account.
+func newCalendarProvider(allowInteractive bool, forceRefresh bool) (calendar.CalendarProvider, error) {
+	return calendar.NewProvider(allowInteractive, forceRefresh, loadSettings().MaxEvents)
+}
+
+// addAtFlag registers --at on commands that render output, so they can be
+// previewed as of another time
+func addAtFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&renderAt, "at", "", "render as of this time (14:00, \"2006-01-02 14:00\" or RFC 3339)")
+}
+
+// addChipsFlags registers --within and --slot for the chips display mode
+// and for clicks on a single chip
+func addChipsFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&chipsWithin, "within", widget.DefaultChipsWithin, "chips cover meetings starting within this long")
+	cmd.Flags().IntVar(&chipSlot, "slot", -1, "meeting chip to show or click (0 is the first; -1 prints all chips as an array)")
+}
+
+// addLookaheadFlag registers --days on commands that look for upcoming
+// events. Commands listing a date range have their own --days.
+func addLookaheadFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&lookahead, "days", 0, "days ahead to search for upcoming meetings (default from settings, or 7)")
+}
+
+// parseRenderAt accepts an RFC 3339 timestamp or anything parseStartTime does
+func parseRenderAt(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Local(), nil
+	}
+	t, err := parseStartTime(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --at time %q: use 14:00, \"2006-01-02 14:00\" or RFC 3339", value)
+	}
+	return t, nil
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.config/calendar-widget/config.json)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "settings file (default is $XDG_CONFIG_HOME/calendar-widget/settings.json)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode")
+	rootCmd.PersistentFlags().BoolVar(&private, "private", false, "hide details of private and confidential events")
+	rootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false, "skip TLS certificate verification (debugging only)")
 
 	rootCmd.AddCommand(widgetCmd)
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(tooltipCmd)
+	addAtFlag(tooltipCmd)
+	addLookaheadFlag(tooltipCmd)
+	addLookaheadFlag(widgetCmd)
 }
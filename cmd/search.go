@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchFrom string
+	searchTo   string
+	searchJSON bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search events in a date range",
+	Long: `Search the events between --from and --to (dates as 2006-01-02, both included) for the query.
+An event matches when every word of the query appears in its subject, body, organizer, attendees or location.
+By default the next 30 days are searched; pass an earlier --from to look back.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSearch(strings.Join(args, " ")); err != nil {
+			fmt.Fprintf(os.Stderr, "Search failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runSearch(query string) error {
+	now := calendar.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	from, err := parseSearchDate(searchFrom, today, today)
+	if err != nil {
+		return err
+	}
+	to, err := parseSearchDate(searchTo, today, from.AddDate(0, 0, 30))
+	if err != nil {
+		return err
+	}
+	// --to is inclusive
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return fmt.Errorf("--to is before --from")
+	}
+
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var events []calendar.Event
+	if searcher, ok := calendarService.(calendar.EventSearcher); ok {
+		events, err = searcher.SearchEvents(ctx, query, from, to)
+	} else {
+		events, err = calendarService.GetEventsBetween(ctx, from, to)
+		events = calendar.MatchingEvents(events, query)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to search events: %w", err)
+	}
+
+	if searchJSON {
+		found := make([]listedEvent, 0, len(events))
+		for _, event := range events {
+			found = append(found, newListedEvent(event))
+		}
+
+		data, err := json.MarshalIndent(found, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Println(i18n.T("No meetings match %q", query))
+		return nil
+	}
+
+	for _, event := range events {
+		fmt.Printf("%s %s-%s  %s\n",
+			i18n.FormatDate(event.Start),
+			i18n.FormatTime(event.Start),
+			i18n.FormatTime(event.End),
+			event.Subject)
+		if link := event.GetJoinLink(); link != "" {
+			fmt.Printf("    %s\n", link)
+		} else if link := event.OutlookLink(); link != "" {
+			fmt.Printf("    %s\n", link)
+		}
+	}
+
+	return nil
+}
+
+// parseSearchDate parses a 2006-01-02 date or "today", or returns fallback
+// when value is empty
+func parseSearchDate(value string, today, fallback time.Time) (time.Time, error) {
+	switch value {
+	case "":
+		return fallback, nil
+	case "today":
+		return today, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, today.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use 2006-01-02 or today", value)
+	}
+	return t, nil
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchFrom, "from", "", "first day to search, as 2006-01-02 or today (default today)")
+	searchCmd.Flags().StringVar(&searchTo, "to", "", "last day to search, as 2006-01-02 or today (default 30 days after --from)")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "print matching events as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	daemonUnit = "calendar-widget.service"
+	notifyUnit = "calendar-widget-notify.service"
+	timerUnit  = "calendar-widget-notify.timer"
+
+	digestUnit      = "calendar-widget-digest.service"
+	digestTimerUnit = "calendar-widget-digest.timer"
+)
+
+var (
+	servicePrint    bool
+	serviceNoEnable bool
+	serviceSignal   int
+	serviceAutojoin bool
+	serviceDigest   string
+)
+
+var installServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Install user systemd units for the daemon and notifications",
+	Long: `Write user-level systemd units for the cache daemon and a once-a-minute meeting
+notification timer to ~/.config/systemd/user, then enable and start them.
+--digest adds a timer sending the morning digest on weekdays at that time.
+Use --print to inspect the units without installing them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runInstallService(); err != nil {
+			fmt.Fprintf(os.Stderr, "Service installation failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runInstallService() error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(self); err == nil {
+		self = resolved
+	}
+
+	units := map[string]string{
+		daemonUnit: daemonUnitFile(self),
+		notifyUnit: notifyUnitFile(self),
+		timerUnit:  timerUnitFile(),
+	}
+	order := []string{daemonUnit, notifyUnit, timerUnit}
+	timers := []string{daemonUnit, timerUnit}
+	if serviceDigest != "" {
+		digestTimer, err := digestTimerUnitFile(serviceDigest)
+		if err != nil {
+			return err
+		}
+		units[digestUnit] = digestUnitFile(self)
+		units[digestTimerUnit] = digestTimer
+		order = append(order, digestUnit, digestTimerUnit)
+		timers = append(timers, digestTimerUnit)
+	}
+
+	if servicePrint {
+		for _, name := range order {
+			fmt.Printf("# %s\n%s\n", name, units[name])
+		}
+		return nil
+	}
+
+	unitDir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create unit directory: %w", err)
+	}
+
+	for _, name := range order {
+		path := filepath.Join(unitDir, name)
+		if err := os.WriteFile(path, []byte(units[name]), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		fmt.Printf("✅ Wrote %s\n", path)
+	}
+
+	if serviceNoEnable {
+		fmt.Println("Enable with: systemctl --user enable --now " + strings.Join(timers, " "))
+		return nil
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctl(append([]string{"enable", "--now"}, timers...)...); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Enabled " + strings.Join(timers, ", "))
+	return nil
+}
+
+// execStart quotes the binary and forwards --config so the units read the
+// same settings as this invocation
+func execStart(self string, args ...string) string {
+	parts := append([]string{systemdQuote(self)}, args...)
+	if configFile != "" {
+		if abs, err := filepath.Abs(configFile); err == nil {
+			parts = append(parts, "--config", systemdQuote(abs))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func daemonUnitFile(self string) string {
+	args := []string{"daemon"}
+	if serviceSignal > 0 {
+		args = append(args, fmt.Sprintf("--signal %d", serviceSignal))
+	}
+	if serviceAutojoin {
+		args = append(args, "--autojoin")
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=Calendar widget cache daemon
+After=graphical-session.target network-online.target
+PartOf=graphical-session.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=30
+Environment=PATH=%s
+
+[Install]
+WantedBy=graphical-session.target
+`, execStart(self, args...), os.Getenv("PATH"))
+}
+
+func notifyUnitFile(self string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Calendar widget meeting notifications
+
+[Service]
+Type=oneshot
+ExecStart=%s
+Environment=PATH=%s
+`, execStart(self, "notify"), os.Getenv("PATH"))
+}
+
+func timerUnitFile() string {
+	return `[Unit]
+Description=Check for upcoming meetings every minute
+PartOf=graphical-session.target
+
+[Timer]
+OnCalendar=*-*-* *:*:00
+AccuracySec=5s
+Unit=` + notifyUnit + `
+
+[Install]
+WantedBy=timers.target
+`
+}
+
+func digestUnitFile(self string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Calendar widget morning digest
+
+[Service]
+Type=oneshot
+ExecStart=%s
+Environment=PATH=%s
+`, execStart(self, "digest"), os.Getenv("PATH"))
+}
+
+// digestTimerUnitFile runs the digest on weekdays at clock, e.g. "08:30"
+func digestTimerUnitFile(clock string) (string, error) {
+	at, err := time.Parse("15:04", clock)
+	if err != nil {
+		return "", fmt.Errorf("invalid --digest time %q, expected e.g. 08:30", clock)
+	}
+
+	return `[Unit]
+Description=Send the meeting digest every weekday morning
+PartOf=graphical-session.target
+
+[Timer]
+OnCalendar=Mon..Fri *-*-* ` + at.Format("15:04") + `:00
+Persistent=true
+Unit=` + digestUnit + `
+
+[Install]
+WantedBy=timers.target
+`, nil
+}
+
+func systemdUserDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "systemd", "user"), nil
+}
+
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", append([]string{"--user"}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl --user %s failed: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+func init() {
+	installServiceCmd.Flags().BoolVar(&servicePrint, "print", false, "print the units instead of installing them")
+	installServiceCmd.Flags().BoolVar(&serviceNoEnable, "no-enable", false, "write the units without enabling them")
+	installServiceCmd.Flags().IntVar(&serviceSignal, "signal", 8, "waybar signal the daemon sends after each refresh (0 to disable)")
+	installServiceCmd.Flags().BoolVar(&serviceAutojoin, "autojoin", false, "run the daemon with auto-join enabled")
+	installServiceCmd.Flags().StringVar(&serviceDigest, "digest", "", "also send the morning digest on weekdays at this time, e.g. 08:30")
+	rootCmd.AddCommand(installServiceCmd)
+}
@@ -10,11 +10,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var setupDeviceCode bool
+
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Setup Microsoft 365 authentication",
 	Long: `Setup authentication for Microsoft 365 calendar access.
-This will authenticate you with Microsoft using a standard login flow - no app registration required!`,
+This will authenticate you with Microsoft using a standard login flow - no app registration required!
+Use --device-code on machines without a browser: it prints a code to enter at microsoft.com/devicelogin
+from any other device.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runSetup(); err != nil {
 			fmt.Printf("Setup failed: %v\n", err)
@@ -31,19 +35,23 @@ func runSetup() error {
 	fmt.Println("No app registration required - we'll use Microsoft's standard authentication flow.")
 	fmt.Println()
 	fmt.Println("This widget can access:")
-	fmt.Println("• Your calendar events (read-only)")
+	fmt.Println("• Your calendar events (read, and create when you ask it to)")
 	fmt.Println("• Your basic profile information")
 	fmt.Println()
 	fmt.Println("Your credentials will be securely cached locally for future use.")
 	fmt.Println()
 
-	// Create default public client config
-	config := &auth.Config{
-		ClientID:    auth.PublicClientID,
-		TenantID:    auth.CommonTenant,
-		RedirectURI: auth.RedirectURI,
-		UsePublic:   true,
+	// Switch to the default public client, keeping settings such as guest
+	// tenants and token encryption
+	config, err := auth.LoadConfig()
+	if err != nil {
+		config = &auth.Config{}
 	}
+	config.ClientID = auth.PublicClientID
+	config.TenantID = auth.CommonTenant
+	config.RedirectURI = auth.RedirectURI
+	config.UsePublic = true
+	config.UseDeviceCode = setupDeviceCode
 
 	// Save the default config
 	if err := auth.SaveConfig(config); err != nil {
@@ -51,18 +59,31 @@ func runSetup() error {
 	}
 
 	fmt.Println("Starting authentication process...")
-	fmt.Println("Your default browser will open for Microsoft login.")
-	fmt.Println("Please complete the authentication in your browser.")
+	if setupDeviceCode {
+		fmt.Println("Open the link below on any device and enter the code to sign in.")
+	} else {
+		fmt.Println("Your default browser will open for Microsoft login.")
+		fmt.Println("Please complete the authentication in your browser.")
+	}
 	fmt.Println()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	_, err := auth.GetAccessTokenWithOptions(ctx, true) // Force interactive authentication
+	_, err = auth.GetAccessTokenWithOptions(ctx, true) // Force interactive authentication
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
+	// Guest tenants may ask for consent or their terms of use once
+	for _, tenant := range config.GuestTenants {
+		if _, err := auth.GetAccessTokenForTenant(ctx, tenant.TenantID, true, false); err != nil {
+			fmt.Printf("⚠ Sign-in to guest tenant %s failed: %v\n", tenant.Label(), err)
+		} else {
+			fmt.Printf("✅ Signed in to guest tenant %s\n", tenant.Label())
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("✅ Authentication successful!")
 	fmt.Println("✅ Credentials cached for future use")
@@ -72,3 +93,7 @@ func runSetup() error {
 
 	return nil
 }
+
+func init() {
+	setupCmd.Flags().BoolVar(&setupDeviceCode, "device-code", false, "sign in with a device code instead of a local browser")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/snooze"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var snoozeClear bool
+
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze [duration|time]",
+	Short: "Mute urgent styling and notifications for a while",
+	Long: `Suppress urgent styling and notifications until the given time.
+Accepts a duration (1h, 30m) or a clock time (14:30). Use --clear to end the snooze early.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSnooze(args); err != nil {
+			fmt.Printf("Snooze failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runSnooze(args []string) error {
+	if snoozeClear {
+		if err := snooze.Clear(); err != nil {
+			return err
+		}
+		fmt.Println("🔔 Snooze cleared")
+		return nil
+	}
+
+	if len(args) == 0 {
+		if until, ok := snooze.Active(); ok {
+			fmt.Printf("🔕 Snoozed until %s\n", i18n.FormatTime(until))
+		} else {
+			fmt.Println("🔔 Not snoozed")
+		}
+		return nil
+	}
+
+	until, err := snooze.ParseUntil(args[0], time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := snooze.Set(until); err != nil {
+		return err
+	}
+
+	fmt.Printf("🔕 Snoozed until %s\n", i18n.FormatTime(until))
+	return nil
+}
+
+func init() {
+	snoozeCmd.Flags().BoolVar(&snoozeClear, "clear", false, "end the current snooze")
+	rootCmd.AddCommand(snoozeCmd)
+}
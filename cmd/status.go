@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Names of the calendar backends the widget can read from
+const (
+	statusProviderGraph = "microsoft-graph"
+	statusProviderEWS   = "exchange-ews"
+	statusProviderFake  = "fake"
+)
+
+// statusStaleAge is how old the cache may get before it counts as stale
+const statusStaleAge = 10 * time.Minute
+
+var statusJSON bool
+
+// widgetStatus is the machine-readable output of `status --json`
+type widgetStatus struct {
+	Healthy  bool         `json:"healthy"`
+	Provider string       `json:"provider"`
+	Auth     authStatus   `json:"auth"`
+	Cache    cacheStatus  `json:"cache"`
+	Daemon   daemonStatus `json:"daemon"`
+}
+
+type authStatus struct {
+	SignedIn bool   `json:"signed_in"`
+	Account  string `json:"account,omitempty"`
+	// AccountType is "personal" or "work" for Microsoft accounts
+	AccountType string     `json:"account_type,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// TokenEncryption is the key the token cache is encrypted with, or
+	// "none" when it is stored in plain text
+	TokenEncryption string `json:"token_encryption,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+type cacheStatus struct {
+	Path string `json:"path"`
+	// LastFetch is when the daemon last fetched events successfully
+	LastFetch  *time.Time `json:"last_fetch,omitempty"`
+	AgeSeconds int        `json:"age_seconds,omitempty"`
+	Stale      bool       `json:"stale"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type daemonStatus struct {
+	Running bool `json:"running"`
+	PID     int  `json:"pid,omitempty"`
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show sign-in, cache and daemon state",
+	Long: `Report whether you are signed in (and when the access token expires), how fresh the event
+cache is, and whether the daemon is running. Exits with status 1 when not signed in or the cache is stale.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		status := collectStatus()
+
+		if statusJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(status); err != nil {
+				fmt.Fprintf(os.Stderr, "Status failed: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			printStatus(status)
+		}
+
+		if !status.Healthy {
+			os.Exit(1)
+		}
+	},
+}
+
+func collectStatus() widgetStatus {
+	status := widgetStatus{
+		Provider: statusProviderGraph,
+		Cache:    cacheStatus{Path: cache.GetCachePath()},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	config, _ := auth.LoadConfig()
+	switch {
+	case os.Getenv(calendar.FakeDataEnv) != "":
+		status.Provider = statusProviderFake
+		status.Auth.SignedIn = true
+	case config != nil && config.Provider == auth.ProviderEWS:
+		// EWS sends credentials with every request, there is no token to check
+		status.Provider = statusProviderEWS
+		if config.EWS == nil {
+			status.Auth.Error = "config.json has no ews section"
+		} else {
+			status.Auth.SignedIn = true
+			status.Auth.Account = config.EWS.Username
+		}
+	default:
+		status.Auth = graphAuthStatus(ctx)
+	}
+
+	snapshot, err := cache.Load()
+	switch {
+	case err != nil:
+		status.Cache.Error = err.Error()
+		status.Cache.Stale = true
+	case snapshot == nil:
+		status.Cache.Stale = true
+	default:
+		lastFetch := snapshot.UpdatedAt
+		status.Cache.LastFetch = &lastFetch
+		status.Cache.AgeSeconds = int(time.Since(lastFetch).Seconds())
+		status.Cache.Stale = snapshot.IsStale(statusStaleAge)
+	}
+
+	if pid := daemonPID(); pid != 0 {
+		status.Daemon.Running = true
+		status.Daemon.PID = pid
+	}
+
+	status.Healthy = status.Auth.SignedIn && !status.Cache.Stale
+	return status
+}
+
+// graphAuthStatus checks the cached Microsoft token. It never prompts:
+// status must be safe to run from scripts and click handlers.
+func graphAuthStatus(ctx context.Context) authStatus {
+	var status authStatus
+	token, err := auth.GetAccessTokenWithOptions(ctx, false)
+	// Read after the token, since reading a plain-text cache encrypts it
+	status.TokenEncryption = auth.TokenCacheEncryption()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.SignedIn = true
+	expiresAt := token.ExpiresOn
+	status.ExpiresAt = &expiresAt
+	if account, err := auth.SignedInAccount(ctx); err == nil {
+		status.Account = account
+	}
+	if accountType, err := auth.DetectAccountType(ctx); err == nil {
+		status.AccountType = accountType
+	}
+	return status
+}
+
+func printStatus(status widgetStatus) {
+	fmt.Printf("Provider:   %s\n", status.Provider)
+
+	switch {
+	case !status.Auth.SignedIn:
+		fmt.Printf("Auth:       not signed in (%s)\n", status.Auth.Error)
+	case status.Auth.ExpiresAt == nil && status.Auth.Account != "":
+		fmt.Printf("Auth:       signed in as %s\n", status.Auth.Account)
+	case status.Auth.ExpiresAt == nil:
+		fmt.Printf("Auth:       signed in\n")
+	case status.Auth.Account != "" && status.Auth.AccountType != "":
+		fmt.Printf("Auth:       signed in as %s (%s account), token expires %s\n", status.Auth.Account, status.Auth.AccountType, status.Auth.ExpiresAt.Local().Format(time.RFC3339))
+	case status.Auth.Account != "":
+		fmt.Printf("Auth:       signed in as %s, token expires %s\n", status.Auth.Account, status.Auth.ExpiresAt.Local().Format(time.RFC3339))
+	default:
+		fmt.Printf("Auth:       signed in, token expires %s\n", status.Auth.ExpiresAt.Local().Format(time.RFC3339))
+	}
+
+	switch status.Auth.TokenEncryption {
+	case "":
+	case auth.EncryptionNone:
+		fmt.Printf("Tokens:     stored in plain text\n")
+	default:
+		fmt.Printf("Tokens:     encrypted with the %s key\n", status.Auth.TokenEncryption)
+	}
+
+	switch {
+	case status.Cache.Error != "":
+		fmt.Printf("Cache:      unreadable (%s)\n", status.Cache.Error)
+	case status.Cache.LastFetch == nil:
+		fmt.Printf("Cache:      empty, run 'calendar-widget daemon'\n")
+	default:
+		freshness := "fresh"
+		if status.Cache.Stale {
+			freshness = "stale"
+		}
+		age := time.Duration(status.Cache.AgeSeconds) * time.Second
+		fmt.Printf("Cache:      %s, last fetch %s ago\n", freshness, age)
+	}
+
+	if status.Daemon.Running {
+		fmt.Printf("Daemon:     running (pid %d)\n", status.Daemon.PID)
+	} else {
+		fmt.Printf("Daemon:     not running\n")
+	}
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "print status as JSON")
+	rootCmd.AddCommand(statusCmd)
+}
@@ -21,12 +21,10 @@ var tooltipCmd = &cobra.Command{
 }
 
 func runTooltip() error {
-	w, err := widget.NewWidget(&widget.Config{
-		Debug: debug,
+	w := widget.NewWidget(&widget.Config{
+		Debug:    debug,
+		Settings: loadSettings(),
 	})
-	if err != nil {
-		return fmt.Errorf("failed to create widget: %w", err)
-	}
 
 	return w.ShowTooltip()
 }
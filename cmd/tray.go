@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"calendar-widget/internal/widget"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var trayCmd = &cobra.Command{
+	Use:   "tray",
+	Short: "Show the next meeting as a tray icon",
+	Long: `Show the next meeting in the tray, for desktops without waybar: KDE, GNOME with the AppIndicator
+extension, other StatusNotifierItem hosts, and Windows.
+
+The icon is colored by the meeting's status and its tooltip counts down to
+it, using the countdown formats from settings. The menu lists today's
+meetings; clicking one joins it. Events come from the cache daemon when it
+runs, otherwise from the calendar every minute.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runTray(); err != nil {
+			fmt.Printf("Tray failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runTray() error {
+	w := widget.NewWidget(&widget.Config{
+		Debug:    debug,
+		Settings: loadSettings(),
+	})
+
+	return w.RunTray()
+}
+
+func init() {
+	addLookaheadFlag(trayCmd)
+	rootCmd.AddCommand(trayCmd)
+}
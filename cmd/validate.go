@@ -67,7 +67,8 @@ func runValidate() error {
 		} else if strings.Contains(errorStr, "insufficient_privileges") || strings.Contains(errorStr, "need admin approval") {
 			fmt.Println("🔧 SOLUTION: Missing permissions or admin consent required.")
 			fmt.Println("   1. Go to your Azure AD app → 'API permissions' tab")
-			fmt.Println("   2. Ensure 'Calendars.Read' and 'User.Read' are added")
+			fmt.Println("   2. Ensure 'Calendars.Read' and 'User.Read' are added ('Calendars.ReadWrite' too for the")
+			fmt.Println("      new, focus and dismiss commands, 'MailboxSettings.Read' for category_colors)")
 			fmt.Println("   3. Click 'Grant admin consent for [organization]'")
 			fmt.Println()
 		} else if strings.Contains(errorStr, "invalid_client") || strings.Contains(errorStr, "Application not found") {
@@ -80,7 +81,8 @@ func runValidate() error {
 		} else {
 			fmt.Println("🔧 Common solutions:")
 			fmt.Println("   1. Make sure 'Allow public client flows' is enabled in Azure AD app")
-			fmt.Println("   2. Ensure the app has 'Calendars.Read' and 'User.Read' permissions")
+			fmt.Println("   2. Ensure the app has 'Calendars.Read' and 'User.Read' permissions ('Calendars.ReadWrite'")
+			fmt.Println("      too for the new, focus and dismiss commands, 'MailboxSettings.Read' for category_colors)")
 			fmt.Println("   3. Check if admin consent is required and granted")
 			fmt.Println("   4. Verify the Client ID and Tenant ID are correct")
 			fmt.Println()
@@ -1,19 +1,38 @@
 package cmd
 
 import (
+	"calendar-widget/internal/config"
 	"calendar-widget/internal/widget"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
-var forceRefresh bool
+// Waybar modules
+const (
+	moduleMeeting = "meeting"
+	moduleDate    = "date"
+)
+
+var (
+	waybarModuleName string
+	forceRefresh     bool
+	display          string
+	chipsWithin      time.Duration
+	chipSlot         int
+	singleClass      bool
+	outputFormat     string
+)
 
 var waybarCmd = &cobra.Command{
 	Use:   "waybar",
 	Short: "Run in waybar mode with JSON output",
-	Long:  `Run the calendar widget in waybar mode, outputting JSON format suitable for waybar modules.`,
+	Long: `Run the calendar widget in waybar mode, outputting JSON format suitable for waybar modules.
+
+With --format swiftbar it prints a SwiftBar or xbar plugin instead: the
+next meeting in the macOS menu bar and today's schedule in its menu.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runWaybar(); err != nil {
 			fmt.Printf("Waybar mode failed: %v\n", err)
@@ -23,20 +42,69 @@ var waybarCmd = &cobra.Command{
 }
 
 func runWaybar() error {
-	w, err := widget.NewWidgetWithOptions(&widget.Config{
+	settings := loadSettings()
+
+	switch waybarModuleName {
+	case moduleMeeting:
+	case moduleDate:
+		if outputFormat != widget.FormatWaybar {
+			return fmt.Errorf("--module date only supports --format waybar")
+		}
+		return runDateModule(settings)
+	default:
+		return fmt.Errorf("unknown module %q, expected meeting or date", waybarModuleName)
+	}
+
+	if err := widget.ValidateFormat(outputFormat, display); err != nil {
+		return err
+	}
+
+	barLayout := settings.Layout.Waybar
+	if layout != "" {
+		barLayout = layout
+	}
+	if err := widget.SetWaybarLayout(barLayout); err != nil {
+		return err
+	}
+
+	config := &widget.Config{
 		RefreshInterval: refresh,
-		Compact:         true,
 		Debug:           debug,
-	}, forceRefresh) // Allow interactive authentication if force refresh is requested
-	if err != nil {
-		return fmt.Errorf("failed to create widget: %w", err)
+		Display:         display,
+		MinFreeSlot:     minFreeSlot,
+		ChipsWithin:     chipsWithin,
+		ChipSlot:        chipSlot,
+		Format:          outputFormat,
+		Settings:        settings,
 	}
 
+	// Countdown runs every second, so it is served from the daemon's cache
+	if display == widget.DisplayCountdown {
+		return widget.RunWaybarCountdown(config)
+	}
+
+	w := widget.NewWidgetWithOptions(config, forceRefresh) // Allow interactive authentication if force refresh is requested
 	return w.RunWaybarWithRefresh(forceRefresh)
 }
 
+// runDateModule prints the date with a month view tooltip. Without a
+// calendar it still prints the date, so it can stand in for the clock.
+func runDateModule(settings *config.Config) error {
+	w := widget.NewWidgetWithOptions(&widget.Config{Debug: debug, Settings: settings}, forceRefresh)
+	return w.RunDateModule()
+}
+
 func init() {
+	waybarCmd.Flags().StringVar(&waybarModuleName, "module", moduleMeeting, "module to render: meeting, or date for today's date with a month view tooltip")
 	waybarCmd.Flags().IntVar(&refresh, "refresh", 60, "refresh interval in seconds")
 	waybarCmd.Flags().BoolVar(&forceRefresh, "force-refresh", false, "force token refresh on this run")
+	waybarCmd.Flags().StringVar(&display, "display", widget.DisplayNext, "what to show in the bar (next|remaining|count|freeslot|countdown|multi|chips)")
+	waybarCmd.Flags().DurationVar(&minFreeSlot, "min-free", 15*time.Minute, "minimum free slot length for --display freeslot")
+	waybarCmd.Flags().StringVar(&layout, "layout", "", "bar layout for next, remaining and chips: micro (icon and minutes), compact (icon and title) or full (with start time and location)")
+	waybarCmd.Flags().BoolVar(&singleClass, "single-class", false, "emit only the status class as a string instead of a class list")
+	waybarCmd.Flags().StringVar(&outputFormat, "format", widget.FormatWaybar, "output format: waybar, swiftbar for a SwiftBar or xbar plugin on macOS, eww for JSON with today's events, or tmux or zellij for a status line")
+	addChipsFlags(waybarCmd)
+	addAtFlag(waybarCmd)
+	addLookaheadFlag(waybarCmd)
 	rootCmd.AddCommand(waybarCmd)
 }
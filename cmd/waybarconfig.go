@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"calendar-widget/internal/widget"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	waybarConfigCSS     bool
+	waybarConfigModule  string
+	waybarConfigDisplay string
+	waybarConfigSignal  int
+)
+
+var waybarConfigCmd = &cobra.Command{
+	Use:   "waybar-config",
+	Short: "Print a waybar module snippet (or matching CSS with --css)",
+	Long: `Print a ready-to-paste waybar custom module block wired to this binary, including
+the refresh signal and click handlers. With --css, print a style.css covering every class the widget emits.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runWaybarConfig(); err != nil {
+			fmt.Fprintf(os.Stderr, "Waybar config failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// waybarModule mirrors waybar's custom module options in the order they are
+// usually written
+type waybarModule struct {
+	Exec          string `json:"exec"`
+	ReturnType    string `json:"return-type"`
+	Interval      int    `json:"interval"`
+	Signal        int    `json:"signal,omitempty"`
+	Tooltip       bool   `json:"tooltip"`
+	OnClick       string `json:"on-click"`
+	OnClickMiddle string `json:"on-click-middle"`
+	OnClickRight  string `json:"on-click-right"`
+	OnScrollUp    string `json:"on-scroll-up"`
+	OnScrollDown  string `json:"on-scroll-down"`
+}
+
+func runWaybarConfig() error {
+	if waybarConfigCSS {
+		fmt.Print(waybarCSS("#" + strings.ReplaceAll(waybarConfigModule, "/", "-")))
+		return nil
+	}
+
+	self := "calendar-widget"
+	if path, err := os.Executable(); err == nil {
+		self = shellQuote(path)
+	}
+
+	exec := self + " waybar"
+	interval := 60
+	if waybarConfigDisplay != widget.DisplayNext {
+		exec += " --display " + waybarConfigDisplay
+	}
+	if waybarConfigDisplay == widget.DisplayCountdown {
+		interval = 1
+	}
+
+	module := waybarModule{
+		Exec:          exec,
+		ReturnType:    "json",
+		Interval:      interval,
+		Signal:        waybarConfigSignal,
+		Tooltip:       true,
+		OnClick:       self + " click --button left",
+		OnClickMiddle: self + " click --button middle",
+		OnClickRight:  self + " click --button right",
+		OnScrollUp:    self + " click --button scroll-up",
+		OnScrollDown:  self + " click --button scroll-down",
+	}
+
+	data, err := json.MarshalIndent(module, "    ", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal module: %w", err)
+	}
+
+	fmt.Printf("    %q: %s\n", waybarConfigModule, data)
+	fmt.Fprintf(os.Stderr, "Add %q to one of your modules-left/center/right lists.\n", waybarConfigModule)
+	return nil
+}
+
+// waybarCSS returns a stylesheet for every class the widget emits
+func waybarCSS(selector string) string {
+	return strings.ReplaceAll(`/* calendar-widget */
+SELECTOR {
+    padding: 0 10px;
+    margin: 0 5px;
+    border-radius: 5px;
+}
+
+/* Meeting status */
+SELECTOR.current {
+    background-color: #44ff44;
+    color: #000000;
+}
+
+SELECTOR.urgent {
+    background-color: #ff4444;
+    color: #ffffff;
+    animation: calendar-pulse 1s infinite;
+}
+
+SELECTOR.soon {
+    background-color: #ffaa00;
+    color: #000000;
+}
+
+SELECTOR.upcoming {
+    background-color: #4488ff;
+    color: #ffffff;
+}
+
+SELECTOR.past,
+SELECTOR.no-meeting,
+SELECTOR.muted {
+    background-color: transparent;
+    color: #888888;
+}
+
+/* Free slot display */
+SELECTOR.free {
+    color: #44ff44;
+}
+
+SELECTOR.busy {
+    color: #ffaa00;
+}
+
+/* Problems */
+SELECTOR.error {
+    background-color: #ff0000;
+    color: #ffffff;
+}
+
+SELECTOR.rate-limited {
+    background-color: #aa6600;
+    color: #ffffff;
+}
+
+/* The displayed meeting overlaps another blocking meeting */
+SELECTOR.conflict {
+    border-top: 2px solid #ff5555;
+}
+
+/* Outlook category colors, e.g. cat-red, cat-blue, cat-green */
+SELECTOR.cat-red {
+    border-bottom: 2px solid #e74856;
+}
+
+SELECTOR.cat-blue {
+    border-bottom: 2px solid #00bcf2;
+}
+
+SELECTOR.cat-green {
+    border-bottom: 2px solid #47d041;
+}
+
+@keyframes calendar-pulse {
+    0% { opacity: 1; }
+    50% { opacity: 0.7; }
+    100% { opacity: 1; }
+}
+`, "SELECTOR", selector)
+}
+
+// shellQuote quotes s for sh, which waybar runs exec and on-click through,
+// unless it only has characters that are safe unquoted
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./+=:@%,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	waybarConfigCmd.Flags().BoolVar(&waybarConfigCSS, "css", false, "print a matching style.css instead of the module")
+	waybarConfigCmd.Flags().StringVar(&waybarConfigModule, "module", "custom/calendar-widget", "waybar module name")
+	waybarConfigCmd.Flags().StringVar(&waybarConfigDisplay, "display", widget.DisplayNext, "display mode used by the module (next|remaining|count|freeslot|countdown|multi)")
+	waybarConfigCmd.Flags().IntVar(&waybarConfigSignal, "signal", 8, "RTMIN+N signal that triggers a redraw (0 to disable)")
+	rootCmd.AddCommand(waybarConfigCmd)
+}
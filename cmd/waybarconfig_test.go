@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "/usr/bin/calendar-widget", want: "/usr/bin/calendar-widget"},
+		{in: "/home/ada/My Apps/calendar-widget", want: "'/home/ada/My Apps/calendar-widget'"},
+		{in: "/opt/it's/calendar-widget", want: `'/opt/it'\''s/calendar-widget'`},
+		{in: "/opt/$HOME;rm -rf/calendar-widget", want: "'/opt/$HOME;rm -rf/calendar-widget'"},
+		{in: "", want: "''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := shellQuote(tt.in)
+			if got != tt.want {
+				t.Errorf("shellQuote(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			if runtime.GOOS == "windows" {
+				return
+			}
+			// sh must see the original string as one word
+			out, err := exec.Command("sh", "-c", "printf %s "+got).Output()
+			if err != nil || string(out) != tt.in {
+				t.Errorf("sh read %q (%v), want %q", out, err, tt.in)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	whenJSON bool
+	whenDays int
+)
+
+var whenCmd = &cobra.Command{
+	Use:   "when <query>",
+	Short: "Show when the next meeting matching a query is",
+	Long: `Find the next meeting whose subject, organizer or attendees match the query and print when it is,
+e.g. calendar-widget when "1:1 with Anna". Matching forgives case, partial words and one typo per word.
+Exits with an error when nothing in the next --days matches.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runWhen(strings.Join(args, " ")); err != nil {
+			fmt.Fprintf(os.Stderr, "When failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// whenResult is the JSON shape printed by `when --json`
+type whenResult struct {
+	listedEvent
+	// StartsIn is the number of minutes until the start, 0 once running
+	StartsIn int `json:"starts_in_minutes"`
+}
+
+func runWhen(query string) error {
+	calendarService, err := newCalendarProvider(false, false)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar service: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := calendar.Now()
+	events, err := calendarService.GetEventsBetween(ctx, now, now.AddDate(0, 0, whenDays))
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+
+	event := calendar.FindNextMatch(events, query, now)
+	if event == nil {
+		return errors.New(i18n.T("No meeting in the next %d days matches %q", whenDays, query))
+	}
+
+	startsIn := max(event.Start.Sub(now), 0)
+	if whenJSON {
+		data, err := json.MarshalIndent(whenResult{
+			listedEvent: newListedEvent(*event),
+			StartsIn:    int(startsIn.Minutes()),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s: %s %s-%s (%s)\n",
+		event.Subject,
+		i18n.FormatDate(event.Start),
+		i18n.FormatTime(event.Start),
+		i18n.FormatTime(event.End),
+		formatStartsIn(startsIn))
+	return nil
+}
+
+// formatStartsIn renders the time until a meeting as "now", "in 45m",
+// "in 3h10m" or, from a day on, "in 2 days"
+func formatStartsIn(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return i18n.T("now")
+	case d < time.Hour:
+		return i18n.T("in %dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return i18n.T("in %dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	case d < 48*time.Hour:
+		return i18n.T("in 1 day")
+	default:
+		return i18n.T("in %d days", int(d.Hours()/24))
+	}
+}
+
+func init() {
+	whenCmd.Flags().BoolVar(&whenJSON, "json", false, "print the meeting as JSON")
+	whenCmd.Flags().IntVar(&whenDays, "days", 30, "number of days ahead to search")
+	rootCmd.AddCommand(whenCmd)
+}
@@ -11,6 +11,7 @@ import (
 var (
 	refresh int
 	compact bool
+	layout  string
 )
 
 var widgetCmd = &cobra.Command{
@@ -26,19 +27,31 @@ var widgetCmd = &cobra.Command{
 }
 
 func runWidget() error {
-	w, err := widget.NewWidget(&widget.Config{
+	settings := loadSettings()
+
+	tuiLayout := settings.Layout.TUI
+	switch {
+	case layout != "":
+		tuiLayout = layout
+	case compact:
+		tuiLayout = widget.LayoutCompact
+	}
+	if err := widget.ValidateLayout(tuiLayout); err != nil {
+		return err
+	}
+
+	w := widget.NewWidget(&widget.Config{
 		RefreshInterval: refresh,
-		Compact:         compact,
+		Layout:          tuiLayout,
 		Debug:           debug,
+		Settings:        settings,
 	})
-	if err != nil {
-		return fmt.Errorf("failed to create widget: %w", err)
-	}
 
 	return w.Run()
 }
 
 func init() {
 	widgetCmd.Flags().IntVar(&refresh, "refresh", 60, "refresh interval in seconds")
-	widgetCmd.Flags().BoolVar(&compact, "compact", false, "use compact display mode")
+	widgetCmd.Flags().StringVar(&layout, "layout", "", "layout preset: micro (icon and minutes), compact (icon and title) or full (with start time and location)")
+	widgetCmd.Flags().BoolVar(&compact, "compact", false, "shorthand for --layout compact")
 }
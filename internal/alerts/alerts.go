@@ -0,0 +1,114 @@
+// Package alerts follows meetings through their lifecycle, such as a
+// meeting turning urgent or starting, so the daemon can act on it.
+package alerts
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+// Watcher is the lifecycle state machine of the meetings in the daemon's
+// window. It calls onChange whenever a blocking meeting moves to another
+// state. Meetings the user declined, or that don't block time, are in
+// StateNone. Meetings are not reported for the state they have when first
+// seen, so starting the daemon stays quiet; those appearing later move from
+// StateNone.
+type Watcher struct {
+	onChange func(Transition)
+
+	mu      sync.Mutex
+	events  []calendar.Event
+	states  map[string]string
+	started bool
+	// left holds transitions of meetings that left the window before a
+	// check saw them change: running meetings that ended, and meetings that
+	// were removed
+	left []Transition
+}
+
+func NewWatcher(onChange func(Transition)) *Watcher {
+	return &Watcher{
+		onChange: onChange,
+		states:   make(map[string]string),
+	}
+}
+
+// Update replaces the set of events the watcher follows
+func (w *Watcher) Update(events []calendar.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Forget meetings that have dropped out of the window
+	states := make(map[string]string)
+	for _, event := range events {
+		key := eventKey(event)
+		if state, ok := w.states[key]; ok {
+			states[key] = state
+		} else if w.started {
+			states[key] = StateNone
+		}
+	}
+	for _, event := range w.events {
+		key := eventKey(event)
+		if _, kept := states[key]; kept {
+			continue
+		}
+		switch state := w.states[key]; {
+		case state == calendar.StatusCurrent && event.GetStatus() == calendar.StatusPast:
+			w.left = append(w.left, Transition{Event: event, From: state, To: calendar.StatusPast})
+		case calendar.IsPending(state) && event.GetStatus() != calendar.StatusPast:
+			w.left = append(w.left, Transition{Event: event, From: state, To: StateNone})
+		}
+	}
+	w.events = events
+	w.states = states
+	w.started = true
+}
+
+// Run checks every second for transitions until ctx is done
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, transition := range w.step() {
+				w.onChange(transition)
+			}
+		}
+	}
+}
+
+// step moves every meeting to its current state and returns the
+// transitions since the last step
+func (w *Watcher) step() []Transition {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	transitions := w.left
+	w.left = nil
+
+	for _, event := range w.events {
+		key := eventKey(event)
+		state := StateNone
+		if event.IsBlockingEvent() && !event.IsDeclined() {
+			state = event.GetStatus()
+		}
+		previous, seen := w.states[key]
+		w.states[key] = state
+		if seen && state != previous {
+			transitions = append(transitions, Transition{Event: event, From: previous, To: state})
+		}
+	}
+	return transitions
+}
+
+func eventKey(event calendar.Event) string {
+	return event.Subject + "|" + event.Start.Format(time.RFC3339)
+}
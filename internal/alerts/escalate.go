@@ -0,0 +1,55 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+// Escalation decides how loudly a meeting's start is announced. At the
+// keyboard the user gets one silent notification; while idle the
+// notification repeats, with a sound, until they are back or the meeting
+// has been running for RepeatFor, or its reminder is dismissed.
+type Escalation struct {
+	Idle func() bool
+	// Notify shows the start notification; loud is set while escalating
+	Notify func(event calendar.Event, loud bool)
+	// Sound plays the alert sound, if any
+	Sound func(event calendar.Event)
+	// Dismissed reports whether the event's reminder has been dismissed
+	Dismissed   func(event calendar.Event) bool
+	RepeatEvery time.Duration
+	RepeatFor   time.Duration
+}
+
+// Start announces event, escalating while the user is idle. It blocks
+// until the escalation ends or ctx is done.
+func (e Escalation) Start(ctx context.Context, event calendar.Event) {
+	if !e.Idle() {
+		e.Notify(event, false)
+		return
+	}
+
+	ticker := time.NewTicker(e.RepeatEvery)
+	defer ticker.Stop()
+
+	stopAt := event.Start.Add(e.RepeatFor)
+	if event.End.Before(stopAt) {
+		stopAt = event.End
+	}
+
+	for {
+		e.Notify(event, true)
+		e.Sound(event)
+
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !e.Idle() || !now.Before(stopAt) || e.Dismissed(event) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package alerts
+
+import (
+	"slices"
+
+	"calendar-widget/internal/calendar"
+)
+
+// StateNone is the state of a meeting the daemon doesn't follow: before it
+// shows up on the calendar, and after it is removed, cancelled or declined
+const StateNone = "none"
+
+// Lifecycle lists the states a meeting goes through, in order: none, then
+// the meeting statuses from upcoming to past. A meeting normally only moves
+// forward, possibly skipping states while the machine slept; moving it to
+// a later time can send it back.
+var Lifecycle = []string{
+	StateNone,
+	calendar.StatusUpcoming,
+	calendar.StatusSoon,
+	calendar.StatusUrgent,
+	calendar.StatusCurrent,
+	calendar.StatusPast,
+}
+
+// Transition is a meeting moving from one lifecycle state to another
+type Transition struct {
+	Event calendar.Event
+	From  string
+	To    string
+}
+
+// IsState reports whether state is one of the Lifecycle states
+func IsState(state string) bool {
+	return slices.Contains(Lifecycle, state)
+}
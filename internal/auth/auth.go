@@ -5,12 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"calendar-widget/internal/xdg"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
 )
 
 const (
@@ -22,12 +27,105 @@ const (
 	RedirectURI = "http://localhost:12345/auth/callback"
 )
 
+// Scopes requested for Microsoft Graph. Optional features and commands that
+// write to the calendar add the scopes they need with AddScope.
+var Scopes = []string{
+	"https://graph.microsoft.com/Calendars.Read",
+	"https://graph.microsoft.com/User.Read",
+}
+
+// AddScope requests scope as well from now on, for features that need a
+// permission not everyone can consent to. Silent sign-in fails until it has
+// been granted, so the next interactive sign-in asks for consent.
+func AddScope(scope string) {
+	for _, existing := range Scopes {
+		if existing == scope {
+			return
+		}
+	}
+	Scopes = append(Scopes, scope)
+}
+
 type Config struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret,omitempty"`
 	TenantID     string `json:"tenant_id"`
 	RedirectURI  string `json:"redirect_uri"`
 	UsePublic    bool   `json:"use_public_client"`
+	// UseDeviceCode signs in with the device code flow instead of a local
+	// browser, for headless machines
+	UseDeviceCode bool `json:"use_device_code,omitempty"`
+	// Provider selects the calendar backend: "graph" (default) or "ews"
+	Provider string `json:"provider,omitempty"`
+	// EWS configures Exchange Web Services for on-premises Exchange
+	EWS *EWSConfig `json:"ews,omitempty"`
+	// AccountType overrides account type detection: "personal" or "work"
+	AccountType string `json:"account_type,omitempty"`
+	// GuestTenants are other Azure AD tenants the account is a guest in;
+	// their calendars are merged with the home tenant's
+	GuestTenants []GuestTenant `json:"guest_tenants,omitempty"`
+	// TokenEncryption chooses how cached tokens are encrypted: "auto"
+	// (default), "keyring", "machine-id", "passphrase" or "none"
+	TokenEncryption string `json:"token_encryption,omitempty"`
+	// TokenPassphraseCommand prints the passphrase for "passphrase"
+	// encryption, e.g. "pass show calendar-widget"
+	TokenPassphraseCommand string `json:"token_passphrase_command,omitempty"`
+}
+
+// Microsoft account types. Personal (Outlook.com, Hotmail, Live) accounts
+// all sign in through the consumer tenant.
+const (
+	AccountTypePersonal = "personal"
+	AccountTypeWork     = "work"
+	ConsumerTenantID    = "9188040d-6c67-4c5b-b112-36a5d9a5b0bd"
+)
+
+// GuestTenant is another tenant to read the signed-in account's calendar from
+type GuestTenant struct {
+	// TenantID is the tenant's ID or domain, e.g. partner.onmicrosoft.com
+	TenantID string `json:"tenant_id"`
+	// Name labels the tenant's events, e.g. "Partner"; defaults to TenantID
+	Name string `json:"name,omitempty"`
+}
+
+// Label returns the name shown for the tenant's events
+func (t GuestTenant) Label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.TenantID
+}
+
+// Calendar backends
+const (
+	ProviderGraph = "graph"
+	ProviderEWS   = "ews"
+)
+
+// EWSConfig holds the endpoint and credentials for on-premises Exchange
+type EWSConfig struct {
+	// URL is the EWS endpoint, e.g. https://mail.example.com/EWS/Exchange.asmx
+	URL string `json:"url"`
+	// Username is "DOMAIN\user" or "user@example.com"
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	// PasswordCommand prints the password instead, e.g. "pass show work/exchange"
+	PasswordCommand string `json:"password_command,omitempty"`
+	// AuthType is "ntlm" (default) or "basic"
+	AuthType string `json:"auth_type,omitempty"`
+}
+
+// GetPassword returns the configured password, running PasswordCommand if set
+func (c *EWSConfig) GetPassword() (string, error) {
+	if c.PasswordCommand == "" {
+		return c.Password, nil
+	}
+
+	out, err := exec.Command("sh", "-c", c.PasswordCommand).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run password command: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
 }
 
 type TokenStore struct {
@@ -38,15 +136,17 @@ type TokenStore struct {
 }
 
 func GetConfigPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".config", "calendar-widget", "config.json")
+	return xdg.ConfigPath("config.json")
 }
 
 func GetTokenPath() string {
-	homeDir, _ := os.UserHomeDir()
-	return filepath.Join(homeDir, ".config", "calendar-widget", "token.json")
+	return xdg.StatePath("token.json")
 }
 
+// corruptConfigWarning warns about a broken config.json once per process,
+// though it is loaded for every token
+var corruptConfigWarning sync.Once
+
 func LoadConfig() (*Config, error) {
 	configPath := GetConfigPath()
 	data, err := os.ReadFile(configPath)
@@ -65,7 +165,12 @@ func LoadConfig() (*Config, error) {
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		// A half-written or hand-broken config.json would otherwise stop
+		// every command; the defaults at least allow signing in again
+		corruptConfigWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %s, it is not valid JSON: %v\n", configPath, err)
+		})
+		config = Config{}
 	}
 
 	// Migrate old configs to use public client if not explicitly set
@@ -92,12 +197,17 @@ func SaveConfig(config *Config) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return os.WriteFile(configPath, data, 0600)
+	return writeFileAtomic(configPath, data, 0600)
 }
 
 func LoadTokenStore() (*TokenStore, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
 	tokenPath := GetTokenPath()
-	data, err := os.ReadFile(tokenPath)
+	data, err := newSealer(config).readFile(tokenPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No token file exists
@@ -107,18 +217,17 @@ func LoadTokenStore() (*TokenStore, error) {
 
 	var token TokenStore
 	if err := json.Unmarshal(data, &token); err != nil {
-		return nil, fmt.Errorf("failed to parse token: %w", err)
+		setAsideCorrupt(tokenPath, err)
+		return nil, nil
 	}
 
 	return &token, nil
 }
 
 func SaveTokenStore(token *TokenStore) error {
-	tokenPath := GetTokenPath()
-	tokenDir := filepath.Dir(tokenPath)
-
-	if err := os.MkdirAll(tokenDir, 0755); err != nil {
-		return fmt.Errorf("failed to create token directory: %w", err)
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	data, err := json.MarshalIndent(token, "", "  ")
@@ -126,7 +235,7 @@ func SaveTokenStore(token *TokenStore) error {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
-	return os.WriteFile(tokenPath, data, 0600)
+	return newSealer(config).writeFile(GetTokenPath(), data)
 }
 
 func IsTokenValid(token *TokenStore) bool {
@@ -143,7 +252,7 @@ func GetCredential() (azcore.TokenCredential, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if config.UsePublic {
+	if config.UsePublic && !config.UseDeviceCode {
 		// Use interactive browser authentication for better user experience
 		credential, err := azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
 			ClientID:    config.ClientID,
@@ -156,7 +265,7 @@ func GetCredential() (azcore.TokenCredential, error) {
 		return credential, nil
 	}
 
-	// Legacy support for custom app registrations - fallback to device code
+	// Device code flow for headless machines and legacy custom app registrations
 	credential, err := azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
 		ClientID: config.ClientID,
 		TenantID: config.TenantID,
@@ -181,56 +290,104 @@ func GetAccessTokenWithOptions(ctx context.Context, allowInteractive bool) (azco
 }
 
 func GetAccessTokenWithOptionsAndForceRefresh(ctx context.Context, allowInteractive bool, forceRefresh bool) (azcore.AccessToken, error) {
-	// Check for cached token first (unless force refresh is requested)
+	return getAccessToken(ctx, "", allowInteractive, forceRefresh)
+}
+
+// GetAccessTokenForTenant returns a token for the signed-in account in a
+// tenant it is a guest in. Signing in to the guest tenant the first time may
+// need interactive login, e.g. to accept its terms of use.
+func GetAccessTokenForTenant(ctx context.Context, tenantID string, allowInteractive bool, forceRefresh bool) (azcore.AccessToken, error) {
+	return getAccessToken(ctx, tenantID, allowInteractive, forceRefresh)
+}
+
+// getAccessToken returns a token for the home tenant, or for tenantID
+func getAccessToken(ctx context.Context, tenantID string, allowInteractive bool, forceRefresh bool) (azcore.AccessToken, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client, err := newPublicClient(config)
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+
+	// Try the MSAL cache first (unless force refresh is requested); it
+	// refreshes expired access tokens without prompting
 	if !forceRefresh {
-		tokenStore, err := LoadTokenStore()
-		if err == nil && IsTokenValid(tokenStore) {
-			return azcore.AccessToken{
-				Token:     tokenStore.AccessToken,
-				ExpiresOn: tokenStore.ExpiresAt,
-			}, nil
+		token, ok, err := cachedAccessToken(ctx, client, tenantID)
+		if err != nil {
+			return azcore.AccessToken{}, err
+		}
+		if ok {
+			return token, nil
 		}
 	}
 
 	// If not interactive and no valid cached token, return error
 	if !allowInteractive {
+		if tenantID != "" {
+			return azcore.AccessToken{}, fmt.Errorf("authentication required for tenant %s: no valid cached token and interactive login disabled", tenantID)
+		}
 		return azcore.AccessToken{}, fmt.Errorf("authentication required: no valid cached token and interactive login disabled")
 	}
 
-	// Get new token
-	credential, err := GetCredential()
+	result, err := acquireTokenInteractive(ctx, client, config, tenantID)
 	if err != nil {
-		return azcore.AccessToken{}, err
+		return azcore.AccessToken{}, fmt.Errorf("failed to get access token: %w", err)
 	}
 
-	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: []string{"https://graph.microsoft.com/Calendars.Read", "https://graph.microsoft.com/User.Read"},
-	})
+	return azcore.AccessToken{
+		Token:     result.AccessToken,
+		ExpiresOn: result.ExpiresOn,
+	}, nil
+}
+
+// cachedAccessToken returns a token from the caches without prompting. It
+// holds the token lock, so concurrent processes redeem the refresh token
+// once and the others pick up the token it got.
+func cachedAccessToken(ctx context.Context, client public.Client, tenantID string) (azcore.AccessToken, bool, error) {
+	unlock, err := lockTokens(ctx)
 	if err != nil {
-		return azcore.AccessToken{}, fmt.Errorf("failed to get access token: %w", err)
+		return azcore.AccessToken{}, false, err
 	}
+	defer unlock()
 
-	// Cache the token
-	tokenStore := &TokenStore{
-		AccessToken: token.Token,
-		ExpiresAt:   token.ExpiresOn,
-		TokenType:   "Bearer",
+	if result, err := acquireTokenSilent(ctx, client, tenantID); err == nil {
+		return azcore.AccessToken{
+			Token:     result.AccessToken,
+			ExpiresOn: result.ExpiresOn,
+		}, true, nil
 	}
 
-	if saveErr := SaveTokenStore(tokenStore); saveErr != nil {
-		fmt.Printf("Warning: failed to cache token: %v\n", saveErr)
+	// Tokens cached by older versions remain usable until they expire
+	tokenStore, err := LoadTokenStore()
+	if err == nil && tenantID == "" && IsTokenValid(tokenStore) {
+		return azcore.AccessToken{
+			Token:     tokenStore.AccessToken,
+			ExpiresOn: tokenStore.ExpiresAt,
+		}, true, nil
 	}
-
-	return token, nil
+	return azcore.AccessToken{}, false, nil
 }
 
-// ClearTokens removes stored tokens, forcing re-authentication on next use
+// ClearTokens removes stored tokens, forcing re-authentication on next use.
+// It waits for a refresh in progress, which would otherwise write the
+// tokens back.
 func ClearTokens() error {
+	ctx, cancel := context.WithTimeout(context.Background(), clearLockWait)
+	defer cancel()
+	unlock, err := lockTokens(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	tokenPath := GetTokenPath()
 	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove token file: %w", err)
 	}
-	return nil
+	return clearMSALCache()
 }
 
 // GetGraphServiceClientWithAuth returns a credential for backwards compatibility
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to a temporary file next to path, syncs it
+// and renames it over path, so a crash mid-write leaves either the old file
+// or the new one, never a truncated mix
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	// Removing fails harmlessly once the rename went through
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", filepath.Base(path), err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+
+	// Sync the directory too, so the rename itself survives a crash
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// setAsideCorrupt moves a token file that doesn't parse out of the way, so
+// it is treated as missing and the next sign-in writes a fresh one. The
+// file is kept next to the original for inspection.
+func setAsideCorrupt(path string, cause error) {
+	corruptPath := path + ".corrupt"
+	if err := os.Rename(path, corruptPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s is corrupted and could not be moved aside: %v\n", path, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s was corrupted (%v), moved it to %s; sign in again with 'calendar-widget reauth' if needed\n", path, cause, corruptPath)
+}
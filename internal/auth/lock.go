@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"calendar-widget/internal/xdg"
+)
+
+// lockPollInterval is how often a process waiting for the token lock retries
+const lockPollInterval = 50 * time.Millisecond
+
+// clearLockWait bounds how long ClearTokens waits for a refresh in progress
+const clearLockWait = 30 * time.Second
+
+// tokenTurn lets one goroutine of this process hold the token lock at a
+// time, so the others wait here instead of polling the lock file
+var tokenTurn = make(chan struct{}, 1)
+
+// GetTokenLockPath returns the lock file that serializes token refreshes
+// across processes
+func GetTokenLockPath() string {
+	return xdg.StatePath("token.lock")
+}
+
+// lockTokens takes an exclusive lock on the token lock file, waiting until
+// ctx is done. Waybar, click handlers and the daemon all refresh tokens;
+// holding the lock while reading and redeeming the cached tokens makes them
+// take turns, so a process that waited finds the token the previous one
+// refreshed instead of redeeming the refresh token again. The lock is
+// released by calling the returned function, or when the process exits.
+func lockTokens(ctx context.Context) (func(), error) {
+	select {
+	case tokenTurn <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for another token refresh: %w", ctx.Err())
+	}
+
+	file, err := openTokenLock()
+	if err != nil {
+		<-tokenTurn
+		return nil, err
+	}
+
+	for {
+		locked, err := tryLockFile(file)
+		if locked {
+			break
+		}
+		if err != nil {
+			file.Close()
+			<-tokenTurn
+			return nil, fmt.Errorf("failed to lock token store: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			<-tokenTurn
+			return nil, fmt.Errorf("timed out waiting for another token refresh: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+		<-tokenTurn
+	}, nil
+}
+
+func openTokenLock() (*os.File, error) {
+	path := GetTokenLockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create token directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token lock: %w", err)
+	}
+	return file, nil
+}
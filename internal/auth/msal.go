@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"calendar-widget/internal/network"
+	"calendar-widget/internal/xdg"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+// GetMSALCachePath returns where MSAL's serialized token cache is stored
+func GetMSALCachePath() string {
+	return xdg.StatePath("msal_cache.json")
+}
+
+// fileCache persists MSAL's token cache (accounts, refresh tokens and access
+// tokens for every scope set) so silent sign-in survives restarts and
+// access token expiry. The file is encrypted per token_encryption.
+type fileCache struct {
+	path   string
+	sealer *sealer
+}
+
+func (fc *fileCache) Replace(ctx context.Context, c cache.Unmarshaler, hints cache.ReplaceHints) error {
+	data, err := fc.sealer.readFile(fc.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read token cache: %w", err)
+	}
+	if err := c.Unmarshal(data); err != nil {
+		setAsideCorrupt(fc.path, err)
+	}
+	return nil
+}
+
+func (fc *fileCache) Export(ctx context.Context, c cache.Marshaler, hints cache.ExportHints) error {
+	data, err := c.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to serialize token cache: %w", err)
+	}
+
+	return fc.sealer.writeFile(fc.path, data)
+}
+
+func newPublicClient(config *Config) (public.Client, error) {
+	client, err := public.New(config.ClientID,
+		public.WithAuthority("https://login.microsoftonline.com/"+config.TenantID),
+		public.WithCache(&fileCache{path: GetMSALCachePath(), sealer: newSealer(config)}),
+		public.WithHTTPClient(network.Client()),
+	)
+	if err != nil {
+		return public.Client{}, fmt.Errorf("failed to create public client: %w", err)
+	}
+	return client, nil
+}
+
+// acquireTokenSilent returns a cached access token for the first signed-in
+// account, redeeming its refresh token when the access token has expired.
+// tenantID selects a guest tenant; "" is the account's own.
+func acquireTokenSilent(ctx context.Context, client public.Client, tenantID string) (public.AuthResult, error) {
+	accounts, err := client.Accounts(ctx)
+	if err != nil {
+		return public.AuthResult{}, fmt.Errorf("failed to read cached accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return public.AuthResult{}, fmt.Errorf("no cached account")
+	}
+
+	options := []public.AcquireSilentOption{public.WithSilentAccount(accounts[0])}
+	if tenantID != "" {
+		options = append(options, public.WithTenantID(tenantID))
+	}
+	return client.AcquireTokenSilent(ctx, Scopes, options...)
+}
+
+// acquireTokenInteractive signs in with the browser, or with a device code
+// for headless machines and custom app registrations
+func acquireTokenInteractive(ctx context.Context, client public.Client, config *Config, tenantID string) (public.AuthResult, error) {
+	if config.UseDeviceCode || !config.UsePublic {
+		var options []public.AcquireByDeviceCodeOption
+		if tenantID != "" {
+			options = append(options, public.WithTenantID(tenantID))
+		}
+		deviceCode, err := client.AcquireTokenByDeviceCode(ctx, Scopes, options...)
+		if err != nil {
+			return public.AuthResult{}, fmt.Errorf("failed to start device code sign-in: %w", err)
+		}
+		fmt.Println(deviceCode.Result.Message)
+		return deviceCode.AuthenticationResult(ctx)
+	}
+
+	options := []public.AcquireInteractiveOption{public.WithRedirectURI(config.RedirectURI)}
+	if tenantID != "" {
+		options = append(options, public.WithTenantID(tenantID))
+	}
+	return client.AcquireTokenInteractive(ctx, Scopes, options...)
+}
+
+// clearMSALCache removes the MSAL token cache
+func clearMSALCache() error {
+	if err := os.Remove(GetMSALCachePath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token cache: %w", err)
+	}
+	return nil
+}
+
+// SignedInAccount returns the username of the account in the MSAL cache, or
+// "" when nobody has signed in yet
+func SignedInAccount(ctx context.Context) (string, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client, err := newPublicClient(config)
+	if err != nil {
+		return "", err
+	}
+
+	accounts, err := client.Accounts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return "", nil
+	}
+	return accounts[0].PreferredUsername, nil
+}
+
+// DetectAccountType reports whether the signed-in account is a personal
+// Microsoft account or a work/school one, honouring the account_type
+// override in config.json. It only reads the token cache, never the network.
+func DetectAccountType(ctx context.Context) (string, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.AccountType != "" {
+		return config.AccountType, nil
+	}
+
+	client, err := newPublicClient(config)
+	if err != nil {
+		return "", err
+	}
+
+	accounts, err := client.Accounts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return "", fmt.Errorf("no cached account")
+	}
+
+	if accounts[0].Realm == ConsumerTenantID {
+		return AccountTypePersonal, nil
+	}
+	return AccountTypeWork, nil
+}
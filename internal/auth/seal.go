@@ -0,0 +1,361 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Token cache encryption, set with token_encryption in config.json
+const (
+	// EncryptionAuto uses the keyring, else the machine ID, else stores
+	// tokens in plain text as before. It is the default.
+	EncryptionAuto = "auto"
+	// EncryptionKeyring keeps a random key in the Secret Service keyring
+	// (secret-tool) or the macOS keychain
+	EncryptionKeyring = "keyring"
+	// EncryptionMachineID derives the key from /etc/machine-id and the user
+	// ID. It keeps the cache unreadable when copied to another machine.
+	EncryptionMachineID = "machine-id"
+	// EncryptionPassphrase derives the key from the output of
+	// token_passphrase_command
+	EncryptionPassphrase = "passphrase"
+	// EncryptionNone stores tokens in plain text
+	EncryptionNone = "none"
+)
+
+const (
+	keyringService = "calendar-widget"
+	keyringAccount = "token-cache"
+	// sealInfo binds derived keys to this use
+	sealInfo = "calendar-widget token cache"
+	// passphraseIterations is the PBKDF2-SHA256 work factor
+	passphraseIterations = 600000
+)
+
+// sealedFile is the on-disk form of an encrypted token file. Plain-text
+// caches from older versions are JSON without "sealed", which is how they
+// are told apart and migrated.
+type sealedFile struct {
+	Sealed int    `json:"sealed"`
+	Key    string `json:"key"`
+	Salt   []byte `json:"salt"`
+	Nonce  []byte `json:"nonce"`
+	Data   []byte `json:"data"`
+}
+
+// sealer reads and writes token files encrypted according to config
+type sealer struct {
+	mode              string
+	passphraseCommand string
+}
+
+func newSealer(config *Config) *sealer {
+	mode := config.TokenEncryption
+	if mode == "" {
+		mode = EncryptionAuto
+	}
+	return &sealer{mode: mode, passphraseCommand: config.TokenPassphraseCommand}
+}
+
+// readFile returns the decrypted contents of path. A plain-text file is
+// returned as is and rewritten encrypted, unless encryption is off; a file
+// encrypted with another key than token_encryption asks for is re-encrypted.
+func (s *sealer) readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sealed sealedFile
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		// Not JSON at all, e.g. cut short by a crash; the caller's parse
+		// reports it, so it is not sealed as if it were valid
+		return data, nil
+	}
+	if sealed.Sealed == 0 {
+		if s.mode != EncryptionNone {
+			// Best effort: without a usable key the file stays as it was
+			_ = s.writeFile(path, data)
+		}
+		return data, nil
+	}
+
+	if sealed.Sealed != 1 {
+		return nil, fmt.Errorf("unsupported token file version %d", sealed.Sealed)
+	}
+	key, err := s.key(sealed.Key, sealed.Salt, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s key for token file: %w", sealed.Key, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, sealed.Nonce, sealed.Data, []byte(sealed.Key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token file, sign in again with 'calendar-widget reauth': %w", err)
+	}
+	if s.mode != EncryptionAuto && s.mode != sealed.Key {
+		// token_encryption changed since the file was written
+		_ = s.writeFile(path, plain)
+	}
+	return plain, nil
+}
+
+// writeFile encrypts data with the first available key and writes it to
+// path. It writes plain text when encryption is off or, in auto mode, when
+// no key source is available.
+func (s *sealer) writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	source, salt, key, err := s.sealingKey()
+	if err != nil {
+		return err
+	}
+	if source == EncryptionNone {
+		return writeFileAtomic(path, data, 0600)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed, err := json.Marshal(sealedFile{
+		Sealed: 1,
+		Key:    source,
+		Salt:   salt,
+		Nonce:  nonce,
+		Data:   aead.Seal(nil, nonce, data, []byte(source)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal token file: %w", err)
+	}
+	return writeFileAtomic(path, sealed, 0600)
+}
+
+// sealingKey picks the key source for a write and derives a key with a
+// fresh salt
+func (s *sealer) sealingKey() (string, []byte, []byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	switch s.mode {
+	case EncryptionNone:
+		return EncryptionNone, nil, nil, nil
+	case EncryptionAuto:
+		for _, source := range []string{EncryptionKeyring, EncryptionMachineID} {
+			if key, err := s.key(source, salt, true); err == nil {
+				return source, salt, key, nil
+			}
+		}
+		return EncryptionNone, nil, nil, nil
+	case EncryptionKeyring, EncryptionMachineID, EncryptionPassphrase:
+		key, err := s.key(s.mode, salt, true)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to get %s key for token file: %w", s.mode, err)
+		}
+		return s.mode, salt, key, nil
+	}
+	return "", nil, nil, fmt.Errorf("unknown token_encryption %q, expected auto, keyring, machine-id, passphrase or none", s.mode)
+}
+
+// TokenCacheEncryption reports which key the MSAL token cache is encrypted
+// with: "keyring", "machine-id" or "passphrase", "none" for plain text, or ""
+// when there is no cache yet
+func TokenCacheEncryption() string {
+	data, err := os.ReadFile(GetMSALCachePath())
+	if err != nil {
+		return ""
+	}
+	var sealed sealedFile
+	if json.Unmarshal(data, &sealed) != nil || sealed.Sealed == 0 {
+		return EncryptionNone
+	}
+	return sealed.Key
+}
+
+// derivedKeys caches keys per source and salt, since every MSAL cache read
+// decrypts and the passphrase key is slow to derive on purpose
+var (
+	derivedKeysMu sync.Mutex
+	derivedKeys   = map[string][]byte{}
+)
+
+// key derives the file key for source and salt. create lets the keyring
+// source generate its key on first use.
+func (s *sealer) key(source string, salt []byte, create bool) ([]byte, error) {
+	cacheKey := source + ":" + base64.StdEncoding.EncodeToString(salt)
+	derivedKeysMu.Lock()
+	defer derivedKeysMu.Unlock()
+	if key, ok := derivedKeys[cacheKey]; ok {
+		return key, nil
+	}
+
+	var key []byte
+	switch source {
+	case EncryptionKeyring:
+		secret, err := keyringSecret(create)
+		if err != nil {
+			return nil, err
+		}
+		key, err = hkdf.Key(sha256.New, secret, salt, sealInfo, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+	case EncryptionMachineID:
+		machineID, err := machineID()
+		if err != nil {
+			return nil, err
+		}
+		secret := machineID + ":" + strconv.Itoa(os.Getuid())
+		key, err = hkdf.Key(sha256.New, []byte(secret), salt, sealInfo, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+	case EncryptionPassphrase:
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, err
+		}
+		key, err = pbkdf2.Key(sha256.New, passphrase, salt, passphraseIterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown key source %q", source)
+	}
+
+	derivedKeys[cacheKey] = key
+	return key, nil
+}
+
+// passphrase runs token_passphrase_command, e.g. "pass show calendar-widget"
+func (s *sealer) passphrase() (string, error) {
+	if s.passphraseCommand == "" {
+		return "", fmt.Errorf("token_passphrase_command is not set")
+	}
+	out, err := exec.Command("sh", "-c", s.passphraseCommand).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run token passphrase command: %w", err)
+	}
+	passphrase := strings.TrimRight(string(out), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("token passphrase command printed nothing")
+	}
+	return passphrase, nil
+}
+
+// keyringSecret returns the random key kept in the keyring, storing a new
+// one when create is set and there is none yet
+func keyringSecret(create bool) ([]byte, error) {
+	encoded, err := keyringLookup()
+	if err != nil {
+		return nil, err
+	}
+	if encoded == "" {
+		if !create {
+			return nil, fmt.Errorf("no token key in the keyring")
+		}
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		encoded = base64.StdEncoding.EncodeToString(secret)
+		if err := keyringStore(encoded); err != nil {
+			return nil, err
+		}
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode keyring key: %w", err)
+	}
+	return secret, nil
+}
+
+// keyringLookup returns the stored key, or "" if there is none
+func keyringLookup() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		// Both tools exit non-zero with no output when the item is missing
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read keyring: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func keyringStore(secret string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", keyringAccount, "-w", secret)
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label=calendar-widget token cache key", "service", keyringService, "account", keyringAccount)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store key in keyring: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// machineID reads the systemd/D-Bus machine ID
+func machineID() (string, error) {
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no machine ID found")
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,130 @@
+package autojoin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/notify"
+	"calendar-widget/internal/snooze"
+)
+
+// Joiner opens meeting join links shortly before they start. A cancellable
+// notification is shown for the grace period before the link is opened.
+type Joiner struct {
+	lead  time.Duration
+	grace time.Duration
+	open  func(calendar.Event) error
+	// redact returns an event as the notification may show it
+	redact func(calendar.Event) calendar.Event
+
+	mu      sync.Mutex
+	events  []calendar.Event
+	handled map[string]bool
+}
+
+func NewJoiner(lead, grace time.Duration, open func(calendar.Event) error, redact func(calendar.Event) calendar.Event) *Joiner {
+	return &Joiner{
+		lead:    lead,
+		grace:   grace,
+		open:    open,
+		redact:  redact,
+		handled: make(map[string]bool),
+	}
+}
+
+// Update replaces the set of events the joiner watches
+func (j *Joiner) Update(events []calendar.Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.events = events
+
+	// Forget meetings that have dropped out of the window
+	handled := make(map[string]bool)
+	for _, event := range events {
+		if key := eventKey(event); j.handled[key] {
+			handled[key] = true
+		}
+	}
+	j.handled = handled
+}
+
+// Run checks every second for meetings that are about to start until ctx is done
+func (j *Joiner) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, event := range j.due(time.Now()) {
+				go j.join(ctx, event)
+			}
+		}
+	}
+}
+
+// due returns the events whose cancel window has opened and marks them handled
+func (j *Joiner) due(now time.Time) []calendar.Event {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var due []calendar.Event
+	for _, event := range j.events {
+		if event.GetJoinLink() == "" || !event.IsBlockingEvent() {
+			continue
+		}
+		key := eventKey(event)
+		if j.handled[key] {
+			continue
+		}
+		notifyAt := event.Start.Add(-j.lead - j.grace)
+		if now.Before(notifyAt) || !now.Before(event.Start) {
+			continue
+		}
+		j.handled[key] = true
+		due = append(due, event)
+	}
+
+	return due
+}
+
+func (j *Joiner) join(ctx context.Context, event calendar.Event) {
+	if _, snoozed := snooze.Active(); snoozed {
+		return
+	}
+
+	body := fmt.Sprintf("Joining in %d seconds", int(j.grace.Seconds()))
+	subject := j.redact(event).Subject
+	cancelled, err := notify.SendWithAction(ctx, subject, body, "Cancel", j.grace)
+	if err != nil {
+		// Without a notification daemon there is no way to cancel, so don't join
+		fmt.Fprintf(os.Stderr, "Autojoin skipped for %q: %v\n", subject, err)
+		return
+	}
+	if cancelled || ctx.Err() != nil {
+		return
+	}
+
+	// Wait out any remaining grace period if the notification closed early
+	if wait := time.Until(event.Start.Add(-j.lead)); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+
+	if err := j.open(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Autojoin failed for %q: %v\n", event.Subject, err)
+	}
+}
+
+func eventKey(event calendar.Event) string {
+	return event.Subject + "|" + event.Start.Format(time.RFC3339)
+}
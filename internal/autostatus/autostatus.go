@@ -0,0 +1,167 @@
+// Package autostatus publishes whether the user is in a meeting, as an
+// "In a meeting until 14:30" status, to Slack and to a state file other
+// tools can read.
+package autostatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+)
+
+// State is what gets published. The zero State means not in a meeting.
+type State struct {
+	InMeeting bool `json:"in_meeting"`
+	// Subject is left empty for private meetings
+	Subject string    `json:"subject,omitempty"`
+	Until   time.Time `json:"until,omitzero"`
+	Text    string    `json:"text"`
+}
+
+// Sink receives the state whenever it changes
+type Sink interface {
+	Publish(ctx context.Context, state State) error
+}
+
+// Publisher follows the daemon's events and publishes to its sinks when
+// the user enters or leaves a meeting, or the meeting's end moves
+type Publisher struct {
+	sinks   []Sink
+	onError func(err error)
+
+	mu        sync.Mutex
+	events    []calendar.Event
+	publishMu sync.Mutex
+	// last is nil until the first state has been published
+	last *State
+}
+
+func NewPublisher(sinks []Sink, onError func(err error)) *Publisher {
+	return &Publisher{sinks: sinks, onError: onError}
+}
+
+// Update replaces the set of events the publisher follows
+func (p *Publisher) Update(events []calendar.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = events
+}
+
+// Run checks every second for a change of state until ctx is done
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			state := Current(p.events, calendar.Now())
+			p.mu.Unlock()
+			p.publish(ctx, state)
+		}
+	}
+}
+
+// Clear publishes that the user is not in a meeting, so a stopped daemon
+// doesn't leave the status behind
+func (p *Publisher) Clear(ctx context.Context) {
+	p.publish(ctx, State{})
+}
+
+func (p *Publisher) publish(ctx context.Context, state State) {
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+
+	if p.last != nil && *p.last == state {
+		return
+	}
+	p.last = &state
+
+	for _, sink := range p.sinks {
+		if err := sink.Publish(ctx, state); err != nil {
+			p.onError(err)
+		}
+	}
+}
+
+// Current returns the state at now: in the blocking meeting currently
+// running, until it ends or, for back-to-back meetings, until the last of
+// them ends
+func Current(events []calendar.Event, now time.Time) State {
+	var meeting *calendar.Event
+	for i := range events {
+		event := &events[i]
+		if !event.IsBlockingEvent() || event.IsDeclined() || event.IsAllDay {
+			continue
+		}
+		if event.Start.After(now) || !event.End.After(now) {
+			continue
+		}
+		if meeting == nil || event.Start.After(meeting.Start) {
+			meeting = event
+		}
+	}
+	if meeting == nil {
+		return State{}
+	}
+
+	until := meeting.End
+	for extended := true; extended; {
+		extended = false
+		for _, event := range events {
+			if !event.IsBlockingEvent() || event.IsDeclined() || event.IsAllDay {
+				continue
+			}
+			if !event.Start.After(until) && event.End.After(until) {
+				until = event.End
+				extended = true
+			}
+		}
+	}
+
+	state := State{
+		InMeeting: true,
+		Until:     until,
+		Text:      i18n.T("In a meeting until %s", i18n.FormatTime(until)),
+	}
+	if !meeting.IsPrivate() {
+		state.Subject = meeting.Subject
+	}
+	return state
+}
+
+// FileSink writes the state as JSON to a file
+type FileSink struct {
+	Path string
+}
+
+func (f FileSink) Publish(ctx context.Context, state State) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create status directory: %w", err)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal meeting status: %w", err)
+	}
+
+	// Write then rename so readers never see a partial file
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write meeting status: %w", err)
+	}
+	if err := os.Rename(tmp, f.Path); err != nil {
+		return fmt.Errorf("failed to write meeting status: %w", err)
+	}
+	return nil
+}
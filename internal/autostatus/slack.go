@@ -0,0 +1,122 @@
+package autostatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"calendar-widget/internal/network"
+)
+
+const slackProfileURL = "https://slack.com/api/users.profile.set"
+
+// SlackSink sets the user's Slack status with a user token (users.profile.write
+// scope) or posts the state to a Slack workflow webhook. It only clears a
+// status it set itself, so a status set by hand survives the daemon starting.
+type SlackSink struct {
+	Token   string
+	Webhook string
+	Emoji   string
+
+	set bool
+}
+
+func (s *SlackSink) Publish(ctx context.Context, state State) error {
+	if !state.InMeeting && !s.set {
+		return nil
+	}
+
+	var err error
+	if s.Token != "" {
+		err = s.setProfile(ctx, state)
+	} else {
+		err = s.postWebhook(ctx, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.set = state.InMeeting
+	return nil
+}
+
+func (s *SlackSink) setProfile(ctx context.Context, state State) error {
+	profile := map[string]any{
+		"status_text":       "",
+		"status_emoji":      "",
+		"status_expiration": 0,
+	}
+	if state.InMeeting {
+		profile["status_text"] = state.Text
+		profile["status_emoji"] = s.Emoji
+		// Slack clears the status itself if the daemon isn't around to
+		profile["status_expiration"] = state.Until.Unix()
+	}
+
+	resp, err := s.post(ctx, slackProfileURL, map[string]any{"profile": profile}, "Bearer "+s.Token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse Slack response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("failed to set Slack status: %s", result.Error)
+	}
+	return nil
+}
+
+func (s *SlackSink) postWebhook(ctx context.Context, state State) error {
+	payload := map[string]any{
+		"in_meeting":   state.InMeeting,
+		"status_text":  state.Text,
+		"status_emoji": "",
+		"until":        "",
+	}
+	if state.InMeeting {
+		payload["status_emoji"] = s.Emoji
+		payload["until"] = state.Until.Format(time.RFC3339)
+	}
+
+	resp, err := s.post(ctx, s.Webhook, payload, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *SlackSink) post(ctx context.Context, url string, payload any, authorization string) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Slack request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := network.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Slack: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to reach Slack: %s", resp.Status)
+	}
+	return resp, nil
+}
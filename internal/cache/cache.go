@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/xdg"
+)
+
+// Snapshot is the last calendar state written by the daemon. Readers such as
+// the countdown display use it so that they never have to hit the API.
+type Snapshot struct {
+	UpdatedAt      time.Time        `json:"updated_at"`
+	TodaysEvents   []calendar.Event `json:"todays_events"`
+	UpcomingEvents []calendar.Event `json:"upcoming_events"`
+	// Delta lets the next refresh fetch only what changed
+	Delta *calendar.DeltaState `json:"delta,omitempty"`
+	// Failures counts the refreshes that failed since this snapshot was taken
+	Failures *Failures `json:"failures,omitempty"`
+	// Moved are the events this refresh found at another time or location
+	// than the previous snapshot had them, and Added the ones the previous
+	// snapshot didn't have. They are not saved.
+	Moved []calendar.Event `json:"-"`
+	Added []calendar.Event `json:"-"`
+}
+
+// changeBadgeFor is how long an event stays marked as updated after it was
+// moved
+const changeBadgeFor = 24 * time.Hour
+
+// Failures records refreshes that failed in a row, so readers can tell why
+// the snapshot is getting old
+type Failures struct {
+	Count int `json:"count"`
+	// Last is a short summary of the last error, e.g. "401" or "offline"
+	Last   string    `json:"last"`
+	LastAt time.Time `json:"last_at"`
+}
+
+func GetCachePath() string {
+	return xdg.CachePath("events.json")
+}
+
+func Load() (*Snapshot, error) {
+	data, err := os.ReadFile(GetCachePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // No cache written yet
+		}
+		return nil, fmt.Errorf("failed to read event cache: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse event cache: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+func Save(snapshot *Snapshot) error {
+	cachePath := GetCachePath()
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event cache: %w", err)
+	}
+
+	return os.WriteFile(cachePath, data, 0600)
+}
+
+// Refresh syncs today's events and those in the lookahead window and writes them to the
+// cache. After the first sync of a day only changes are requested from Graph.
+func Refresh(ctx context.Context, provider calendar.CalendarProvider) (*Snapshot, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	windowEnd := startOfDay.AddDate(0, 0, calendar.LookaheadDays()+1)
+
+	previous, _ := Load()
+	delta, err := syncWindow(ctx, provider, previous, startOfDay, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+	moved, added := trackChanges(previous, delta, now)
+
+	snapshot := &Snapshot{
+		UpdatedAt:      now,
+		TodaysEvents:   delta.EventsBetween(startOfDay, startOfDay.Add(24*time.Hour)),
+		UpcomingEvents: delta.EventsBetween(now, now.Add(calendar.Lookahead())),
+		Delta:          delta,
+		Moved:          moved,
+		Added:          added,
+	}
+
+	if err := Save(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// syncWindow fetches the window incrementally when the provider supports
+// delta sync, and in full otherwise
+func syncWindow(ctx context.Context, provider calendar.CalendarProvider, previous *Snapshot, windowStart, windowEnd time.Time) (*calendar.DeltaState, error) {
+	if syncer, ok := provider.(calendar.DeltaSyncer); ok {
+		var state *calendar.DeltaState
+		if previous != nil {
+			state = previous.Delta
+		}
+		return syncer.SyncDelta(ctx, state, windowStart, windowEnd)
+	}
+
+	events, err := provider.GetEventsBetween(ctx, windowStart, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &calendar.DeltaState{
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+		Events:      make(map[string]calendar.Event, len(events)),
+	}
+	for _, event := range events {
+		state.Events[event.ID] = event
+	}
+	return state, nil
+}
+
+// trackChanges compares the synced events with the previous snapshot's and
+// marks the ones that were moved, keeping earlier marks for changeBadgeFor.
+// It returns the events moved and added since the previous snapshot. Events
+// are only reported as added when the previous snapshot covered the same
+// window, so a new day or a longer lookahead doesn't count as new meetings.
+func trackChanges(previous *Snapshot, delta *calendar.DeltaState, now time.Time) ([]calendar.Event, []calendar.Event) {
+	var before map[string]calendar.Event
+	sameWindow := false
+	if previous != nil && previous.Delta != nil {
+		before = previous.Delta.Events
+		sameWindow = previous.Delta.WindowStart.Equal(delta.WindowStart) && previous.Delta.WindowEnd.Equal(delta.WindowEnd)
+	}
+
+	var moved, added []calendar.Event
+	for key, event := range delta.Events {
+		old, seen := before[key]
+		switch {
+		case seen && calendar.Moved(old, event):
+			event.Change = &calendar.EventChange{
+				At:               now,
+				PreviousStart:    old.Start,
+				PreviousEnd:      old.End,
+				PreviousLocation: old.Location,
+			}
+			moved = append(moved, event)
+		case seen && event.Change == nil:
+			// Refetched events come without the mark
+			event.Change = old.Change
+		case !seen && sameWindow:
+			added = append(added, event)
+		}
+		if event.Change != nil && now.Sub(event.Change.At) > changeBadgeFor {
+			event.Change = nil
+		}
+		delta.Events[key] = event
+	}
+
+	for _, events := range [][]calendar.Event{moved, added} {
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].Start.Before(events[j].Start)
+		})
+	}
+	return moved, added
+}
+
+// RecordFailure adds a failed refresh to the cached snapshot. A successful
+// Refresh writes a new snapshot, which clears the count. Without a snapshot
+// there is nothing stale to explain, so nothing is recorded.
+func RecordFailure(summary string, at time.Time) (*Failures, error) {
+	snapshot, err := Load()
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	if snapshot.Failures == nil {
+		snapshot.Failures = &Failures{}
+	}
+	snapshot.Failures.Count++
+	snapshot.Failures.Last = summary
+	snapshot.Failures.LastAt = at
+
+	if err := Save(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot.Failures, nil
+}
+
+// IsStale reports whether the snapshot is older than maxAge
+func (s *Snapshot) IsStale(maxAge time.Duration) bool {
+	return s == nil || time.Since(s.UpdatedAt) > maxAge
+}
+
+// Events returns today's events and the upcoming ones as of now, so readers
+// can answer from the snapshot instead of asking the API. ok is false when
+// the snapshot is older than maxAge or its window doesn't cover today and
+// the lookahead, e.g. after midnight.
+func (s *Snapshot) Events(now time.Time, maxAge time.Duration) (todays, upcoming []calendar.Event, ok bool) {
+	if s.IsStale(maxAge) || s.Delta == nil {
+		return nil, nil, false
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	upcomingEnd := now.Add(calendar.Lookahead())
+	if !s.Delta.WindowStart.Equal(startOfDay) || s.Delta.WindowEnd.Before(upcomingEnd) {
+		return nil, nil, false
+	}
+
+	todays = s.Delta.EventsBetween(startOfDay, startOfDay.Add(24*time.Hour))
+	upcoming = s.Delta.EventsBetween(now, upcomingEnd)
+	return todays, upcoming, true
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+// Intervals bound the daemon's adaptive refresh schedule
+type Intervals struct {
+	// Base is used while refreshes fail, and Min and Max are widened to
+	// include it
+	Base time.Duration
+	// Min is used once a meeting is soon, so the countdown and urgency
+	// follow late changes closely
+	Min time.Duration
+	// Max is the longest wait during free stretches
+	Max time.Duration
+}
+
+// NextRefresh returns how long to wait before refreshing again. Once a
+// blocking meeting is within calendar.SoonWithin of starting (or of leaving
+// for it) refreshes come every Min; before that they are timed to land just
+// as it turns soon. Through long free stretches the wait grows up to Max.
+func (s *Snapshot) NextRefresh(now time.Time, intervals Intervals) time.Duration {
+	minInterval := min(intervals.Min, intervals.Base)
+	maxInterval := max(intervals.Max, intervals.Base)
+	if s == nil {
+		return intervals.Base
+	}
+
+	var next *calendar.Event
+	for i, event := range s.UpcomingEvents {
+		if !event.IsBlockingEvent() || event.IsDeclined() || !event.Start.After(now) {
+			continue
+		}
+		if next == nil || event.LeaveBy().Before(next.LeaveBy()) {
+			next = &s.UpcomingEvents[i]
+		}
+	}
+	if next == nil {
+		return maxInterval
+	}
+
+	untilSoon := next.LeaveBy().Sub(now) - calendar.SoonWithin
+	if untilSoon <= minInterval {
+		return minInterval
+	}
+	return min(untilSoon, maxInterval)
+}
@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// Attachment kinds, from the attachment's @odata.type
+const (
+	AttachmentFile      = "file"
+	AttachmentItem      = "item"
+	AttachmentReference = "reference"
+)
+
+// Attachment describes a file attached to an event. Only file attachments
+// can be downloaded; items are attached Outlook messages or events and
+// references are cloud links.
+type Attachment struct {
+	ID          string
+	Name        string
+	ContentType string
+	Size        int
+	Inline      bool
+	Kind        string
+}
+
+// AttachmentFetcher is implemented by providers that can list and download
+// event attachments
+type AttachmentFetcher interface {
+	ListAttachments(ctx context.Context, eventID string) ([]Attachment, error)
+	DownloadAttachment(ctx context.Context, eventID, attachmentID string) ([]byte, error)
+}
+
+// documentHosts are the sites whose links in an invite are shared documents
+// rather than join or map links
+var documentHosts = []string{
+	"sharepoint.com",
+	"onedrive.live.com",
+	"1drv.ms",
+	"docs.google.com",
+	"drive.google.com",
+	"dropbox.com",
+	"box.com",
+	"notion.so",
+	"atlassian.net",
+}
+
+var documentURLRegex = regexp.MustCompile(`https://[^\s<>"']+`)
+
+// DocumentLinks returns the links to shared documents in the event's body,
+// in order and without duplicates
+func (e *Event) DocumentLinks() []string {
+	var links []string
+	for _, match := range documentURLRegex.FindAllString(e.BodyText, -1) {
+		link := strings.TrimRight(match, ".,:;!?)")
+		u, err := url.Parse(link)
+		if err != nil || !isDocumentHost(u.Hostname()) || slices.Contains(links, link) {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func isDocumentHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, documentHost := range documentHosts {
+		if host == documentHost || strings.HasSuffix(host, "."+documentHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDocuments reports whether the event has attachments or links to shared
+// documents
+func (e *Event) HasDocuments() bool {
+	return e.HasAttachments || len(e.DocumentLinks()) > 0
+}
+
+// ListAttachments returns the attachments of an event, without their content
+func (cs *CalendarService) ListAttachments(ctx context.Context, eventID string) ([]Attachment, error) {
+	requestConfiguration := &users.ItemEventsItemAttachmentsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &users.ItemEventsItemAttachmentsRequestBuilderGetQueryParameters{
+			Select: []string{"id", "name", "contentType", "size", "isInline"},
+		},
+	}
+
+	response, err := cs.client.Me().Events().ByEventId(eventID).Attachments().Get(ctx, requestConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	var attachments []Attachment
+	for _, attachment := range response.GetValue() {
+		attachments = append(attachments, convertAttachment(attachment))
+	}
+	return attachments, nil
+}
+
+// DownloadAttachment returns the content of a file attachment
+func (cs *CalendarService) DownloadAttachment(ctx context.Context, eventID, attachmentID string) ([]byte, error) {
+	attachment, err := cs.client.Me().Events().ByEventId(eventID).Attachments().ByAttachmentId(attachmentID).Get(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	file, ok := attachment.(models.FileAttachmentable)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a file attachment", getStringValue(attachment.GetName()))
+	}
+	return file.GetContentBytes(), nil
+}
+
+func convertAttachment(attachment models.Attachmentable) Attachment {
+	a := Attachment{
+		ID:          getStringValue(attachment.GetId()),
+		Name:        getStringValue(attachment.GetName()),
+		ContentType: getStringValue(attachment.GetContentType()),
+		Inline:      getBoolValue(attachment.GetIsInline()),
+		Kind:        AttachmentFile,
+	}
+	if attachment.GetSize() != nil {
+		a.Size = int(*attachment.GetSize())
+	}
+
+	switch getStringValue(attachment.GetOdataType()) {
+	case "#microsoft.graph.itemAttachment":
+		a.Kind = AttachmentItem
+	case "#microsoft.graph.referenceAttachment":
+		a.Kind = AttachmentReference
+	}
+	return a
+}
@@ -0,0 +1,66 @@
+package calendar
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+	// htmlMarkupRegex tells HTML bodies from plain text ones, which may
+	// contain a stray "<" or "&" of their own
+	htmlMarkupRegex   = regexp.MustCompile(`(?i)<(html|body|div|p|br|span|a|table|font|b|i)\b[^>]*>|&(nbsp|amp|lt|gt|quot|#\d+);`)
+	htmlIgnoredRegex  = regexp.MustCompile(`(?is)<!--.*?-->|<(style|script|head)\b[^>]*>.*?</(style|script|head)>`)
+	htmlAnchorRegex   = regexp.MustCompile(`(?is)<a\b[^>]*?\bhref\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	htmlListItemRegex = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	// htmlBlockEndRegex matches tags that end a line or paragraph
+	htmlBlockEndRegex    = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|ul|ol|tr|table|h[1-6])>`)
+	linkPlaceholderRegex = regexp.MustCompile("\x00([0-9]+)\x00")
+	blankLinesRegex      = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText converts an HTML event body to plain text. Links keep their
+// target as "label <url>" so join links survive, with Safe Links unwrapped.
+// Plain text bodies only have their Safe Links unwrapped and whitespace
+// tidied.
+func htmlToText(body string) string {
+	if htmlMarkupRegex.MatchString(body) {
+		body = htmlIgnoredRegex.ReplaceAllString(body, "")
+
+		// Links are swapped for placeholders so stripping tags doesn't eat
+		// the "<url>" they turn into
+		var links []string
+		body = htmlAnchorRegex.ReplaceAllStringFunc(body, func(anchor string) string {
+			match := htmlAnchorRegex.FindStringSubmatch(anchor)
+			href := unwrapSafeLinks(strings.TrimSpace(html.UnescapeString(match[1])))
+			label := strings.Join(strings.Fields(html.UnescapeString(htmlTagRegex.ReplaceAllString(match[2], " "))), " ")
+
+			link := strings.TrimPrefix(href, "mailto:")
+			switch {
+			case href == "" || strings.HasPrefix(href, "#"):
+				return label
+			case label != "" && label != href && label != link:
+				link = label + " <" + href + ">"
+			}
+			links = append(links, link)
+			return fmt.Sprintf("\x00%d\x00", len(links)-1)
+		})
+
+		body = htmlListItemRegex.ReplaceAllString(body, "\n- ")
+		body = htmlBlockEndRegex.ReplaceAllString(body, "\n")
+		body = html.UnescapeString(htmlTagRegex.ReplaceAllString(body, ""))
+		body = linkPlaceholderRegex.ReplaceAllStringFunc(body, func(placeholder string) string {
+			i, _ := strconv.Atoi(strings.Trim(placeholder, "\x00"))
+			return links[i]
+		})
+	}
+
+	lines := strings.Split(strings.ReplaceAll(unwrapSafeLinks(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
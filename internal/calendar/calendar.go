@@ -12,27 +12,138 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	abstractions "github.com/microsoft/kiota-abstractions-go"
 	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
 	"github.com/microsoftgraph/msgraph-sdk-go-core/authentication"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/microsoftgraph/msgraph-sdk-go/users"
 )
 
 type Event struct {
+	ID        string
+	ICalUID   string
 	Subject   string
 	Start     time.Time
 	End       time.Time
 	Location  string
 	WebLink   string
 	TeamsLink string
+	ZoomLink  string
 	IsTeams   bool
 	IsAllDay  bool
 	Organizer string
 	Attendees []string
-	Body      string
+	// AttendeeResponses maps attendee names to their response (the same
+	// values as ResponseStatus), when the provider reports them
+	AttendeeResponses map[string]string
+	// ResponseStatus is my response to the invite: accepted,
+	// tentativelyAccepted, declined, notResponded, organizer or none
+	ResponseStatus string
+	// Categories are the event's Outlook categories; Color is the preset
+	// color name (e.g. "red") of the first one that has a color
+	Categories []string
+	Color      string
+	// ShowAs is the free/busy status: free, tentative, busy, oof,
+	// workingElsewhere or unknown
+	ShowAs string
+	// Sensitivity is normal, personal, private or confidential
+	Sensitivity string
+	// Type is singleInstance, occurrence, exception or seriesMaster.
+	// Occurrences and exceptions point at their series via SeriesMasterID;
+	// exceptions also carry the OriginalStart they replace.
+	Type           string
+	SeriesMasterID string
+	OriginalStart  time.Time
+	// Recurrence is the series' iCalendar RRULE, only set on series masters
+	Recurrence string
+	// Body is the invite body as the provider returned it, usually HTML;
+	// BodyText is the same converted to plain text, with links kept
+	Body     string
+	BodyText string
+	// DialIn is the audio conferencing number of Teams and Zoom meetings, if any
+	DialIn *DialIn
+	// ReminderOn and ReminderMinutes are the event's own reminder, as set in
+	// Outlook (isReminderOn, reminderMinutesBeforeStart)
+	ReminderOn      bool
+	ReminderMinutes int
+	// IsCancelled is set on meetings the organizer cancelled that are still
+	// on the calendar
+	IsCancelled bool
+	// HasAttachments is set when files are attached to the invite
+	HasAttachments bool
+	// Tenant names the guest tenant the event comes from, empty for the
+	// account's own tenant
+	Tenant string
+	// Group names the Microsoft 365 group calendar the event comes from,
+	// empty for the user's own calendar
+	Group string
+	// Change is set by the event cache on events that were moved recently
+	Change *EventChange
 }
 
+// preferredTimeZone is sent in the Prefer header so Graph returns every
+// dateTime in one known zone, regardless of the organizer's time zone
+const preferredTimeZone = "UTC"
+
+// DefaultBlockingShowAs are the showAs values that make an event blocking
+var DefaultBlockingShowAs = []string{"busy", "tentative", "oof", "workingElsewhere", "unknown"}
+
+var blockingShowAs = DefaultBlockingShowAs
+
+// SetBlockingShowAs configures which showAs values count as blocking.
+// An empty list keeps the defaults.
+func SetBlockingShowAs(values []string) {
+	if len(values) == 0 {
+		blockingShowAs = DefaultBlockingShowAs
+		return
+	}
+	blockingShowAs = values
+}
+
+// DefaultLookaheadDays is how far ahead upcoming events are searched
+const DefaultLookaheadDays = 7
+
+var lookaheadDays = DefaultLookaheadDays
+
+// SetLookaheadDays sets how many days ahead upcoming events and the next
+// meeting are searched. Zero or less keeps the default.
+func SetLookaheadDays(days int) {
+	if days <= 0 {
+		days = DefaultLookaheadDays
+	}
+	lookaheadDays = days
+}
+
+// Lookahead returns the upcoming events window
+func Lookahead() time.Duration {
+	return time.Duration(lookaheadDays) * 24 * time.Hour
+}
+
+// LookaheadDays returns the upcoming events window in days
+func LookaheadDays() int {
+	return lookaheadDays
+}
+
+// DefaultMaxEvents caps how many events a single range query pages through
+const DefaultMaxEvents = 1000
+
+// pageSize is the number of events requested per Graph page
+const pageSize = 50
+
 type CalendarService struct {
-	client *msgraphsdk.GraphServiceClient
+	client         *msgraphsdk.GraphServiceClient
+	maxEvents      int
+	categoryColors map[string]string
+	// accountType selects compatibility fixes, see applyPersonalQuirks
+	accountType string
+	// tenant is the guest tenant's name on services created for one
+	tenant string
+	// group is set on services reading a group calendar instead of /me
+	group *groupCalendar
+	// merged read the calendars merged into this one: the guest tenants
+	// from config.json and the group calendars from settings.json
+	merged []*CalendarService
 }
 
 func NewCalendarService() (*CalendarService, error) {
@@ -45,41 +156,72 @@ func NewCalendarServiceWithOptions(allowInteractive bool) (*CalendarService, err
 
 func NewCalendarServiceWithRefresh(allowInteractive bool, forceRefresh bool) (*CalendarService, error) {
 	// Create a custom credential that respects interactive mode
-	credential := &nonInteractiveCredential{
+	client, err := newGraphClient(&nonInteractiveCredential{
 		allowInteractive: allowInteractive,
 		forceRefresh:     forceRefresh,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	authProvider, err := authentication.NewAzureIdentityAuthenticationProviderWithScopes(credential, []string{
-		"https://graph.microsoft.com/Calendars.Read",
-		"https://graph.microsoft.com/User.Read",
-	})
+	// Without a cached account the work account behaviour is the safe default
+	accountType, err := auth.DetectAccountType(context.Background())
+	if err != nil {
+		accountType = auth.AccountTypeWork
+	}
+
+	service := &CalendarService{client: client, maxEvents: DefaultMaxEvents, accountType: accountType}
+	if config, err := auth.LoadConfig(); err == nil {
+		if err := service.addGuestTenants(config.GuestTenants, allowInteractive, forceRefresh); err != nil {
+			return nil, err
+		}
+	}
+	service.addGroupCalendars(groupCalendars)
+	return service, nil
+}
+
+// newGraphClient creates a Graph client authenticated with credential
+func newGraphClient(credential azcore.TokenCredential) (*msgraphsdk.GraphServiceClient, error) {
+	authProvider, err := authentication.NewAzureIdentityAuthenticationProviderWithScopes(credential, auth.Scopes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth provider: %w", err)
 	}
 
-	adapter, err := msgraphsdk.NewGraphRequestAdapter(authProvider)
+	adapter, err := msgraphsdk.NewGraphRequestAdapterWithParseNodeFactoryAndSerializationWriterFactoryAndHttpClient(authProvider, nil, nil, newHTTPClient())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create adapter: %w", err)
 	}
 
-	client := msgraphsdk.NewGraphServiceClient(adapter)
+	return msgraphsdk.NewGraphServiceClient(adapter), nil
+}
 
-	return &CalendarService{client: client}, nil
+// SetMaxEvents sets the safety cap on events fetched per range query
+func (cs *CalendarService) SetMaxEvents(maxEvents int) {
+	if maxEvents > 0 {
+		cs.maxEvents = maxEvents
+		for _, other := range cs.merged {
+			other.maxEvents = maxEvents
+		}
+	}
 }
 
 // nonInteractiveCredential wraps the authentication to control interactive behavior
 type nonInteractiveCredential struct {
 	allowInteractive bool
 	forceRefresh     bool
+	// tenantID is set for guest tenants
+	tenantID string
 }
 
 func (nic *nonInteractiveCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	if nic.tenantID != "" {
+		return auth.GetAccessTokenForTenant(ctx, nic.tenantID, nic.allowInteractive, nic.forceRefresh)
+	}
 	return auth.GetAccessTokenWithOptionsAndForceRefresh(ctx, nic.allowInteractive, nic.forceRefresh)
 }
 
 func (cs *CalendarService) GetTodaysEvents(ctx context.Context) ([]Event, error) {
-	now := time.Now()
+	now := Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
@@ -91,9 +233,9 @@ func (cs *CalendarService) GetTodaysEvents(ctx context.Context) ([]Event, error)
 }
 
 func (cs *CalendarService) GetUpcomingEvents(ctx context.Context) ([]Event, error) {
-	now := time.Now()
-	// Get events from now until 7 days from now
-	endTime := now.Add(7 * 24 * time.Hour)
+	now := Now()
+	// Get events from now until the end of the lookahead window
+	endTime := now.Add(Lookahead())
 
 	// Use CalendarView with proper date range
 	nowStr := now.UTC().Format("2006-01-02T15:04:05.000Z")
@@ -102,82 +244,192 @@ func (cs *CalendarService) GetUpcomingEvents(ctx context.Context) ([]Event, erro
 	return cs.getEventsWithCalendarView(ctx, nowStr, endStr)
 }
 
+// GetEventsBetween returns the events overlapping [start, end)
+func (cs *CalendarService) GetEventsBetween(ctx context.Context, start, end time.Time) ([]Event, error) {
+	startStr := start.UTC().Format("2006-01-02T15:04:05.000Z")
+	endStr := end.UTC().Format("2006-01-02T15:04:05.000Z")
+
+	return cs.getEventsWithCalendarView(ctx, startStr, endStr)
+}
+
 func (cs *CalendarService) getEventsWithCalendarView(ctx context.Context, startDateTime, endDateTime string) ([]Event, error) {
+	var result []Event
+	err := cs.iterateCalendarView(ctx, startDateTime, endDateTime, func(event Event) bool {
+		result = append(result, event)
+		return len(result) < cs.maxEvents
+	})
+	if err != nil {
+		return nil, err
+	}
+	cs.applyCategoryColors(ctx, result)
+	cs.sortMerged(result)
+
+	return HideCancelled(Dedupe(result)), nil
+}
+
+// iterateCalendarView pages through the calendar view between startDateTime
+// and endDateTime, then through each merged calendar's, handing each event
+// to visit until it returns false
+func (cs *CalendarService) iterateCalendarView(ctx context.Context, startDateTime, endDateTime string, visit func(Event) bool) error {
+	stopped := false
+	for _, service := range append([]*CalendarService{cs}, cs.merged...) {
+		err := service.iterateOwnCalendarView(ctx, startDateTime, endDateTime, func(event Event) bool {
+			stopped = !visit(event)
+			return !stopped
+		})
+		if err != nil {
+			return err
+		}
+		if stopped {
+			break
+		}
+	}
+	return nil
+}
+
+// calendarViewFields are the event properties requested from calendar views
+var calendarViewFields = []string{"id", "iCalUId", "subject", "start", "end", "location", "webLink", "body", "organizer", "attendees", "responseStatus", "categories", "showAs", "sensitivity", "onlineMeeting", "onlineMeetingUrl", "isOnlineMeeting", "onlineMeetingProvider", "isAllDay", "type", "seriesMasterId", "originalStart", "isReminderOn", "reminderMinutesBeforeStart", "isCancelled", "hasAttachments"}
+
+// iterateOwnCalendarView pages through this service's calendar view only
+func (cs *CalendarService) iterateOwnCalendarView(ctx context.Context, startDateTime, endDateTime string, visit func(Event) bool) error {
+	headers := abstractions.NewRequestHeaders()
+	headers.Add("Prefer", fmt.Sprintf("outlook.timezone=%q", preferredTimeZone))
+
+	if cs.group != nil {
+		events, err := cs.getGroupCalendarView(ctx, headers, startDateTime, endDateTime)
+		if err != nil {
+			return err
+		}
+		return cs.iterateEvents(ctx, events, headers, visit)
+	}
+
 	requestConfiguration := &users.ItemCalendarViewRequestBuilderGetRequestConfiguration{
+		Headers: headers,
 		QueryParameters: &users.ItemCalendarViewRequestBuilderGetQueryParameters{
 			StartDateTime: &startDateTime,
 			EndDateTime:   &endDateTime,
 			Orderby:       []string{"start/dateTime"},
-			Select:        []string{"subject", "start", "end", "location", "webLink", "body", "organizer", "attendees", "onlineMeeting", "isAllDay"},
-			Top:           intPtr(50),
+			Select:        calendarViewFields,
+			Top:           intPtr(pageSize),
 		},
 	}
 
 	events, err := cs.client.Me().CalendarView().Get(ctx, requestConfiguration)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get calendar view: %w", err)
+		if cs.tenant != "" {
+			return fmt.Errorf("failed to get calendar view in tenant %s: %w", cs.tenant, err)
+		}
+		return fmt.Errorf("failed to get calendar view: %w", err)
 	}
+	return cs.iterateEvents(ctx, events, headers, visit)
+}
 
-	var result []Event
-	for _, event := range events.GetValue() {
-		e := Event{
-			Subject:  getStringValue(event.GetSubject()),
-			Location: getStringValue(event.GetLocation().GetDisplayName()),
-			WebLink:  getStringValue(event.GetWebLink()),
-			Body:     getStringValue(event.GetBody().GetContent()),
-			IsAllDay: getBoolValue(event.GetIsAllDay()),
-		}
+// iterateEvents hands each event of a calendar view response to visit,
+// fetching further pages until it returns false
+func (cs *CalendarService) iterateEvents(ctx context.Context, events models.EventCollectionResponseable, headers *abstractions.RequestHeaders, visit func(Event) bool) error {
+	// Follow @odata.nextLink so busy calendars aren't silently truncated
+	pageIterator, err := msgraphcore.NewPageIterator[models.Eventable](events, cs.client.GetAdapter(), models.CreateEventCollectionResponseFromDiscriminatorValue)
+	if err != nil {
+		return fmt.Errorf("failed to create page iterator: %w", err)
+	}
+	pageIterator.SetHeaders(headers)
 
-		if event.GetStart() != nil && event.GetStart().GetDateTime() != nil {
-			startStr := getStringValue(event.GetStart().GetDateTime())
-			e.Start = parseMicrosoftDateTime(startStr)
-		}
-		if event.GetEnd() != nil && event.GetEnd().GetDateTime() != nil {
-			endStr := getStringValue(event.GetEnd().GetDateTime())
-			e.End = parseMicrosoftDateTime(endStr)
-		}
+	err = pageIterator.Iterate(ctx, func(event models.Eventable) bool {
+		return visit(cs.convert(event))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to page calendar view: %w", err)
+	}
+	return nil
+}
 
-		if event.GetOrganizer() != nil && event.GetOrganizer().GetEmailAddress() != nil {
-			e.Organizer = getStringValue(event.GetOrganizer().GetEmailAddress().GetName())
-		}
+// convertEvent maps a Graph event onto our Event type
+func convertEvent(event models.Eventable) Event {
+	e := Event{
+		ID:             getStringValue(event.GetId()),
+		ICalUID:        getStringValue(event.GetICalUId()),
+		Subject:        getStringValue(event.GetSubject()),
+		Location:       getStringValue(event.GetLocation().GetDisplayName()),
+		WebLink:        getStringValue(event.GetWebLink()),
+		Body:           getStringValue(event.GetBody().GetContent()),
+		IsAllDay:       getBoolValue(event.GetIsAllDay()),
+		IsCancelled:    getBoolValue(event.GetIsCancelled()),
+		HasAttachments: getBoolValue(event.GetHasAttachments()),
+		Categories:     event.GetCategories(),
+	}
 
-		for _, attendee := range event.GetAttendees() {
-			if attendee.GetEmailAddress() != nil {
-				e.Attendees = append(e.Attendees, getStringValue(attendee.GetEmailAddress().GetName()))
-			}
-		}
+	e.BodyText = htmlToText(e.Body)
+	e.Start = parseDateTimeTimeZone(event.GetStart(), e.IsAllDay)
+	e.End = parseDateTimeTimeZone(event.GetEnd(), e.IsAllDay)
 
-		// Use onlineMeeting field for Teams meetings
-		if event.GetOnlineMeeting() != nil {
-			e.IsTeams = true
-			if event.GetOnlineMeeting().GetJoinUrl() != nil {
-				e.TeamsLink = getStringValue(event.GetOnlineMeeting().GetJoinUrl())
+	if event.GetOrganizer() != nil && event.GetOrganizer().GetEmailAddress() != nil {
+		e.Organizer = getStringValue(event.GetOrganizer().GetEmailAddress().GetName())
+	}
+
+	if event.GetTypeEscaped() != nil {
+		e.Type = event.GetTypeEscaped().String()
+	}
+	e.SeriesMasterID = getStringValue(event.GetSeriesMasterId())
+	if event.GetOriginalStart() != nil {
+		e.OriginalStart = *event.GetOriginalStart()
+	}
+
+	if event.GetShowAs() != nil {
+		e.ShowAs = event.GetShowAs().String()
+	}
+
+	e.ReminderOn = getBoolValue(event.GetIsReminderOn())
+	if event.GetReminderMinutesBeforeStart() != nil {
+		e.ReminderMinutes = int(*event.GetReminderMinutesBeforeStart())
+	}
+
+	if event.GetSensitivity() != nil {
+		e.Sensitivity = event.GetSensitivity().String()
+	}
+
+	if event.GetResponseStatus() != nil && event.GetResponseStatus().GetResponse() != nil {
+		e.ResponseStatus = event.GetResponseStatus().GetResponse().String()
+	}
+
+	for _, attendee := range event.GetAttendees() {
+		if attendee.GetEmailAddress() == nil {
+			continue
+		}
+		name := getStringValue(attendee.GetEmailAddress().GetName())
+		e.Attendees = append(e.Attendees, name)
+		if attendee.GetStatus() != nil && attendee.GetStatus().GetResponse() != nil {
+			if e.AttendeeResponses == nil {
+				e.AttendeeResponses = make(map[string]string)
 			}
-		} else {
-			// Fallback to body/location parsing for non-standard meeting links
-			e.TeamsLink, e.IsTeams = extractTeamsLink(e.Body, e.Location)
+			e.AttendeeResponses[name] = attendee.GetStatus().GetResponse().String()
 		}
+	}
 
-		result = append(result, e)
+	// Use onlineMeeting field for Teams meetings
+	if event.GetOnlineMeeting() != nil {
+		e.IsTeams = true
+		if event.GetOnlineMeeting().GetJoinUrl() != nil {
+			e.TeamsLink = getStringValue(event.GetOnlineMeeting().GetJoinUrl())
+		}
+	} else {
+		// Fallback to body/location parsing for non-standard meeting links
+		e.TeamsLink, e.IsTeams = extractTeamsLink(e.BodyText, e.Location)
+	}
+	e.ZoomLink = extractZoomLink(e.BodyText, e.Location)
+	if e.IsTeams || e.ZoomLink != "" {
+		e.DialIn = extractDialIn(e.BodyText)
 	}
 
-	return result, nil
+	return e
 }
 
-func (cs *CalendarService) GetNextMeeting(ctx context.Context) (*Event, error) {
+func (cs *CalendarService) GetNextMeeting(ctx context.Context, opts NextMeetingOptions) (*Event, error) {
 	events, err := cs.GetUpcomingEvents(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	for _, event := range events {
-		if event.Start.After(now) || (event.Start.Before(now) && event.End.After(now)) {
-			return &event, nil
-		}
-	}
-
-	return nil, nil
+	return SelectNextMeeting(events, Now(), opts), nil
 }
 
 func extractTeamsLink(body, location string) (string, bool) {
@@ -188,7 +440,7 @@ func extractTeamsLink(body, location string) (string, bool) {
 		`https://[a-zA-Z0-9-]+\.teams\.microsoft\.com/[^\s<>"']+`,
 	}
 
-	content := body + " " + location
+	content := unwrapSafeLinks(body + " " + location)
 
 	// Try each Teams URL pattern
 	for _, pattern := range teamsPatterns {
@@ -230,6 +482,14 @@ func extractTeamsLink(body, location string) (string, bool) {
 	return "", false
 }
 
+func extractZoomLink(body, location string) string {
+	zoomRegex := regexp.MustCompile(`https://[a-zA-Z0-9.-]*zoom\.us/(j|my|w)/[^\s<>"']+`)
+	if match := zoomRegex.FindString(unwrapSafeLinks(location + " " + body)); match != "" {
+		return strings.TrimRight(match, ".,:;!?")
+	}
+	return ""
+}
+
 func getStringValue(ptr *string) string {
 	if ptr == nil {
 		return ""
@@ -244,32 +504,56 @@ func getBoolValue(ptr *bool) bool {
 	return *ptr
 }
 
+func getInt32Value(ptr *int32) int32 {
+	if ptr == nil {
+		return 0
+	}
+	return *ptr
+}
+
 func intPtr(i int32) *int32 {
 	return &i
 }
 
-func parseMicrosoftDateTime(dateTimeStr string) time.Time {
+// parseDateTimeTimeZone converts a Graph dateTime/timeZone pair into a local
+// time.Time. All-day events are pinned to local midnight, since their dates
+// are calendar days rather than instants.
+func parseDateTimeTimeZone(dt models.DateTimeTimeZoneable, isAllDay bool) time.Time {
+	if dt == nil || dt.GetDateTime() == nil {
+		return time.Time{}
+	}
+
+	loc := resolveTimeZone(getStringValue(dt.GetTimeZone()))
+	parsed := parseMicrosoftDateTime(getStringValue(dt.GetDateTime()), loc)
+	if parsed.IsZero() {
+		return parsed
+	}
+
+	if isAllDay {
+		return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.Local)
+	}
+	return parsed.In(time.Local)
+}
+
+// parseMicrosoftDateTime parses a Graph dateTime string. Graph omits the
+// offset and reports the zone separately, so the wall clock is read in loc.
+func parseMicrosoftDateTime(dateTimeStr string, loc *time.Location) time.Time {
 	if dateTimeStr == "" {
 		return time.Time{}
 	}
 
 	// Microsoft Graph datetime formats to try
 	formats := []string{
-		"2006-01-02T15:04:05.0000000", // Microsoft's .NET format
-		"2006-01-02T15:04:05",         // ISO without fractional seconds
-		time.RFC3339,                  // Standard RFC3339
-		"2006-01-02T15:04:05Z",        // UTC format
-		"2006-01-02T15:04:05.000Z",    // UTC with milliseconds
+		"2006-01-02T15:04:05.0000000",   // Microsoft's .NET format
+		"2006-01-02T15:04:05",           // ISO without fractional seconds
+		"2006-01-02T15:04:05.999999999", // Other fractional precisions (personal accounts)
+		time.RFC3339,                    // Standard RFC3339
+		"2006-01-02T15:04:05Z",          // UTC format
+		"2006-01-02T15:04:05.000Z",      // UTC with milliseconds
 	}
 
 	for _, format := range formats {
-		if parsedTime, err := time.Parse(format, dateTimeStr); err == nil {
-			// Microsoft Graph returns times in the user's timezone, but without timezone info
-			// We'll assume local timezone if no timezone is specified
-			if parsedTime.Location() == time.UTC && !strings.HasSuffix(dateTimeStr, "Z") {
-				// Convert to local timezone
-				return parsedTime.In(time.Local)
-			}
+		if parsedTime, err := time.ParseInLocation(format, dateTimeStr, loc); err == nil {
 			return parsedTime
 		}
 	}
@@ -278,27 +562,49 @@ func parseMicrosoftDateTime(dateTimeStr string) time.Time {
 	return time.Time{}
 }
 
-func (e *Event) GetTimeUntil() time.Duration {
-	return time.Until(e.Start)
+// GetJoinLink returns the online meeting link, preferring Teams over Zoom
+func (e *Event) GetJoinLink() string {
+	if e.IsTeams && e.TeamsLink != "" {
+		return e.TeamsLink
+	}
+	return e.ZoomLink
 }
 
-func (e *Event) GetStatus() string {
-	now := time.Now()
-	if now.After(e.End) {
-		return "past"
+// outlookItemURL opens an event by its ID in Outlook on the web
+const outlookItemURL = "https://outlook.office.com/calendar/item/"
+
+// OutlookLink returns the event's page in Outlook on the web, where it can be
+// edited: the provider's web link, or one built from the event ID
+func (e *Event) OutlookLink() string {
+	if strings.HasPrefix(e.WebLink, "https://") || strings.HasPrefix(e.WebLink, "http://") {
+		return e.WebLink
 	}
-	if now.After(e.Start) && now.Before(e.End) {
-		return "current"
+	if e.ID != "" {
+		return outlookItemURL + url.PathEscape(e.ID)
 	}
+	return ""
+}
 
-	timeUntil := time.Until(e.Start)
-	if timeUntil <= 5*time.Minute {
-		return "urgent"
-	}
-	if timeUntil <= 15*time.Minute {
-		return "soon"
+// GetProvider names the online meeting service: "teams", "zoom" or "" for
+// events without a join link
+func (e *Event) GetProvider() string {
+	switch {
+	case e.IsTeams:
+		return "teams"
+	case e.ZoomLink != "":
+		return "zoom"
+	default:
+		return ""
 	}
-	return "upcoming"
+}
+
+func (e *Event) GetTimeUntil() time.Duration {
+	return e.Start.Sub(Now())
+}
+
+// IsPrivate reports whether the organizer marked the event private or confidential
+func (e *Event) IsPrivate() bool {
+	return e.Sensitivity == "private" || e.Sensitivity == "confidential"
 }
 
 func (e *Event) GetDuration() time.Duration {
@@ -309,6 +615,22 @@ func (e *Event) IsLongEvent() bool {
 	return e.GetDuration() > 4*time.Hour
 }
 
+// IsBlockingEvent reports whether the event occupies time: not all-day, not
+// longer than four hours, and shown as one of the blocking showAs values
 func (e *Event) IsBlockingEvent() bool {
-	return !e.IsAllDay && !e.IsLongEvent()
+	return !e.IsAllDay && !e.IsCancelled && !e.IsLongEvent() && e.IsShownAsBlocking()
+}
+
+// IsShownAsBlocking reports whether the event's showAs value is configured as
+// blocking. Events without a showAs value (e.g. from an older cache) count as blocking.
+func (e *Event) IsShownAsBlocking() bool {
+	if e.ShowAs == "" {
+		return true
+	}
+	for _, value := range blockingShowAs {
+		if strings.EqualFold(value, e.ShowAs) {
+			return true
+		}
+	}
+	return false
 }
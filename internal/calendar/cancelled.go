@@ -0,0 +1,24 @@
+package calendar
+
+var showCancelled bool
+
+// SetShowCancelled keeps cancelled events that are still on the calendar
+// instead of dropping them. Shown, they never count as blocking.
+func SetShowCancelled(show bool) {
+	showCancelled = show
+}
+
+// HideCancelled drops cancelled events unless they are configured to be shown
+func HideCancelled(events []Event) []Event {
+	if showCancelled {
+		return events
+	}
+
+	result := events[:0:0]
+	for _, event := range events {
+		if !event.IsCancelled {
+			result = append(result, event)
+		}
+	}
+	return result
+}
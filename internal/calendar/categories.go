@@ -0,0 +1,106 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// presetColors maps Outlook's category color presets to the names used in
+// waybar classes ("cat-red") and tooltip colors
+var presetColors = map[string]string{
+	"preset0":  "red",
+	"preset1":  "orange",
+	"preset2":  "brown",
+	"preset3":  "yellow",
+	"preset4":  "green",
+	"preset5":  "teal",
+	"preset6":  "olive",
+	"preset7":  "blue",
+	"preset8":  "purple",
+	"preset9":  "cranberry",
+	"preset10": "steel",
+	"preset11": "darksteel",
+	"preset12": "gray",
+	"preset13": "darkgray",
+	"preset14": "black",
+	"preset15": "darkred",
+	"preset16": "darkorange",
+	"preset17": "darkbrown",
+	"preset18": "darkyellow",
+	"preset19": "darkgreen",
+	"preset20": "darkteal",
+	"preset21": "darkolive",
+	"preset22": "darkblue",
+	"preset23": "darkpurple",
+	"preset24": "darkcranberry",
+}
+
+// GetCategoryColors returns the user's Outlook master category list as
+// lowercased category name -> color name. Categories without a color are omitted.
+func (cs *CalendarService) GetCategoryColors(ctx context.Context) (map[string]string, error) {
+	response, err := cs.client.Me().Outlook().MasterCategories().Get(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master categories: %w", err)
+	}
+
+	colors := make(map[string]string)
+	for _, category := range response.GetValue() {
+		if category.GetColor() == nil {
+			continue
+		}
+		if color, ok := presetColors[category.GetColor().String()]; ok {
+			colors[strings.ToLower(getStringValue(category.GetDisplayName()))] = color
+		}
+	}
+
+	return colors, nil
+}
+
+// CategoriesScope is the permission looking up category colors needs. It
+// is only requested when category colors are enabled.
+const CategoriesScope = "https://graph.microsoft.com/MailboxSettings.Read"
+
+var categoryColorsEnabled bool
+
+// SetCategoryColors toggles looking up Outlook category colors for events
+func SetCategoryColors(enabled bool) {
+	categoryColorsEnabled = enabled
+}
+
+// applyCategoryColors sets Color on events from their first colored category.
+// The master category list is only fetched when category colors are enabled
+// and an event has categories, and a failure (e.g. a token without
+// MailboxSettings.Read) leaves events uncolored.
+func (cs *CalendarService) applyCategoryColors(ctx context.Context, events []Event) {
+	if !categoryColorsEnabled || !hasCategories(events) {
+		return
+	}
+
+	if cs.categoryColors == nil {
+		colors, err := cs.GetCategoryColors(ctx)
+		if err != nil {
+			return
+		}
+		cs.categoryColors = colors
+	}
+
+	for i := range events {
+		events[i].Color = ""
+		for _, category := range events[i].Categories {
+			if color, ok := cs.categoryColors[strings.ToLower(category)]; ok {
+				events[i].Color = color
+				break
+			}
+		}
+	}
+}
+
+func hasCategories(events []Event) bool {
+	for _, event := range events {
+		if len(event.Categories) > 0 {
+			return true
+		}
+	}
+	return false
+}
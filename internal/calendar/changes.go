@@ -0,0 +1,23 @@
+package calendar
+
+import "time"
+
+// EventChange records that an event was moved, i.e. its time or location
+// changed since an earlier refresh, and where it was before
+type EventChange struct {
+	At               time.Time
+	PreviousStart    time.Time
+	PreviousEnd      time.Time
+	PreviousLocation string
+}
+
+// Moved reports whether event has another time or location than before
+func Moved(before, event Event) bool {
+	return !before.Start.Equal(event.Start) || !before.End.Equal(event.End) || before.Location != event.Location
+}
+
+// Rescheduled reports whether the change moved event in time rather than
+// only to another location
+func (c *EventChange) Rescheduled(event Event) bool {
+	return !c.PreviousStart.Equal(event.Start) || !c.PreviousEnd.Equal(event.End)
+}
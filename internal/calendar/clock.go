@@ -0,0 +1,37 @@
+package calendar
+
+import "time"
+
+// Clock tells the time. Status and rendering code asks the package clock
+// instead of calling time.Now, so output can be produced for a frozen moment.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a Clock that is stopped at the given time
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var clock Clock = systemClock{}
+
+// SetClock replaces the package clock; nil restores the system clock
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clock = c
+}
+
+// Now returns the current time according to the package clock
+func Now() time.Time {
+	return clock.Now()
+}
@@ -0,0 +1,75 @@
+package calendar
+
+import "time"
+
+// BackToBackGap is the longest break after a meeting that still leaves it
+// back-to-back with the next one
+const BackToBackGap = 5 * time.Minute
+
+// Overlaps reports whether two events share any time
+func (e *Event) Overlaps(other *Event) bool {
+	return e.Start.Before(other.End) && other.Start.Before(e.End)
+}
+
+// HasConflict reports whether event is blocking and overlaps another
+// blocking event in events
+func HasConflict(event Event, events []Event) bool {
+	if !event.IsBlockingEvent() {
+		return false
+	}
+	for _, other := range events {
+		if other.ID == event.ID || !other.IsBlockingEvent() {
+			continue
+		}
+		if event.Overlaps(&other) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextBackToBack returns the blocking meeting the user hasn't declined that
+// starts at most BackToBackGap after event ends, or nil when a break follows
+func NextBackToBack(event Event, events []Event) *Event {
+	var next *Event
+	for i := range events {
+		other := &events[i]
+		if other.ID == event.ID || !other.IsBlockingEvent() || other.IsDeclined() {
+			continue
+		}
+		if !other.Start.After(event.Start) || !other.End.After(event.End) || other.Start.After(event.End.Add(BackToBackGap)) {
+			continue
+		}
+		if next == nil || other.Start.Before(next.Start) {
+			next = other
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	found := *next
+	return &found
+}
+
+// ConflictsWithPrevious returns, for events sorted by start, whether each
+// one is blocking and starts before an earlier blocking event has ended.
+// Renderers use it to put a marker between double-booked rows.
+func ConflictsWithPrevious(events []Event) []bool {
+	conflicts := make([]bool, len(events))
+
+	var latest *Event
+	for i := range events {
+		event := &events[i]
+		if !event.IsBlockingEvent() {
+			continue
+		}
+		if latest != nil && event.Start.Before(latest.End) {
+			conflicts[i] = true
+		}
+		if latest == nil || event.End.After(latest.End) {
+			latest = event
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,62 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+// Dedupe drops events that appear more than once, e.g. a forwarded invite
+// that lands in several calendars. Two events are the same meeting if they
+// share an iCalUId and start time, or the same subject, start and end. The
+// first occurrence is kept and missing links/location are filled from the
+// duplicates.
+func Dedupe(events []Event) []Event {
+	var result []Event
+	byUID := make(map[string]int)
+	bySubject := make(map[string]int)
+
+	for _, event := range events {
+		uidKey := ""
+		if event.ICalUID != "" {
+			uidKey = event.ICalUID + "|" + event.Start.UTC().Format(time.RFC3339)
+		}
+		subjectKey := strings.ToLower(strings.TrimSpace(event.Subject)) + "|" +
+			event.Start.UTC().Format(time.RFC3339) + "|" + event.End.UTC().Format(time.RFC3339)
+
+		index, seen := byUID[uidKey]
+		if uidKey == "" || !seen {
+			index, seen = bySubject[subjectKey]
+		}
+
+		if seen {
+			mergeDuplicate(&result[index], event)
+		} else {
+			index = len(result)
+			result = append(result, event)
+		}
+
+		if uidKey != "" {
+			byUID[uidKey] = index
+		}
+		bySubject[subjectKey] = index
+	}
+
+	return result
+}
+
+// mergeDuplicate fills fields that kept is missing from dup
+func mergeDuplicate(kept *Event, dup Event) {
+	if kept.TeamsLink == "" && dup.TeamsLink != "" {
+		kept.TeamsLink = dup.TeamsLink
+		kept.IsTeams = true
+	}
+	if kept.ZoomLink == "" {
+		kept.ZoomLink = dup.ZoomLink
+	}
+	if kept.Location == "" {
+		kept.Location = dup.Location
+	}
+	if kept.WebLink == "" {
+		kept.WebLink = dup.WebLink
+	}
+}
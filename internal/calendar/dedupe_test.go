@@ -0,0 +1,135 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC)
+	}
+	event := func(id, uid, subject string, start int) Event {
+		return Event{ID: id, ICalUID: uid, Subject: subject, Start: at(start), End: at(start + 1)}
+	}
+
+	tests := []struct {
+		name   string
+		events []Event
+		want   []string
+	}{
+		{
+			name:   "same iCalUID and start",
+			events: []Event{event("a", "uid-1", "Standup", 9), event("b", "uid-1", "Standup", 9)},
+			want:   []string{"a"},
+		},
+		{
+			name:   "same iCalUID with a renamed subject",
+			events: []Event{event("a", "uid-1", "Standup", 9), event("b", "uid-1", "Daily standup", 9)},
+			want:   []string{"a"},
+		},
+		{
+			name:   "same iCalUID at different starts are occurrences",
+			events: []Event{event("a", "uid-1", "Standup", 9), event("b", "uid-1", "Standup", 10)},
+			want:   []string{"a", "b"},
+		},
+		{
+			name: "exception moved away from the series",
+			events: []Event{
+				{ID: "occurrence", ICalUID: "uid-1", Subject: "Standup", Start: at(9), End: at(10), Type: "occurrence", SeriesMasterID: "master"},
+				{ID: "exception", ICalUID: "uid-1", Subject: "Standup", Start: at(11), End: at(12), Type: "exception", SeriesMasterID: "master", OriginalStart: at(9)},
+			},
+			want: []string{"occurrence", "exception"},
+		},
+		{
+			name: "exception at an occurrence's start",
+			events: []Event{
+				{ID: "occurrence", ICalUID: "uid-1", Subject: "Standup", Start: at(9), End: at(10), Type: "occurrence"},
+				{ID: "exception", ICalUID: "uid-1", Subject: "Standup", Start: at(9), End: at(10), Type: "exception", OriginalStart: at(9)},
+			},
+			want: []string{"occurrence"},
+		},
+		{
+			name:   "empty iCalUID falls back to subject, start and end",
+			events: []Event{event("a", "", "Standup", 9), event("b", "", " standup ", 9)},
+			want:   []string{"a"},
+		},
+		{
+			name:   "empty iCalUIDs with different subjects",
+			events: []Event{event("a", "", "Standup", 9), event("b", "", "Review", 9)},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "empty iCalUID with a different end",
+			events: []Event{event("a", "", "Standup", 9), {ID: "b", Subject: "Standup", Start: at(9), End: at(11)}},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "different iCalUIDs with the same subject and times",
+			events: []Event{event("a", "uid-1", "Standup", 9), event("b", "uid-2", "Standup", 9)},
+			want:   []string{"a"},
+		},
+		{
+			name:   "empty",
+			events: nil,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Dedupe(tt.events)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d events %v, want %v", len(got), ids(got), tt.want)
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Errorf("got %v, want %v", ids(got), tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDedupeMergesDuplicates(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	kept := Event{ID: "kept", ICalUID: "uid-1", Subject: "Standup", Start: start, End: start.Add(time.Hour), Location: "Room 1"}
+	dup := Event{
+		ID: "dup", ICalUID: "uid-1", Subject: "Standup", Start: start, End: start.Add(time.Hour),
+		Location:  "Room 2",
+		TeamsLink: "https://teams.microsoft.com/l/meetup-join/1",
+		ZoomLink:  "https://example.zoom.us/j/1",
+		WebLink:   "https://outlook.office.com/calendar/item/dup",
+	}
+	third := Event{ID: "third", ICalUID: "uid-1", Subject: "Standup", Start: start, End: start.Add(time.Hour), TeamsLink: "https://teams.microsoft.com/l/meetup-join/3"}
+
+	got := Dedupe([]Event{kept, dup, third})
+	if len(got) != 1 {
+		t.Fatalf("got %d events, want 1", len(got))
+	}
+	event := got[0]
+	if event.ID != "kept" {
+		t.Errorf("kept %q, want the first occurrence", event.ID)
+	}
+	if event.Location != "Room 1" {
+		t.Errorf("location %q, want the first occurrence's to win", event.Location)
+	}
+	if event.TeamsLink != dup.TeamsLink || !event.IsTeams {
+		t.Errorf("teams link %q (IsTeams %v), want the first duplicate's", event.TeamsLink, event.IsTeams)
+	}
+	if event.ZoomLink != dup.ZoomLink {
+		t.Errorf("zoom link %q, want it filled from the duplicate", event.ZoomLink)
+	}
+	if event.WebLink != dup.WebLink {
+		t.Errorf("web link %q, want it filled from the duplicate", event.WebLink)
+	}
+}
+
+func ids(events []Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.ID)
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	abstractions "github.com/microsoft/kiota-abstractions-go"
+	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// DeltaState tracks an incremental calendarView sync. Graph ties a deltaLink
+// to the window it was created for, so a new window means a full resync.
+type DeltaState struct {
+	WindowStart time.Time        `json:"window_start"`
+	WindowEnd   time.Time        `json:"window_end"`
+	DeltaLink   string           `json:"delta_link,omitempty"`
+	Events      map[string]Event `json:"events"`
+}
+
+// SyncDelta brings state up to date for the given window. Only changes since
+// the last sync are fetched when state already covers the same window. Guest
+// tenants and group calendars have no delta link of their own and are
+// fetched in full.
+func (cs *CalendarService) SyncDelta(ctx context.Context, state *DeltaState, windowStart, windowEnd time.Time) (*DeltaState, error) {
+	if len(cs.merged) == 0 {
+		return cs.syncOwnDelta(ctx, state, windowStart, windowEnd)
+	}
+
+	next, err := cs.syncOwnDelta(ctx, state.withoutMerged(), windowStart, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range cs.merged {
+		events, err := other.GetEventsBetween(ctx, windowStart, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			next.Events[mergedEventKey(event)] = event
+		}
+	}
+	return next, nil
+}
+
+// withoutMerged returns a copy of state with only the user's own events,
+// which is what its delta link applies to
+func (ds *DeltaState) withoutMerged() *DeltaState {
+	if ds == nil {
+		return nil
+	}
+	own := *ds
+	own.Events = make(map[string]Event, len(ds.Events))
+	for id, event := range ds.Events {
+		if event.Tenant == "" && event.Group == "" {
+			own.Events[id] = event
+		}
+	}
+	return &own
+}
+
+// mergedEventKey keys merged events apart from the user's own, whose IDs
+// come from another mailbox
+func mergedEventKey(event Event) string {
+	return event.Tenant + "/" + event.Group + "/" + event.ID
+}
+
+func (cs *CalendarService) syncOwnDelta(ctx context.Context, state *DeltaState, windowStart, windowEnd time.Time) (*DeltaState, error) {
+	if state != nil && state.DeltaLink != "" && state.WindowStart.Equal(windowStart) && state.WindowEnd.Equal(windowEnd) {
+		next, err := cs.syncDelta(ctx, state, state.DeltaLink)
+		if err == nil {
+			return next, nil
+		}
+		// The delta token may have expired; fall back to a full sync
+	}
+
+	fresh := &DeltaState{
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+		Events:      make(map[string]Event),
+	}
+	return cs.syncDelta(ctx, fresh, "")
+}
+
+func (cs *CalendarService) syncDelta(ctx context.Context, state *DeltaState, deltaLink string) (*DeltaState, error) {
+	headers := abstractions.NewRequestHeaders()
+	headers.Add("Prefer", fmt.Sprintf("outlook.timezone=%q", preferredTimeZone))
+	headers.Add("Prefer", fmt.Sprintf("odata.maxpagesize=%d", pageSize))
+
+	builder := cs.client.Me().CalendarView().Delta()
+	requestConfiguration := &users.ItemCalendarViewDeltaRequestBuilderGetRequestConfiguration{
+		Headers: headers,
+	}
+	if deltaLink != "" {
+		builder = builder.WithUrl(deltaLink)
+	} else {
+		startStr := state.WindowStart.UTC().Format("2006-01-02T15:04:05.000Z")
+		endStr := state.WindowEnd.UTC().Format("2006-01-02T15:04:05.000Z")
+		requestConfiguration.QueryParameters = &users.ItemCalendarViewDeltaRequestBuilderGetQueryParameters{
+			StartDateTime: &startStr,
+			EndDateTime:   &endStr,
+		}
+	}
+
+	response, err := builder.GetAsDeltaGetResponse(ctx, requestConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar delta: %w", err)
+	}
+
+	pageIterator, err := msgraphcore.NewPageIterator[models.Eventable](response, cs.client.GetAdapter(), users.CreateItemCalendarViewDeltaGetResponseFromDiscriminatorValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page iterator: %w", err)
+	}
+	pageIterator.SetHeaders(headers)
+
+	// Apply changes to a copy so a failed sync leaves the old state intact
+	events := make(map[string]Event, len(state.Events))
+	for id, event := range state.Events {
+		events[id] = event
+	}
+
+	// The cap counts the new and updated events of this sync, not the ones
+	// kept from the previous state, and removals are always drained; either
+	// would otherwise stop the sync before it reaches the delta link
+	changed := 0
+	err = pageIterator.Iterate(ctx, func(event models.Eventable) bool {
+		id := getStringValue(event.GetId())
+		if _, removed := event.GetAdditionalData()["@removed"]; removed {
+			delete(events, id)
+			return true
+		}
+		events[id] = cs.convert(event)
+		changed++
+		return changed < cs.maxEvents
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to page calendar delta: %w", err)
+	}
+
+	synced := make([]Event, 0, len(events))
+	for _, event := range events {
+		synced = append(synced, event)
+	}
+	cs.applyCategoryColors(ctx, synced)
+	for _, event := range synced {
+		events[event.ID] = event
+	}
+
+	next := &DeltaState{
+		WindowStart: state.WindowStart,
+		WindowEnd:   state.WindowEnd,
+		Events:      events,
+	}
+	if link := pageIterator.GetOdataDeltaLink(); link != nil {
+		next.DeltaLink = *link
+	}
+
+	return next, nil
+}
+
+// EventsBetween returns the synced events overlapping [start, end), ordered by start
+func (ds *DeltaState) EventsBetween(start, end time.Time) []Event {
+	var result []Event
+	for _, event := range ds.Events {
+		if event.End.After(start) && event.Start.Before(end) {
+			result = append(result, event)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return HideCancelled(Dedupe(result))
+}
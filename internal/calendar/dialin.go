@@ -0,0 +1,90 @@
+package calendar
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DialIn holds the audio conferencing details of a Teams or Zoom invite
+type DialIn struct {
+	// Number is the phone number as written in the invite, e.g. "+45 32 72 66 19"
+	Number string `json:"number"`
+	// ConferenceID is entered after dialing, without the trailing #
+	ConferenceID string `json:"conference_id,omitempty"`
+	// Passcode is the numeric Zoom passcode some dial-ins ask for
+	Passcode string `json:"passcode,omitempty"`
+}
+
+var (
+	// Teams and Zoom link their one-tap numbers as tel:+4532726619,,123456789#
+	telLinkRegex     = regexp.MustCompile(`tel:(\+?[0-9]+)((?:,+[0-9*#]+)*)`)
+	phoneNumberRegex = regexp.MustCompile(`\+[0-9][0-9 ()\-.]{6,}[0-9]`)
+	// Teams invites also carry a "Meeting ID" for the web client, so the
+	// phone conference ID wins when both are present
+	conferenceIDRegex = regexp.MustCompile(`(?i)(?:phone conference id|konference-id|id der telefonkonferenz)\s*:?\s*([0-9][0-9 ]*[0-9])`)
+	meetingIDRegex    = regexp.MustCompile(`(?i)meeting id\s*:?\s*([0-9][0-9 ]*[0-9])`)
+	passcodeRegex     = regexp.MustCompile(`(?i)(?:passcode|adgangskode|kenncode)\s*:?\s*([0-9]+)\b`)
+)
+
+// extractDialIn finds dial-in details in an online meeting's invite body,
+// converted to text. It returns nil when there is no phone number.
+func extractDialIn(text string) *DialIn {
+	if text == "" {
+		return nil
+	}
+
+	dialIn := &DialIn{}
+
+	if match := phoneNumberRegex.FindString(text); match != "" {
+		dialIn.Number = strings.TrimSpace(match)
+	}
+	if match := conferenceIDRegex.FindStringSubmatch(text); match != nil {
+		dialIn.ConferenceID = match[1]
+	} else if match := meetingIDRegex.FindStringSubmatch(text); match != nil {
+		dialIn.ConferenceID = match[1]
+	}
+	if match := passcodeRegex.FindStringSubmatch(text); match != nil {
+		dialIn.Passcode = match[1]
+	}
+
+	// A tel: link is the most reliable source when the text has none
+	if dialIn.Number == "" || dialIn.ConferenceID == "" {
+		if match := telLinkRegex.FindStringSubmatch(text); match != nil {
+			if dialIn.Number == "" {
+				dialIn.Number = match[1]
+			}
+			if dialIn.ConferenceID == "" {
+				parts := strings.FieldsFunc(match[2], func(r rune) bool { return r == ',' })
+				if len(parts) > 0 {
+					dialIn.ConferenceID = strings.TrimSuffix(parts[0], "#")
+				}
+			}
+		}
+	}
+
+	if dialIn.Number == "" {
+		return nil
+	}
+	return dialIn
+}
+
+// TelURI returns a tel: URI that dials the number and, after a pause,
+// enters the conference ID and passcode
+func (d *DialIn) TelURI() string {
+	number := strings.Map(func(r rune) rune {
+		if r == '+' || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, d.Number)
+
+	uri := "tel:" + number
+	if id := strings.ReplaceAll(d.ConferenceID, " ", ""); id != "" {
+		uri += ",," + id + url.QueryEscape("#")
+	}
+	if d.Passcode != "" {
+		uri += ",," + d.Passcode + url.QueryEscape("#")
+	}
+	return uri
+}
@@ -0,0 +1,15 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+)
+
+// DismissReminder dismisses the event's reminder in Outlook. This requires
+// the Calendars.ReadWrite scope.
+func (cs *CalendarService) DismissReminder(ctx context.Context, eventID string) error {
+	if err := cs.client.Me().Events().ByEventId(eventID).DismissReminder().Post(ctx, nil); err != nil {
+		return fmt.Errorf("failed to dismiss reminder: %w", err)
+	}
+	return nil
+}
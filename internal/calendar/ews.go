@@ -0,0 +1,405 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/auth"
+	"calendar-widget/internal/network"
+
+	"github.com/Azure/go-ntlmssp"
+)
+
+// ewsTimeFormat is the xs:dateTime format EWS expects in requests
+const ewsTimeFormat = "2006-01-02T15:04:05Z"
+
+// EWSProvider reads the calendar from on-premises Exchange through Exchange
+// Web Services, for mailboxes that Microsoft Graph can't reach
+type EWSProvider struct {
+	url       string
+	username  string
+	password  string
+	client    *http.Client
+	maxEvents int
+}
+
+// NewEWSProvider creates an EWS provider authenticating with NTLM or Basic
+func NewEWSProvider(config *auth.EWSConfig) (*EWSProvider, error) {
+	if config == nil || config.URL == "" {
+		return nil, fmt.Errorf("ews.url is not set in config.json")
+	}
+
+	password, err := config.GetPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	var transport http.RoundTripper
+	switch strings.ToLower(config.AuthType) {
+	case "", "ntlm":
+		transport = ntlmssp.Negotiator{RoundTripper: network.Transport()}
+	case "basic":
+		transport = network.Transport()
+	default:
+		return nil, fmt.Errorf("unknown ews.auth_type %q: use ntlm or basic", config.AuthType)
+	}
+
+	return &EWSProvider{
+		url:       config.URL,
+		username:  config.Username,
+		password:  password,
+		client:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		maxEvents: DefaultMaxEvents,
+	}, nil
+}
+
+// SetMaxEvents sets the safety cap on events fetched per range query
+func (ep *EWSProvider) SetMaxEvents(maxEvents int) {
+	if maxEvents > 0 {
+		ep.maxEvents = maxEvents
+	}
+}
+
+func (ep *EWSProvider) GetTodaysEvents(ctx context.Context) ([]Event, error) {
+	now := Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return ep.GetEventsBetween(ctx, startOfDay, startOfDay.Add(24*time.Hour))
+}
+
+func (ep *EWSProvider) GetUpcomingEvents(ctx context.Context) ([]Event, error) {
+	now := Now()
+	return ep.GetEventsBetween(ctx, now, now.Add(Lookahead()))
+}
+
+func (ep *EWSProvider) GetNextMeeting(ctx context.Context, opts NextMeetingOptions) (*Event, error) {
+	events, err := ep.GetUpcomingEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return SelectNextMeeting(events, Now(), opts), nil
+}
+
+// FindNextFreeSlot returns the next gap of at least minDuration in today's
+// blocking events, or nil if the rest of the day is booked
+func (ep *EWSProvider) FindNextFreeSlot(ctx context.Context, minDuration time.Duration) (*FreeSlot, error) {
+	events, err := ep.GetTodaysEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return NextFreeSlot(events, now, startOfDay.Add(24*time.Hour), minDuration), nil
+}
+
+// GetEventsBetween returns the events overlapping [start, end). FindItem
+// can't return bodies, so a GetItem call fetches them (and attendees) for
+// join link extraction.
+func (ep *EWSProvider) GetEventsBetween(ctx context.Context, start, end time.Time) ([]Event, error) {
+	found, err := ep.call(ctx, fmt.Sprintf(ewsFindItemBody, ep.maxEvents, start.UTC().Format(ewsTimeFormat), end.UTC().Format(ewsTimeFormat)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar view: %w", err)
+	}
+
+	items, err := found.Body.FindItem.items()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar view: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	details, err := ep.getDetails(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(items))
+	for _, item := range items {
+		if detail, ok := details[item.ItemID.ID]; ok {
+			item.Body = detail.Body
+			item.RequiredAttendees = detail.RequiredAttendees
+			item.OptionalAttendees = detail.OptionalAttendees
+		}
+		events = append(events, item.toEvent())
+	}
+
+	return HideCancelled(Dedupe(events)), nil
+}
+
+// getDetails fetches bodies and attendees for items, keyed by item ID
+func (ep *EWSProvider) getDetails(ctx context.Context, items []ewsCalendarItem) (map[string]ewsCalendarItem, error) {
+	var ids strings.Builder
+	for _, item := range items {
+		ids.WriteString(`<t:ItemId Id="`)
+		xml.EscapeText(&ids, []byte(item.ItemID.ID))
+		ids.WriteString(`"/>`)
+	}
+
+	got, err := ep.call(ctx, fmt.Sprintf(ewsGetItemBody, ids.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event details: %w", err)
+	}
+
+	detailed, err := got.Body.GetItem.items()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event details: %w", err)
+	}
+
+	details := make(map[string]ewsCalendarItem, len(detailed))
+	for _, item := range detailed {
+		details[item.ItemID.ID] = item
+	}
+	return details, nil
+}
+
+// call posts a SOAP body and decodes the envelope
+func (ep *EWSProvider) call(ctx context.Context, body string) (*ewsEnvelope, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.url, strings.NewReader(fmt.Sprintf(ewsEnvelopeFormat, body)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.SetBasicAuth(ep.username, ep.password)
+
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("exchange authentication failed: check ews.username and password")
+	case http.StatusTooManyRequests:
+		return nil, &ewsStatusError{statusCode: resp.StatusCode}
+	}
+
+	var envelope ewsEnvelope
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("exchange returned %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to parse exchange response: %w", err)
+	}
+	if fault := envelope.Body.Fault; fault != nil {
+		return nil, fmt.Errorf("exchange fault: %s", fault.String)
+	}
+
+	return &envelope, nil
+}
+
+// ewsStatusError carries the HTTP status so IsRateLimited works for EWS too
+type ewsStatusError struct {
+	statusCode int
+}
+
+func (e *ewsStatusError) Error() string {
+	return fmt.Sprintf("exchange returned HTTP %d", e.statusCode)
+}
+
+func (e *ewsStatusError) GetStatusCode() int {
+	return e.statusCode
+}
+
+const ewsEnvelopeFormat = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+               xmlns:t="http://schemas.microsoft.com/exchange/services/2006/types"
+               xmlns:m="http://schemas.microsoft.com/exchange/services/2006/messages">
+  <soap:Header>
+    <t:RequestServerVersion Version="Exchange2013"/>
+  </soap:Header>
+  <soap:Body>%s</soap:Body>
+</soap:Envelope>`
+
+const ewsFindItemBody = `<m:FindItem Traversal="Shallow">
+  <m:ItemShape>
+    <t:BaseShape>IdOnly</t:BaseShape>
+    <t:AdditionalProperties>
+      <t:FieldURI FieldURI="item:Subject"/>
+      <t:FieldURI FieldURI="item:Categories"/>
+      <t:FieldURI FieldURI="item:Sensitivity"/>
+      <t:FieldURI FieldURI="item:WebClientReadFormQueryString"/>
+      <t:FieldURI FieldURI="calendar:Start"/>
+      <t:FieldURI FieldURI="calendar:End"/>
+      <t:FieldURI FieldURI="calendar:Location"/>
+      <t:FieldURI FieldURI="calendar:IsAllDayEvent"/>
+      <t:FieldURI FieldURI="calendar:LegacyFreeBusyStatus"/>
+      <t:FieldURI FieldURI="calendar:Organizer"/>
+      <t:FieldURI FieldURI="calendar:MyResponseType"/>
+      <t:FieldURI FieldURI="calendar:CalendarItemType"/>
+      <t:FieldURI FieldURI="calendar:UID"/>
+      <t:FieldURI FieldURI="calendar:IsCancelled"/>
+      <t:FieldURI FieldURI="item:HasAttachments"/>
+      <t:FieldURI FieldURI="item:ReminderIsSet"/>
+      <t:FieldURI FieldURI="item:ReminderMinutesBeforeStart"/>
+    </t:AdditionalProperties>
+  </m:ItemShape>
+  <m:CalendarView MaxEntriesReturned="%d" StartDate="%s" EndDate="%s"/>
+  <m:ParentFolderIds>
+    <t:DistinguishedFolderId Id="calendar"/>
+  </m:ParentFolderIds>
+</m:FindItem>`
+
+const ewsGetItemBody = `<m:GetItem>
+  <m:ItemShape>
+    <t:BaseShape>IdOnly</t:BaseShape>
+    <t:BodyType>Text</t:BodyType>
+    <t:AdditionalProperties>
+      <t:FieldURI FieldURI="item:Body"/>
+      <t:FieldURI FieldURI="calendar:RequiredAttendees"/>
+      <t:FieldURI FieldURI="calendar:OptionalAttendees"/>
+    </t:AdditionalProperties>
+  </m:ItemShape>
+  <m:ItemIds>%s</m:ItemIds>
+</m:GetItem>`
+
+// ewsEnvelope matches FindItem and GetItem responses by local element name
+type ewsEnvelope struct {
+	Body struct {
+		Fault *struct {
+			String string `xml:"faultstring"`
+		} `xml:"Fault"`
+		FindItem ewsResponse `xml:"FindItemResponse"`
+		GetItem  ewsResponse `xml:"GetItemResponse"`
+	} `xml:"Body"`
+}
+
+type ewsResponse struct {
+	FindMessages []ewsResponseMessage `xml:"ResponseMessages>FindItemResponseMessage"`
+	GetMessages  []ewsResponseMessage `xml:"ResponseMessages>GetItemResponseMessage"`
+}
+
+type ewsResponseMessage struct {
+	ResponseClass string            `xml:"ResponseClass,attr"`
+	ResponseCode  string            `xml:"ResponseCode"`
+	MessageText   string            `xml:"MessageText"`
+	FolderItems   []ewsCalendarItem `xml:"RootFolder>Items>CalendarItem"`
+	Items         []ewsCalendarItem `xml:"Items>CalendarItem"`
+}
+
+// items collects the calendar items of all response messages, failing on
+// the first error message
+func (r ewsResponse) items() ([]ewsCalendarItem, error) {
+	var items []ewsCalendarItem
+	for _, message := range append(r.FindMessages, r.GetMessages...) {
+		if message.ResponseClass == "Error" {
+			return nil, fmt.Errorf("%s: %s", message.ResponseCode, message.MessageText)
+		}
+		items = append(items, message.FolderItems...)
+		items = append(items, message.Items...)
+	}
+	return items, nil
+}
+
+type ewsCalendarItem struct {
+	ItemID struct {
+		ID string `xml:"Id,attr"`
+	} `xml:"ItemId"`
+	Subject              string        `xml:"Subject"`
+	Body                 string        `xml:"Body"`
+	Categories           []string      `xml:"Categories>String"`
+	Sensitivity          string        `xml:"Sensitivity"`
+	WebLink              string        `xml:"WebClientReadFormQueryString"`
+	Start                time.Time     `xml:"Start"`
+	End                  time.Time     `xml:"End"`
+	Location             string        `xml:"Location"`
+	IsAllDayEvent        bool          `xml:"IsAllDayEvent"`
+	LegacyFreeBusyStatus string        `xml:"LegacyFreeBusyStatus"`
+	Organizer            string        `xml:"Organizer>Mailbox>Name"`
+	MyResponseType       string        `xml:"MyResponseType"`
+	CalendarItemType     string        `xml:"CalendarItemType"`
+	UID                  string        `xml:"UID"`
+	IsCancelled          bool          `xml:"IsCancelled"`
+	HasAttachments       bool          `xml:"HasAttachments"`
+	ReminderIsSet        bool          `xml:"ReminderIsSet"`
+	ReminderMinutes      int           `xml:"ReminderMinutesBeforeStart"`
+	RequiredAttendees    []ewsAttendee `xml:"RequiredAttendees>Attendee"`
+	OptionalAttendees    []ewsAttendee `xml:"OptionalAttendees>Attendee"`
+}
+
+type ewsAttendee struct {
+	Name         string `xml:"Mailbox>Name"`
+	ResponseType string `xml:"ResponseType"`
+}
+
+// EWS enumerations mapped onto the Graph values used by Event
+var (
+	ewsShowAs = map[string]string{
+		"Free":             "free",
+		"Tentative":        "tentative",
+		"Busy":             "busy",
+		"OOF":              "oof",
+		"WorkingElsewhere": "workingElsewhere",
+		"NoData":           "unknown",
+	}
+	ewsResponseStatus = map[string]string{
+		"Unknown":            "none",
+		"Organizer":          "organizer",
+		"Tentative":          "tentativelyAccepted",
+		"Accept":             "accepted",
+		"Decline":            "declined",
+		"NoResponseReceived": "notResponded",
+	}
+	ewsItemType = map[string]string{
+		"Single":          EventTypeSingle,
+		"Occurrence":      EventTypeOccurrence,
+		"Exception":       EventTypeException,
+		"RecurringMaster": EventTypeSeriesMaster,
+	}
+)
+
+func (item ewsCalendarItem) toEvent() Event {
+	e := Event{
+		ID:              item.ItemID.ID,
+		ICalUID:         item.UID,
+		Subject:         item.Subject,
+		Location:        item.Location,
+		WebLink:         item.WebLink,
+		Body:            item.Body,
+		IsAllDay:        item.IsAllDayEvent,
+		Organizer:       item.Organizer,
+		Categories:      item.Categories,
+		ShowAs:          ewsShowAs[item.LegacyFreeBusyStatus],
+		ResponseStatus:  ewsResponseStatus[item.MyResponseType],
+		Type:            ewsItemType[item.CalendarItemType],
+		Sensitivity:     strings.ToLower(item.Sensitivity),
+		Start:           item.Start.Local(),
+		End:             item.End.Local(),
+		ReminderOn:      item.ReminderIsSet,
+		ReminderMinutes: item.ReminderMinutes,
+		IsCancelled:     item.IsCancelled,
+		HasAttachments:  item.HasAttachments,
+	}
+
+	for _, attendee := range append(item.RequiredAttendees, item.OptionalAttendees...) {
+		e.Attendees = append(e.Attendees, attendee.Name)
+		if response, ok := ewsResponseStatus[attendee.ResponseType]; ok {
+			if e.AttendeeResponses == nil {
+				e.AttendeeResponses = make(map[string]string)
+			}
+			e.AttendeeResponses[attendee.Name] = response
+		}
+	}
+
+	// All-day items start at midnight in the mailbox's zone
+	if e.IsAllDay {
+		e.Start = time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, time.Local)
+		e.End = time.Date(e.End.Year(), e.End.Month(), e.End.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	fillEventLinks(&e)
+
+	return e
+}
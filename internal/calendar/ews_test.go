@@ -0,0 +1,237 @@
+package calendar
+
+import (
+	"calendar-widget/internal/auth"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEWSRecording reads a recorded SOAP response from testdata
+func readEWSRecording(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "ews", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// serveEWS answers FindItem and GetItem requests with the given status and
+// recordings, checking the basic auth credentials on the way
+func serveEWS(t *testing.T, status int, findItem, getItem string) *EWSProvider {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != `CONTOSO\kim` || password != "hunter2" {
+			t.Errorf("basic auth = %q, %q, %v", username, password, ok)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var name string
+		switch {
+		case strings.Contains(string(body), "<m:FindItem"):
+			name = findItem
+		case strings.Contains(string(body), "<m:GetItem"):
+			name = getItem
+		}
+		if name == "" {
+			t.Errorf("unexpected request: %s", body)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.WriteHeader(status)
+		w.Write(readEWSRecording(t, name))
+	}))
+	t.Cleanup(srv.Close)
+
+	provider, err := NewEWSProvider(&auth.EWSConfig{URL: srv.URL, Username: `CONTOSO\kim`, Password: "hunter2", AuthType: "basic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestEWSGetEventsBetween(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	local := time.Local
+	time.Local = berlin
+	t.Cleanup(func() { time.Local = local })
+
+	provider := serveEWS(t, http.StatusOK, "finditem.xml", "getitem.xml")
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, berlin)
+	events, err := provider.GetEventsBetween(context.Background(), start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		start, end  string
+		allDay      bool
+		showAs      string
+		response    string
+		eventType   string
+		sensitivity string
+		organizer   string
+		join        string
+	}
+	// The cancelled "Budget sync" is hidden
+	wants := map[string]want{
+		// An occurrence with a Zoom link in its body
+		"Team standup": {start: "2025-06-02T08:30:00Z", end: "2025-06-02T08:45:00Z", showAs: "busy", response: "tentativelyAccepted", eventType: EventTypeOccurrence, sensitivity: "private", organizer: "Robin Diaz", join: "https://contoso.zoom.us/j/81234567890?pwd=aBcDeFgH"},
+		// Escaped characters in the subject, a Teams link in the body
+		"Architecture review & Q&A": {start: "2025-06-02T09:00:00Z", end: "2025-06-02T10:00:00Z", showAs: "busy", response: "accepted", eventType: EventTypeSingle, sensitivity: "normal", organizer: "Kim Lee", join: "https://teams.microsoft.com/l/meetup-join/19%3ameeting_NzA2%40thread.v2/0?context=%7b%22Tid%22%3a%22c0ffee%22%7d"},
+		// Times with a UTC offset instead of Z
+		"Sprint planning": {start: "2025-06-02T13:00:00Z", end: "2025-06-02T14:30:00Z", showAs: "workingElsewhere", response: "organizer", eventType: EventTypeException, sensitivity: "confidential", organizer: "Ada Ng"},
+		// All-day, sent as UTC instants for local midnight
+		"Whit Monday": {start: "2025-06-09T00:00:00+02:00", end: "2025-06-10T00:00:00+02:00", allDay: true, showAs: "oof", response: "organizer", eventType: EventTypeSingle, sensitivity: "normal", organizer: "Ada Ng"},
+	}
+
+	if len(events) != len(wants) {
+		t.Fatalf("got %d events, want %d: %v", len(events), len(wants), subjects(events))
+	}
+	for _, event := range events {
+		w, ok := wants[event.Subject]
+		if !ok {
+			t.Errorf("unexpected event %q", event.Subject)
+			continue
+		}
+		layout := time.RFC3339
+		gotStart, gotEnd := event.Start.UTC().Format(layout), event.End.UTC().Format(layout)
+		if w.allDay {
+			gotStart, gotEnd = event.Start.Format(layout), event.End.Format(layout)
+		}
+		if gotStart != w.start || gotEnd != w.end {
+			t.Errorf("%s: time = %s–%s, want %s–%s", event.Subject, gotStart, gotEnd, w.start, w.end)
+		}
+		if event.IsAllDay != w.allDay {
+			t.Errorf("%s: all day = %v, want %v", event.Subject, event.IsAllDay, w.allDay)
+		}
+		if event.ShowAs != w.showAs {
+			t.Errorf("%s: show as = %q, want %q", event.Subject, event.ShowAs, w.showAs)
+		}
+		if event.ResponseStatus != w.response {
+			t.Errorf("%s: response = %q, want %q", event.Subject, event.ResponseStatus, w.response)
+		}
+		if event.Type != w.eventType {
+			t.Errorf("%s: type = %q, want %q", event.Subject, event.Type, w.eventType)
+		}
+		if event.Sensitivity != w.sensitivity {
+			t.Errorf("%s: sensitivity = %q, want %q", event.Subject, event.Sensitivity, w.sensitivity)
+		}
+		if event.Organizer != w.organizer {
+			t.Errorf("%s: organizer = %q, want %q", event.Subject, event.Organizer, w.organizer)
+		}
+		if got := event.GetJoinLink(); got != w.join {
+			t.Errorf("%s: join link = %q, want %q", event.Subject, got, w.join)
+		}
+	}
+
+	review := findSubject(events, "Architecture review & Q&A")
+	if review == nil {
+		return
+	}
+	if !review.IsTeams {
+		t.Error("review: not detected as a Teams meeting")
+	}
+	if review.ID != "AAMkADk0Yz-review" || review.ICalUID != "040000008200E00074C5B7101A82E00800000000-review" {
+		t.Errorf("review: ids = %q, %q", review.ID, review.ICalUID)
+	}
+	if review.Location != "Room 4.12" {
+		t.Errorf("review: location = %q", review.Location)
+	}
+	if want := "https://mail.contoso.com/owa/?ItemID=AAMkADk0Yz-review&exvsurl=1&viewmodel=CalendarItem"; review.WebLink != want {
+		t.Errorf("review: web link = %q, want %q", review.WebLink, want)
+	}
+	if want := []string{"Blue category", "Customer"}; !reflect.DeepEqual(review.Categories, want) {
+		t.Errorf("review: categories = %q, want %q", review.Categories, want)
+	}
+	if !review.ReminderOn || review.ReminderMinutes != 10 || !review.HasAttachments {
+		t.Errorf("review: reminder = %v %d, attachments = %v", review.ReminderOn, review.ReminderMinutes, review.HasAttachments)
+	}
+	// Required attendees come first; an unknown response type is left out
+	if want := []string{"Kim Lee", "Ada Ng", "Sam Roe", "Meeting Room 4.12"}; !reflect.DeepEqual(review.Attendees, want) {
+		t.Errorf("review: attendees = %q, want %q", review.Attendees, want)
+	}
+	if want := map[string]string{"Kim Lee": "organizer", "Ada Ng": "accepted", "Sam Roe": "declined"}; !reflect.DeepEqual(review.AttendeeResponses, want) {
+		t.Errorf("review: attendee responses = %v, want %v", review.AttendeeResponses, want)
+	}
+}
+
+func TestEWSErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		recording string
+		want      string
+		limited   bool
+	}{
+		{
+			name:      "response message error",
+			status:    http.StatusOK,
+			recording: "error.xml",
+			want:      "failed to get calendar view: ErrorFolderNotFound: The specified folder could not be found in the store.",
+		},
+		{
+			name:      "soap fault",
+			status:    http.StatusInternalServerError,
+			recording: "fault.xml",
+			want:      "failed to get calendar view: exchange fault: The request failed schema validation: The 'MaxEntriesReturned' attribute is invalid.",
+		},
+		{
+			name:      "unauthorized",
+			status:    http.StatusUnauthorized,
+			recording: "fault.xml",
+			want:      "failed to get calendar view: exchange authentication failed: check ews.username and password",
+		},
+		{
+			name:      "throttled",
+			status:    http.StatusTooManyRequests,
+			recording: "fault.xml",
+			want:      "failed to get calendar view: exchange returned HTTP 429",
+			limited:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := serveEWS(t, tt.status, tt.recording, tt.recording)
+			_, err := provider.GetEventsBetween(context.Background(), time.Now(), time.Now().Add(24*time.Hour))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", err, tt.want)
+			}
+			if IsRateLimited(err) != tt.limited {
+				t.Errorf("IsRateLimited = %v, want %v", IsRateLimited(err), tt.limited)
+			}
+		})
+	}
+}
+
+func subjects(events []Event) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.Subject)
+	}
+	return result
+}
+
+func findSubject(events []Event, subject string) *Event {
+	for i := range events {
+		if events[i].Subject == subject {
+			return &events[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// FakeProvider serves events from a JSON fixture instead of Microsoft Graph.
+// It is used for demos and for testing rendering without an account.
+type FakeProvider struct {
+	events    []Event
+	rooms     []Room
+	schedules map[string][]BusyPeriod
+}
+
+// Fixture is the fake calendar file format. When Now is set,
+// LoadFakeProvider shifts all event times so that Now maps to the current
+// time, which keeps a fixture looking the same whenever it is loaded.
+type Fixture struct {
+	Now    time.Time `json:"now"`
+	Events []Event   `json:"events"`
+	// Rooms and Schedules stand in for the room directory and other
+	// people's free/busy, keyed by email address
+	Rooms     []Room                  `json:"rooms,omitempty"`
+	Schedules map[string][]BusyPeriod `json:"schedules,omitempty"`
+}
+
+// NewFakeProvider returns a provider serving the given events
+func NewFakeProvider(events []Event) *FakeProvider {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	return &FakeProvider{events: sorted}
+}
+
+// LoadFakeProvider reads a fixture file and serves its events relative to
+// the current time
+func LoadFakeProvider(path string) (*FakeProvider, error) {
+	fixture, err := ReadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anchor to the wall clock rather than the package clock, so --at can
+	// preview a shifted fixture at another time of day
+	if !fixture.Now.IsZero() {
+		offset := time.Since(fixture.Now)
+		for i := range fixture.Events {
+			shiftEvent(&fixture.Events[i], offset)
+		}
+		for _, busy := range fixture.Schedules {
+			for i := range busy {
+				busy[i].Start = busy[i].Start.Add(offset)
+				busy[i].End = busy[i].End.Add(offset)
+			}
+		}
+	}
+
+	provider := NewFakeProvider(fixture.Events)
+	provider.rooms = fixture.Rooms
+	provider.schedules = fixture.Schedules
+	return provider, nil
+}
+
+// ReadFixture reads a fixture file without shifting it. It accepts either an
+// object with "now" and "events" or a bare array of events. Times are
+// converted to the local zone and missing IDs and join links are filled in.
+func ReadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake calendar data: %w", err)
+	}
+
+	var fixture Fixture
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &fixture.Events)
+	} else {
+		err = json.Unmarshal(data, &fixture)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fake calendar data: %w", err)
+	}
+
+	for i := range fixture.Events {
+		localizeEvent(&fixture.Events[i])
+		if fixture.Events[i].ID == "" {
+			fixture.Events[i].ID = fmt.Sprintf("fake-%d", i+1)
+		}
+		fillEventLinks(&fixture.Events[i])
+	}
+	for _, busy := range fixture.Schedules {
+		for i := range busy {
+			busy[i].Start = busy[i].Start.Local()
+			busy[i].End = busy[i].End.Local()
+		}
+	}
+
+	return &fixture, nil
+}
+
+func shiftEvent(event *Event, offset time.Duration) {
+	event.Start = event.Start.Add(offset)
+	event.End = event.End.Add(offset)
+	if !event.OriginalStart.IsZero() {
+		event.OriginalStart = event.OriginalStart.Add(offset)
+	}
+}
+
+// localizeEvent converts event times to the local zone, as the Graph provider does
+func localizeEvent(event *Event) {
+	event.Start = event.Start.Local()
+	event.End = event.End.Local()
+	if !event.OriginalStart.IsZero() {
+		event.OriginalStart = event.OriginalStart.Local()
+	}
+}
+
+// fillEventLinks derives the text body, join links and dial-in details from
+// the body and location, like the Graph provider does, for fixtures that
+// don't set them, and normalizes the links
+func fillEventLinks(event *Event) {
+	if event.BodyText == "" {
+		event.BodyText = htmlToText(event.Body)
+	}
+	if event.TeamsLink == "" {
+		event.TeamsLink, event.IsTeams = extractTeamsLink(event.BodyText, event.Location)
+	}
+	if event.ZoomLink == "" {
+		event.ZoomLink = extractZoomLink(event.BodyText, event.Location)
+	}
+	if event.DialIn == nil && (event.IsTeams || event.ZoomLink != "") {
+		event.DialIn = extractDialIn(event.BodyText)
+	}
+	normalizeEventLinks(event)
+}
+
+func (fp *FakeProvider) GetTodaysEvents(ctx context.Context) ([]Event, error) {
+	now := Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return fp.GetEventsBetween(ctx, startOfDay, startOfDay.Add(24*time.Hour))
+}
+
+func (fp *FakeProvider) GetUpcomingEvents(ctx context.Context) ([]Event, error) {
+	now := Now()
+	return fp.GetEventsBetween(ctx, now, now.Add(Lookahead()))
+}
+
+// GetEventsBetween returns the events overlapping [start, end)
+func (fp *FakeProvider) GetEventsBetween(ctx context.Context, start, end time.Time) ([]Event, error) {
+	var events []Event
+	for _, event := range fp.events {
+		if event.End.After(start) && event.Start.Before(end) {
+			events = append(events, event)
+		}
+	}
+	return HideCancelled(events), nil
+}
+
+func (fp *FakeProvider) GetNextMeeting(ctx context.Context, opts NextMeetingOptions) (*Event, error) {
+	events, err := fp.GetUpcomingEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return SelectNextMeeting(events, Now(), opts), nil
+}
+
+// FindNextFreeSlot returns the next gap of at least minDuration in today's
+// blocking events, or nil if the rest of the day is booked
+func (fp *FakeProvider) FindNextFreeSlot(ctx context.Context, minDuration time.Duration) (*FreeSlot, error) {
+	events, err := fp.GetTodaysEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return NextFreeSlot(events, now, startOfDay.Add(24*time.Hour), minDuration), nil
+}
+
+// FindRooms returns the fixture's rooms
+func (fp *FakeProvider) FindRooms(ctx context.Context) ([]Room, error) {
+	return fp.rooms, nil
+}
+
+// GetSchedules returns the fixture's busy periods overlapping [start, end)
+func (fp *FakeProvider) GetSchedules(ctx context.Context, emails []string, start, end time.Time) ([]Schedule, error) {
+	schedules := make([]Schedule, 0, len(emails))
+	for _, email := range emails {
+		schedule := Schedule{Email: email}
+		for _, busy := range fp.schedules[email] {
+			if busy.Start.Before(end) && busy.End.After(start) {
+				schedule.Busy = append(schedule.Busy, busy)
+			}
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
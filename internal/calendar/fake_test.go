@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFixture(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFixture(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "array",
+			data: `[
+				{"Subject": "Review", "Start": "2025-06-02T10:00:00Z", "End": "2025-06-02T11:00:00Z"},
+				{"Subject": "Standup", "Start": "2025-06-02T09:00:00Z", "End": "2025-06-02T09:15:00Z",
+				 "Body": "Join: https://teams.microsoft.com/l/meetup-join/19%3ameeting_1%40thread.v2/0"}
+			]`,
+		},
+		{
+			name: "object",
+			data: `{"events": [
+				{"Subject": "Review", "Start": "2025-06-02T10:00:00Z", "End": "2025-06-02T11:00:00Z"},
+				{"Subject": "Standup", "Start": "2025-06-02T09:00:00Z", "End": "2025-06-02T09:15:00Z",
+				 "Body": "Join: https://teams.microsoft.com/l/meetup-join/19%3ameeting_1%40thread.v2/0"}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture, err := ReadFixture(writeFixture(t, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !fixture.Now.IsZero() {
+				t.Errorf("now = %v, want zero", fixture.Now)
+			}
+			if len(fixture.Events) != 2 {
+				t.Fatalf("got %d events, want 2", len(fixture.Events))
+			}
+			if got := fixture.Events[0].ID; got != "fake-1" {
+				t.Errorf("first ID = %q, want fake-1", got)
+			}
+			standup := fixture.Events[1]
+			if !standup.IsTeams || standup.TeamsLink == "" {
+				t.Errorf("teams link %q (IsTeams %v), want it extracted from the body", standup.TeamsLink, standup.IsTeams)
+			}
+			if standup.Start.Location() != time.Local {
+				t.Errorf("start in %v, want the local zone", standup.Start.Location())
+			}
+		})
+	}
+
+	if _, err := ReadFixture(writeFixture(t, `{"events": [`)); err == nil {
+		t.Error("ReadFixture accepted malformed JSON")
+	}
+}
+
+func TestLoadFakeProviderShiftsToNow(t *testing.T) {
+	path := writeFixture(t, `{
+		"now": "2025-06-02T09:50:00Z",
+		"events": [
+			{"ID": "later", "Subject": "Review", "Start": "2025-06-02T11:00:00Z", "End": "2025-06-02T12:00:00Z"},
+			{"ID": "soon", "Subject": "Standup", "Start": "2025-06-02T10:00:00Z", "End": "2025-06-02T10:15:00Z",
+			 "Type": "exception", "OriginalStart": "2025-06-02T09:30:00Z"}
+		],
+		"schedules": {"ada@example.com": [{"Start": "2025-06-02T10:00:00Z", "End": "2025-06-02T10:30:00Z"}]}
+	}`)
+
+	before := time.Now()
+	provider, err := LoadFakeProvider(path)
+	after := time.Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	within := func(got time.Time, offset time.Duration) bool {
+		return !got.Before(before.Add(offset)) && !got.After(after.Add(offset))
+	}
+
+	if len(provider.events) != 2 || provider.events[0].ID != "soon" {
+		t.Fatalf("events %v, want sorted by start", ids(provider.events))
+	}
+	soon := provider.events[0]
+	if !within(soon.Start, 10*time.Minute) {
+		t.Errorf("start %v, want 10 minutes from now", soon.Start)
+	}
+	if got := soon.End.Sub(soon.Start); got != 15*time.Minute {
+		t.Errorf("duration %v, want 15m", got)
+	}
+	if !within(soon.OriginalStart, -20*time.Minute) {
+		t.Errorf("original start %v, want 20 minutes ago", soon.OriginalStart)
+	}
+
+	busy := provider.schedules["ada@example.com"]
+	if len(busy) != 1 || !within(busy[0].Start, 10*time.Minute) {
+		t.Errorf("busy %v, want shifted to 10 minutes from now", busy)
+	}
+}
+
+func TestFakeProviderGetEventsBetween(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 6, 2, hour, minute, 0, 0, time.UTC)
+	}
+	provider := NewFakeProvider([]Event{
+		{ID: "standup", Start: at(9, 0), End: at(10, 0)},
+		{ID: "review", Start: at(10, 0), End: at(11, 0)},
+		{ID: "cancelled", Start: at(10, 0), End: at(11, 0), IsCancelled: true},
+		{ID: "lunch", Start: at(12, 0), End: at(12, 30)},
+	})
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       []string
+	}{
+		{name: "end is exclusive", start: at(8, 0), end: at(9, 0), want: nil},
+		{name: "start is exclusive of events ending there", start: at(10, 0), end: at(10, 30), want: []string{"review"}},
+		{name: "partial overlap", start: at(9, 59), end: at(10, 1), want: []string{"standup", "review"}},
+		{name: "inside an event", start: at(12, 10), end: at(12, 20), want: []string{"lunch"}},
+		{name: "whole day", start: at(0, 0), end: at(23, 59), want: []string{"standup", "review", "lunch"}},
+		{name: "gap", start: at(11, 0), end: at(12, 0), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := provider.GetEventsBetween(context.Background(), tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(events)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
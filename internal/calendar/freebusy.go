@@ -0,0 +1,72 @@
+package calendar
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// FreeSlot is a gap between blocking events
+type FreeSlot struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (fs *FreeSlot) GetDuration() time.Duration {
+	return fs.End.Sub(fs.Start)
+}
+
+// IsNow reports whether the slot has already started
+func (fs *FreeSlot) IsNow() bool {
+	return !fs.Start.After(Now())
+}
+
+// FindNextFreeSlot returns the next gap of at least minDuration in today's
+// blocking events, or nil if the rest of the day is booked
+func (cs *CalendarService) FindNextFreeSlot(ctx context.Context, minDuration time.Duration) (*FreeSlot, error) {
+	events, err := cs.GetTodaysEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	return NextFreeSlot(events, now, endOfDay, minDuration), nil
+}
+
+// NextFreeSlot walks the blocking events between from and until and returns
+// the first gap that is at least minDuration long
+func NextFreeSlot(events []Event, from, until time.Time, minDuration time.Duration) *FreeSlot {
+	var busy []Event
+	for _, event := range events {
+		if !event.IsBlockingEvent() {
+			continue
+		}
+		if !event.End.After(from) || !event.Start.Before(until) {
+			continue
+		}
+		busy = append(busy, event)
+	}
+
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	cursor := from
+	for _, event := range busy {
+		if event.Start.After(cursor) && event.Start.Sub(cursor) >= minDuration {
+			return &FreeSlot{Start: cursor, End: event.Start}
+		}
+		if event.End.After(cursor) {
+			cursor = event.End
+		}
+	}
+
+	if until.Sub(cursor) >= minDuration {
+		return &FreeSlot{Start: cursor, End: until}
+	}
+
+	return nil
+}
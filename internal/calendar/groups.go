@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	abstractions "github.com/microsoft/kiota-abstractions-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/groups"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+// GroupScope is the permission group calendars need. Most tenants only
+// grant it with an admin's consent.
+const GroupScope = "https://graph.microsoft.com/Group.Read.All"
+
+// groupIDPattern matches group object IDs, which are GUIDs
+var groupIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var groupCalendars []string
+
+// SetGroupCalendars sets the Microsoft 365 groups, by ID or display name,
+// whose calendars are merged into the user's own by Graph services created
+// afterwards
+func SetGroupCalendars(refs []string) {
+	groupCalendars = refs
+}
+
+// groupCalendar is a group whose calendar a service reads. ref is what the
+// settings name it by; id and name are looked up on first use.
+type groupCalendar struct {
+	ref  string
+	id   string
+	name string
+}
+
+// addGroupCalendars creates a service per configured group calendar,
+// sharing this service's client
+func (cs *CalendarService) addGroupCalendars(refs []string) {
+	for _, ref := range refs {
+		cs.merged = append(cs.merged, &CalendarService{
+			client:      cs.client,
+			maxEvents:   cs.maxEvents,
+			accountType: cs.accountType,
+			group:       &groupCalendar{ref: ref},
+		})
+	}
+}
+
+// getGroupCalendarView returns the first page of the group's calendar view
+func (cs *CalendarService) getGroupCalendarView(ctx context.Context, headers *abstractions.RequestHeaders, startDateTime, endDateTime string) (models.EventCollectionResponseable, error) {
+	if err := cs.resolveGroup(ctx); err != nil {
+		return nil, err
+	}
+
+	events, err := cs.client.Groups().ByGroupId(cs.group.id).CalendarView().Get(ctx, &groups.ItemCalendarViewRequestBuilderGetRequestConfiguration{
+		Headers: headers,
+		QueryParameters: &groups.ItemCalendarViewRequestBuilderGetQueryParameters{
+			StartDateTime: &startDateTime,
+			EndDateTime:   &endDateTime,
+			Orderby:       []string{"start/dateTime"},
+			Select:        calendarViewFields,
+			Top:           intPtr(pageSize),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar view of group %s: %w", cs.group.name, err)
+	}
+	return events, nil
+}
+
+// resolveGroup looks up the group's ID and display name, by ID when the
+// settings give one and by exact display name otherwise
+func (cs *CalendarService) resolveGroup(ctx context.Context) error {
+	group := cs.group
+	if group.id != "" {
+		return nil
+	}
+
+	if groupIDPattern.MatchString(group.ref) {
+		found, err := cs.client.Groups().ByGroupId(group.ref).Get(ctx, &groups.GroupItemRequestBuilderGetRequestConfiguration{
+			QueryParameters: &groups.GroupItemRequestBuilderGetQueryParameters{
+				Select: []string{"id", "displayName"},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get group %s: %w", group.ref, err)
+		}
+		group.id = group.ref
+		group.name = getStringValue(found.GetDisplayName())
+		return nil
+	}
+
+	filter := fmt.Sprintf("displayName eq '%s'", strings.ReplaceAll(group.ref, "'", "''"))
+	found, err := cs.client.Groups().Get(ctx, &groups.GroupsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &groups.GroupsRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"id", "displayName"},
+			Top:    intPtr(2),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up group %q: %w", group.ref, err)
+	}
+	switch matches := found.GetValue(); len(matches) {
+	case 0:
+		return fmt.Errorf("no group is named %q", group.ref)
+	case 1:
+		group.id = getStringValue(matches[0].GetId())
+		group.name = getStringValue(matches[0].GetDisplayName())
+		return nil
+	default:
+		return fmt.Errorf("several groups are named %q, use the group's ID instead", group.ref)
+	}
+}
@@ -0,0 +1,135 @@
+package calendar
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// linkEntityRegex matches the HTML entities found in links copied from HTML
+// bodies. The semicolon is required, so query parameters such as "&copy="
+// aren't taken for entities.
+var linkEntityRegex = regexp.MustCompile(`&(amp|lt|gt|quot|apos|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+
+// linkRedirectors are link wrappers whose target is in a query parameter:
+// Microsoft Defender Safe Links, as rewritten in Outlook
+// (nam12.safelinks.protection.outlook.com) and Teams, and Google's redirect
+// page. Joining through them is slower and some break when their parameters
+// get mangled.
+var linkRedirectors = []struct {
+	hostSuffix string
+	// pathSuffix, if set, must end the link's path
+	pathSuffix string
+	param      string
+}{
+	{hostSuffix: ".safelinks.protection.outlook.com", param: "url"},
+	{hostSuffix: "statics.teams.cdn.office.net", pathSuffix: "/atp-safelinks.html", param: "url"},
+	{hostSuffix: "google.com", pathSuffix: "/url", param: "q"},
+}
+
+// safeLinkRegex finds Safe Links in text, e.g.
+// https://nam12.safelinks.protection.outlook.com/?url=https%3A%2F%2Fteams...
+var safeLinkRegex = regexp.MustCompile(`(?i)https?://(?:[a-z0-9-]+\.safelinks\.protection\.outlook\.com|statics\.teams\.cdn\.office\.net)/[^\s<>"']+`)
+
+// maxLinkUnwrap bounds unwrapping of links wrapped several times
+const maxLinkUnwrap = 3
+
+// normalizeLink turns a link taken from an invite into one that can be
+// opened: HTML entities such as &amp; are decoded, stray punctuation around
+// it is dropped and redirect wrappers are replaced by their target. Links
+// that aren't http(s) URLs come back empty.
+func normalizeLink(link string) string {
+	link = strings.TrimSpace(link)
+	// Bodies that were escaped twice turn & into &amp;amp;
+	for i := 0; i < maxLinkUnwrap && linkEntityRegex.MatchString(link); i++ {
+		link = linkEntityRegex.ReplaceAllStringFunc(link, html.UnescapeString)
+	}
+	link = strings.TrimLeft(strings.Trim(link, `<>"' `), "(")
+	link = strings.TrimRight(link, ".,:;!?")
+	if strings.HasSuffix(link, ")") && !strings.Contains(link, "(") {
+		link = strings.TrimRight(link, ")")
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	target, unwrapped := unwrapRedirects(parsed)
+	if (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return ""
+	}
+	if unwrapped {
+		return target.String()
+	}
+	// Keep the link as written rather than as url.URL would re-encode it
+	return link
+}
+
+// unwrapRedirects follows redirect wrappers around link to the link they
+// point to, and reports whether there were any
+func unwrapRedirects(link *url.URL) (*url.URL, bool) {
+	unwrapped := false
+	for i := 0; i < maxLinkUnwrap; i++ {
+		target := redirectTarget(link)
+		if target == nil {
+			break
+		}
+		link = target
+		unwrapped = true
+	}
+	return link, unwrapped
+}
+
+// unwrapSafeLinks replaces the Safe Links in text with the links they
+// wrap, so join links are found in bodies whose links were all rewritten.
+// Links that don't decode to an http(s) URL are left alone.
+func unwrapSafeLinks(text string) string {
+	if !strings.Contains(strings.ToLower(text), "safelinks") {
+		return text
+	}
+	return safeLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		link := strings.TrimRight(match, ".,:;!?)")
+		trailing := match[len(link):]
+		parsed, err := url.Parse(html.UnescapeString(link))
+		if err != nil {
+			return match
+		}
+		target, unwrapped := unwrapRedirects(parsed)
+		if !unwrapped {
+			return match
+		}
+		return target.String() + trailing
+	})
+}
+
+// redirectTarget returns where a redirect wrapper link points, or nil if
+// link isn't one
+func redirectTarget(link *url.URL) *url.URL {
+	host := strings.ToLower(link.Hostname())
+	for _, redirector := range linkRedirectors {
+		if !strings.HasSuffix(host, redirector.hostSuffix) || !strings.HasSuffix(link.Path, redirector.pathSuffix) {
+			continue
+		}
+		target, err := url.Parse(link.Query().Get(redirector.param))
+		if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+			return nil
+		}
+		return target
+	}
+	return nil
+}
+
+// normalizeEventLinks normalizes the event's join and web links, see
+// normalizeLink
+func normalizeEventLinks(e *Event) {
+	if e.TeamsLink != "" {
+		e.TeamsLink = normalizeLink(e.TeamsLink)
+	}
+	if e.ZoomLink != "" {
+		e.ZoomLink = normalizeLink(e.ZoomLink)
+	}
+	if e.WebLink != "" {
+		e.WebLink = normalizeLink(e.WebLink)
+	}
+}
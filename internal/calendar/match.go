@@ -0,0 +1,143 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// matchStopWords are dropped from queries such as "1:1 with Anna" or "the
+// next standup", unless the query has nothing else
+var matchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "with": true, "w": true,
+	"my": true, "next": true, "for": true, "on": true, "meeting": true,
+}
+
+// MatchScore rates how well a free-text query matches the event's subject,
+// organizer and attendees. Every query word must match a word of the event
+// exactly, as a prefix, inside it or with one typo; subject matches count
+// double. 0 means no match.
+func MatchScore(event Event, query string) int {
+	queryWords := matchWords(query)
+	var kept []string
+	for _, word := range queryWords {
+		if !matchStopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) > 0 {
+		queryWords = kept
+	}
+	if len(queryWords) == 0 {
+		return 0
+	}
+
+	subjectWords := matchWords(event.Subject)
+	var peopleWords []string
+	for _, person := range append([]string{event.Organizer}, event.Attendees...) {
+		peopleWords = append(peopleWords, matchWords(person)...)
+	}
+
+	score := 0
+	for _, queryWord := range queryWords {
+		best := max(2*bestWordMatch(queryWord, subjectWords), bestWordMatch(queryWord, peopleWords))
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+	return score
+}
+
+// FindNextMatch returns the earliest event that hasn't ended among those
+// matching query best, skipping declined and cancelled meetings, or nil if
+// nothing matches
+func FindNextMatch(events []Event, query string, now time.Time) *Event {
+	var found *Event
+	bestScore := 0
+	for i := range events {
+		event := &events[i]
+		if !event.End.After(now) || event.IsDeclined() || event.IsCancelled {
+			continue
+		}
+		score := MatchScore(*event, query)
+		if score == 0 {
+			continue
+		}
+		if score > bestScore || (score == bestScore && event.Start.Before(found.Start)) {
+			found = event
+			bestScore = score
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	match := *found
+	return &match
+}
+
+// matchWords splits s into lowercase words, keeping inner punctuation so
+// "1:1" and "o'brien" stay whole
+func matchWords(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("/,;()[]|<>\"", r)
+	})
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// bestWordMatch rates the best match of queryWord among words: 4 for the
+// same word, 3 for a prefix, 2 inside a word, 1 for one typo, 0 for none
+func bestWordMatch(queryWord string, words []string) int {
+	best := 0
+	for _, word := range words {
+		switch {
+		case word == queryWord:
+			return 4
+		case strings.HasPrefix(word, queryWord):
+			best = max(best, 3)
+		case len(queryWord) >= 3 && strings.Contains(word, queryWord):
+			best = max(best, 2)
+		case len([]rune(queryWord)) >= 4 && withinOneEdit(queryWord, word):
+			best = max(best, 1)
+		}
+	}
+	return best
+}
+
+// withinOneEdit reports whether a and b differ by at most one inserted,
+// deleted or replaced character, or two swapped neighbours
+func withinOneEdit(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+	if i == len(ra) {
+		return true
+	}
+	if len(ra) == len(rb) {
+		// Replace the first differing character, or swap it with the next
+		if string(ra[i+1:]) == string(rb[i+1:]) {
+			return true
+		}
+		return i+1 < len(ra) && ra[i] == rb[i+1] && ra[i+1] == rb[i] && string(ra[i+2:]) == string(rb[i+2:])
+	}
+	// Insert the first differing character into the shorter word
+	return string(ra[i:]) == string(rb[i+1:])
+}
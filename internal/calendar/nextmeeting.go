@@ -0,0 +1,40 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+
+	"calendar-widget/internal/selection"
+)
+
+// NextMeetingOptions controls which events GetNextMeeting may return. The
+// zero value only considers blocking meetings the user hasn't declined.
+type NextMeetingOptions = selection.Options
+
+// SelectNextMeeting picks the meeting to act on from events: a running
+// meeting first, then the one starting soonest. Within each status blocking
+// meetings win over the rest. It returns a copy of the event, or nil if no
+// event qualifies.
+func SelectNextMeeting(events []Event, now time.Time, opts NextMeetingOptions) *Event {
+	candidates := make([]selection.Candidate, len(events))
+	for i := range events {
+		candidates[i] = selection.Candidate{
+			Status:   events[i].GetStatus(),
+			Start:    events[i].Start,
+			Blocking: events[i].IsBlockingEvent(),
+			Declined: events[i].IsDeclined(),
+		}
+	}
+
+	i := selection.Best(candidates, now, opts)
+	if i < 0 {
+		return nil
+	}
+	selected := events[i]
+	return &selected
+}
+
+// IsDeclined reports whether the user has declined the meeting
+func (e *Event) IsDeclined() bool {
+	return strings.EqualFold(e.ResponseStatus, "declined")
+}
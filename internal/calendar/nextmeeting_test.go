@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"calendar-widget/internal/selection"
+	"testing"
+	"time"
+)
+
+func TestSelectNextMeeting(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	SetClock(FixedClock(now))
+	defer SetClock(nil)
+
+	in := func(minutes int) time.Time {
+		return now.Add(time.Duration(minutes) * time.Minute)
+	}
+	today := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	allDay := Event{ID: "all-day", Subject: "Conference", Start: today, End: today.AddDate(0, 0, 1), IsAllDay: true, ShowAs: "oof"}
+	free := Event{ID: "free", Subject: "Lunch", Start: in(-10), End: in(20), ShowAs: "free"}
+	tentative := Event{ID: "tentative", Subject: "Review", Start: in(3), End: in(33), ShowAs: "tentative", ResponseStatus: "tentativelyAccepted"}
+	busy := Event{ID: "busy", Subject: "Planning", Start: in(30), End: in(60), ShowAs: "busy"}
+	running := Event{ID: "running", Subject: "Incident", Start: in(-5), End: in(25), ShowAs: "busy"}
+	declined := Event{ID: "declined", Subject: "Town hall", Start: in(-5), End: in(55), ShowAs: "busy", ResponseStatus: "declined"}
+
+	tests := []struct {
+		name   string
+		events []Event
+		opts   NextMeetingOptions
+		want   string
+	}{
+		{name: "tentative counts as a meeting", events: []Event{tentative, busy}, opts: selection.Meetings, want: "tentative"},
+		{name: "blocking wins within a status", events: []Event{free, running}, opts: selection.Display, want: "running"},
+		{name: "running free event wins over an urgent meeting", events: []Event{free, tentative}, opts: selection.Display, want: "free"},
+		{name: "free event as the display fallback", events: []Event{free, busy}, opts: selection.Display, want: "free"},
+		{name: "free event is not a meeting", events: []Event{free}, opts: selection.Meetings, want: ""},
+		{name: "all-day event as the display fallback", events: []Event{allDay}, opts: selection.Display, want: "all-day"},
+		{name: "all-day event is not a meeting", events: []Event{allDay, busy}, opts: selection.Meetings, want: "busy"},
+		{name: "declined is skipped", events: []Event{declined, busy}, opts: selection.Display, want: "busy"},
+		{name: "declined when included", events: []Event{declined, busy}, opts: selection.Options{IncludeDeclined: true}, want: "declined"},
+		{name: "none", events: nil, opts: selection.Display, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectNextMeeting(tt.events, now, tt.opts)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("selected %q, want none", got.ID)
+				}
+				return
+			}
+			if got == nil || got.ID != tt.want {
+				t.Errorf("selected %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectNextMeetingReturnsACopy(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	SetClock(FixedClock(now))
+	defer SetClock(nil)
+
+	events := []Event{
+		{ID: "first", Subject: "Standup", Start: now.Add(10 * time.Minute), End: now.Add(25 * time.Minute)},
+		{ID: "second", Subject: "Review", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+	}
+
+	first := SelectNextMeeting(events, now, selection.Meetings)
+	again := SelectNextMeeting(events, now, selection.Meetings)
+	if first == nil || again == nil {
+		t.Fatal("no meeting selected")
+	}
+	if first == again {
+		t.Error("two selections returned the same pointer")
+	}
+	if first == &events[0] {
+		t.Error("selection points into the events slice")
+	}
+
+	first.Subject = "Changed"
+	if events[0].Subject != "Standup" || again.Subject != "Standup" {
+		t.Errorf("changing the selection changed the events: %q, %q", events[0].Subject, again.Subject)
+	}
+}
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"calendar-widget/internal/auth"
+)
+
+// CalendarProvider is the read side of a calendar backend. The widget, waybar
+// rendering and click handling only depend on this, so they can run against
+// the fake provider as well as Microsoft Graph.
+type CalendarProvider interface {
+	GetTodaysEvents(ctx context.Context) ([]Event, error)
+	GetUpcomingEvents(ctx context.Context) ([]Event, error)
+	GetEventsBetween(ctx context.Context, start, end time.Time) ([]Event, error)
+	GetNextMeeting(ctx context.Context, opts NextMeetingOptions) (*Event, error)
+	FindNextFreeSlot(ctx context.Context, minDuration time.Duration) (*FreeSlot, error)
+}
+
+// DeltaSyncer is implemented by providers that support incremental sync
+type DeltaSyncer interface {
+	SyncDelta(ctx context.Context, state *DeltaState, windowStart, windowEnd time.Time) (*DeltaState, error)
+}
+
+// ReminderDismisser is implemented by providers that can dismiss an
+// event's reminder on the server, so other devices stop reminding too
+type ReminderDismisser interface {
+	DismissReminder(ctx context.Context, eventID string) error
+}
+
+// EventSearcher is implemented by providers that search a date range
+// themselves instead of returning every event in it
+type EventSearcher interface {
+	SearchEvents(ctx context.Context, query string, start, end time.Time) ([]Event, error)
+}
+
+// ChangeSubscriber is implemented by providers that can push change
+// notifications for the user's calendar to a webhook
+type ChangeSubscriber interface {
+	Subscribe(ctx context.Context, notificationURL, clientState string, expires time.Time) (string, error)
+	RenewSubscription(ctx context.Context, id string, expires time.Time) error
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+// FakeDataEnv names the environment variable that points at a JSON fixture.
+// When it is set every provider is the fake one and no account is needed.
+const FakeDataEnv = "CALENDAR_WIDGET_FAKE_DATA"
+
+// NewProvider returns the fake provider when FakeDataEnv is set, the EWS
+// provider when config.json selects it and a Graph calendar service
+// otherwise. maxEvents <= 0 keeps the default cap.
+func NewProvider(allowInteractive bool, forceRefresh bool, maxEvents int) (CalendarProvider, error) {
+	if path := os.Getenv(FakeDataEnv); path != "" {
+		return LoadFakeProvider(path)
+	}
+
+	if config, err := auth.LoadConfig(); err == nil && config.Provider == auth.ProviderEWS {
+		if config.EWS == nil {
+			return nil, fmt.Errorf("provider is %q but config.json has no ews section", auth.ProviderEWS)
+		}
+		provider, err := NewEWSProvider(config.EWS)
+		if err != nil {
+			return nil, err
+		}
+		provider.SetMaxEvents(maxEvents)
+		return provider, nil
+	}
+
+	service, err := NewCalendarServiceWithRefresh(allowInteractive, forceRefresh)
+	if err != nil {
+		return nil, err
+	}
+	service.SetMaxEvents(maxEvents)
+	return service, nil
+}
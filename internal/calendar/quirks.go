@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/auth"
+
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+// windowsTimeZones maps the Windows zone names Graph sometimes reports
+// instead of honouring the Prefer header (personal calendars do this for
+// recurring exceptions) onto IANA names
+var windowsTimeZones = map[string]string{
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Eastern Standard Time":           "America/New_York",
+	"Central Standard Time":           "America/Chicago",
+	"Mountain Standard Time":          "America/Denver",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Atlantic Standard Time":          "America/Halifax",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"India Standard Time":             "Asia/Kolkata",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Canada Central Standard Time":    "America/Regina",
+	"Argentina Standard Time":         "America/Buenos_Aires",
+	"Mexico Standard Time":            "America/Mexico_City",
+	"Central America Standard Time":   "America/Guatemala",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+}
+
+// resolveTimeZone returns the location for a Graph timeZone value, which is
+// an IANA name, a Windows name or "tzone://Microsoft/Utc". Unknown zones
+// fall back to UTC, the zone we ask Graph for.
+func resolveTimeZone(tz string) *time.Location {
+	tz = strings.TrimPrefix(tz, "tzone://Microsoft/")
+	if tz == "" || strings.EqualFold(tz, "UTC") {
+		return time.UTC
+	}
+	if name, ok := windowsTimeZones[tz]; ok {
+		tz = name
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// convert maps a Graph event onto our Event type, applying the
+// compatibility fixes for the signed-in account type
+func (cs *CalendarService) convert(event models.Eventable) Event {
+	e := convertEvent(event)
+	if cs.accountType == auth.AccountTypePersonal {
+		applyPersonalQuirks(&e, event)
+	}
+	normalizeEventLinks(&e)
+	e.Tenant = cs.tenant
+	if cs.group != nil {
+		e.Group = cs.group.name
+	}
+	return e
+}
+
+// applyPersonalQuirks fills in what personal accounts leave out. Their
+// Teams meetings often have an onlineMeeting without a joinUrl, with the
+// link in onlineMeetingUrl or only in the body, and organizers outside the
+// account's contacts come without a display name.
+func applyPersonalQuirks(e *Event, event models.Eventable) {
+	if e.TeamsLink == "" {
+		if link := getStringValue(event.GetOnlineMeetingUrl()); link != "" {
+			e.TeamsLink = link
+			e.IsTeams = true
+		} else {
+			e.TeamsLink, e.IsTeams = extractTeamsLink(e.BodyText, e.Location)
+		}
+	}
+
+	// An empty onlineMeeting alone doesn't make it a Teams meeting
+	if !e.IsTeams && getBoolValue(event.GetIsOnlineMeeting()) && event.GetOnlineMeetingProvider() != nil {
+		e.IsTeams = *event.GetOnlineMeetingProvider() == models.TEAMSFORBUSINESS_ONLINEMEETINGPROVIDERTYPE
+	}
+
+	if e.Organizer == "" && event.GetOrganizer() != nil && event.GetOrganizer().GetEmailAddress() != nil {
+		e.Organizer = getStringValue(event.GetOrganizer().GetEmailAddress().GetAddress())
+	}
+}
+
+// ParseCalendarView converts a recorded calendarView response body the
+// way the service would for accountType. It lets recorded personal and work
+// account responses be checked without signing in.
+func ParseCalendarView(data []byte, accountType string) ([]Event, error) {
+	node, err := jsonserialization.NewJsonParseNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	parsed, err := node.GetObjectValue(models.CreateEventCollectionResponseFromDiscriminatorValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	response, ok := parsed.(models.EventCollectionResponseable)
+	if !ok {
+		return nil, fmt.Errorf("response is not an event collection")
+	}
+
+	cs := &CalendarService{accountType: accountType}
+	var events []Event
+	for _, event := range response.GetValue() {
+		events = append(events, cs.convert(event))
+	}
+	return HideCancelled(Dedupe(events)), nil
+}
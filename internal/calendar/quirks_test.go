@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"calendar-widget/internal/auth"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readRecording reads a recorded calendarView response from testdata
+func readRecording(t *testing.T, name string) (accountType string, response []byte) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "golden", "graph", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recording struct {
+		AccountType string          `json:"account_type"`
+		Response    json.RawMessage `json:"response"`
+	}
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatal(err)
+	}
+	return recording.AccountType, recording.Response
+}
+
+func TestParseCalendarView(t *testing.T) {
+	type want struct {
+		start     string
+		teams     bool
+		organizer string
+		join      string
+	}
+	tests := []struct {
+		recording string
+		want      map[string]want
+	}{
+		{
+			recording: "personal-calendarview.json",
+			want: map[string]want{
+				"Dentist": {start: "2025-06-02T08:30:00Z", organizer: "Jamie Doe"},
+				// An empty onlineMeeting with the Teams provider, and an
+				// organizer with only an address
+				"Family call": {start: "2025-06-02T10:00:00Z", teams: true, organizer: "alex.doe@hotmail.com", join: "https://teams.live.com/meet/9381726354012?p=AbCdEfGhIjKlMn"},
+				// A Windows time zone name
+				"Book club": {start: "2025-06-02T17:30:00Z", organizer: "Sam Lee"},
+				// The join link only in onlineMeetingUrl, in tzone://Microsoft/Utc
+				"Quiz night (video)": {start: "2025-06-02T20:00:00Z", teams: true, organizer: "Quiz Team", join: "https://teams.live.com/meet/9455512000381?p=QrStUvWxYz"},
+				"Alex's birthday":    {start: "2025-06-02T00:00:00Z", organizer: "Jamie Doe"},
+			},
+		},
+		{
+			recording: "work-calendarview.json",
+			want: map[string]want{
+				"Sprint planning": {start: "2025-06-02T09:00:00Z", teams: true, organizer: "Robin Diaz", join: "https://teams.microsoft.com/l/meetup-join/19%3ameeting_ZmE1YjQ2%40thread.v2/0?context=%7b%22Tid%22%3a%224f1c2d3e%22%7d"},
+				"1:1 with Kim":    {start: "2025-06-02T13:00:00Z", organizer: "Robin Diaz"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.recording, func(t *testing.T) {
+			accountType, response := readRecording(t, tt.recording)
+			events, err := ParseCalendarView(response, accountType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("got %d events, want %d", len(events), len(tt.want))
+			}
+			for _, event := range events {
+				w, ok := tt.want[event.Subject]
+				if !ok {
+					t.Errorf("unexpected event %q", event.Subject)
+					continue
+				}
+				if got := event.Start.UTC().Format(time.RFC3339); got != w.start {
+					t.Errorf("%s: start %s, want %s", event.Subject, got, w.start)
+				}
+				if event.IsTeams != w.teams {
+					t.Errorf("%s: teams %v, want %v", event.Subject, event.IsTeams, w.teams)
+				}
+				if event.Organizer != w.organizer {
+					t.Errorf("%s: organizer %q, want %q", event.Subject, event.Organizer, w.organizer)
+				}
+				if got := event.GetJoinLink(); got != w.join {
+					t.Errorf("%s: join %q, want %q", event.Subject, got, w.join)
+				}
+			}
+		})
+	}
+}
+
+func TestPersonalQuirksOnlyForPersonalAccounts(t *testing.T) {
+	_, response := readRecording(t, "personal-calendarview.json")
+	events, err := ParseCalendarView(response, auth.AccountTypeWork)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range events {
+		switch event.Subject {
+		case "Quiz night (video)":
+			if event.GetJoinLink() != "" {
+				t.Errorf("join %q, want onlineMeetingUrl ignored for work accounts", event.GetJoinLink())
+			}
+		case "Family call":
+			if event.Organizer != "" {
+				t.Errorf("organizer %q, want no address fallback for work accounts", event.Organizer)
+			}
+		}
+	}
+}
+
+func TestResolveTimeZone(t *testing.T) {
+	tests := []struct {
+		tz   string
+		want string
+	}{
+		{tz: "UTC", want: "UTC"},
+		{tz: "", want: "UTC"},
+		{tz: "tzone://Microsoft/Utc", want: "UTC"},
+		{tz: "W. Europe Standard Time", want: "Europe/Berlin"},
+		{tz: "Pacific Standard Time", want: "America/Los_Angeles"},
+		{tz: "India Standard Time", want: "Asia/Kolkata"},
+		{tz: "Europe/Oslo", want: "Europe/Oslo"},
+		{tz: "Nowhere Standard Time", want: "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tz, func(t *testing.T) {
+			if got := resolveTimeZone(tt.tz).String(); got != tt.want {
+				t.Errorf("resolveTimeZone(%q) = %s, want %s", tt.tz, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	abstractions "github.com/microsoft/kiota-abstractions-go"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// Event types as reported by Graph
+const (
+	EventTypeSingle       = "singleInstance"
+	EventTypeOccurrence   = "occurrence"
+	EventTypeException    = "exception"
+	EventTypeSeriesMaster = "seriesMaster"
+)
+
+// GetSeriesMaster fetches the series master of a recurring event, with its
+// recurrence pattern converted to an iCalendar RRULE in Recurrence
+func (cs *CalendarService) GetSeriesMaster(ctx context.Context, id string) (*Event, error) {
+	headers := abstractions.NewRequestHeaders()
+	headers.Add("Prefer", fmt.Sprintf("outlook.timezone=%q", preferredTimeZone))
+
+	requestConfiguration := &users.ItemEventsEventItemRequestBuilderGetRequestConfiguration{
+		Headers: headers,
+		QueryParameters: &users.ItemEventsEventItemRequestBuilderGetQueryParameters{
+			Select: []string{"id", "iCalUId", "subject", "start", "end", "location", "webLink", "body", "organizer", "attendees", "responseStatus", "categories", "showAs", "sensitivity", "onlineMeeting", "isAllDay", "type", "recurrence"},
+		},
+	}
+
+	master, err := cs.client.Me().Events().ByEventId(id).Get(ctx, requestConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series master: %w", err)
+	}
+
+	event := cs.convert(master)
+	event.Recurrence = recurrenceRule(master.GetRecurrence())
+	return &event, nil
+}
+
+// CollapseRecurring replaces the occurrences of recurring series with their
+// series master (carrying an RRULE) so each series is listed once. Modified
+// occurrences are kept as exceptions and take the master's ICalUID so they
+// override the right instance. Series whose master can't be fetched keep
+// their individual occurrences.
+func (cs *CalendarService) CollapseRecurring(ctx context.Context, events []Event) []Event {
+	masters := make(map[string]*Event)
+	var result []Event
+
+	for _, event := range events {
+		if event.SeriesMasterID == "" || (event.Type != EventTypeOccurrence && event.Type != EventTypeException) {
+			result = append(result, event)
+			continue
+		}
+
+		master, seen := masters[event.SeriesMasterID]
+		if !seen {
+			fetched, err := cs.GetSeriesMaster(ctx, event.SeriesMasterID)
+			if err == nil && fetched.Recurrence != "" {
+				master = fetched
+				result = append(result, *master)
+			}
+			masters[event.SeriesMasterID] = master
+		}
+
+		switch {
+		case master == nil:
+			result = append(result, event)
+		case event.Type == EventTypeException:
+			event.ICalUID = master.ICalUID
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
+// rruleDays maps Graph weekdays to iCalendar BYDAY codes
+var rruleDays = map[string]string{
+	"sunday":    "SU",
+	"monday":    "MO",
+	"tuesday":   "TU",
+	"wednesday": "WE",
+	"thursday":  "TH",
+	"friday":    "FR",
+	"saturday":  "SA",
+}
+
+// rruleIndex maps Graph week indexes to BYSETPOS values
+var rruleIndex = map[string]string{
+	"first":  "1",
+	"second": "2",
+	"third":  "3",
+	"fourth": "4",
+	"last":   "-1",
+}
+
+// recurrenceRule converts a Graph recurrence to an RRULE value such as
+// "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE". It returns "" for patterns it
+// can't express.
+func recurrenceRule(recurrence models.PatternedRecurrenceable) string {
+	if recurrence == nil || recurrence.GetPattern() == nil || recurrence.GetPattern().GetTypeEscaped() == nil {
+		return ""
+	}
+	pattern := recurrence.GetPattern()
+
+	var days []string
+	for _, day := range pattern.GetDaysOfWeek() {
+		days = append(days, rruleDays[day.String()])
+	}
+
+	var parts []string
+	switch pattern.GetTypeEscaped().String() {
+	case "daily":
+		parts = append(parts, "FREQ=DAILY")
+	case "weekly":
+		parts = append(parts, "FREQ=WEEKLY")
+		if len(days) > 0 {
+			parts = append(parts, "BYDAY="+strings.Join(days, ","))
+		}
+	case "absoluteMonthly":
+		parts = append(parts, "FREQ=MONTHLY", fmt.Sprintf("BYMONTHDAY=%d", getInt32Value(pattern.GetDayOfMonth())))
+	case "relativeMonthly":
+		parts = append(parts, "FREQ=MONTHLY", "BYDAY="+strings.Join(days, ","))
+		if pattern.GetIndex() != nil {
+			parts = append(parts, "BYSETPOS="+rruleIndex[pattern.GetIndex().String()])
+		}
+	case "absoluteYearly":
+		parts = append(parts, "FREQ=YEARLY",
+			fmt.Sprintf("BYMONTH=%d", getInt32Value(pattern.GetMonth())),
+			fmt.Sprintf("BYMONTHDAY=%d", getInt32Value(pattern.GetDayOfMonth())))
+	case "relativeYearly":
+		parts = append(parts, "FREQ=YEARLY",
+			fmt.Sprintf("BYMONTH=%d", getInt32Value(pattern.GetMonth())),
+			"BYDAY="+strings.Join(days, ","))
+		if pattern.GetIndex() != nil {
+			parts = append(parts, "BYSETPOS="+rruleIndex[pattern.GetIndex().String()])
+		}
+	default:
+		return ""
+	}
+
+	if interval := getInt32Value(pattern.GetInterval()); interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", interval))
+	}
+
+	if r := recurrence.GetRangeEscaped(); r != nil && r.GetTypeEscaped() != nil {
+		switch r.GetTypeEscaped().String() {
+		case "endDate":
+			if r.GetEndDate() != nil {
+				if until, ok := rruleUntil(r.GetEndDate().String(), getStringValue(r.GetRecurrenceTimeZone())); ok {
+					parts = append(parts, "UNTIL="+until)
+				}
+			}
+		case "numbered":
+			parts = append(parts, fmt.Sprintf("COUNT=%d", getInt32Value(r.GetNumberOfOccurrences())))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// rruleUntil converts a range's end date, a day in the recurrence's time
+// zone, to an UNTIL value. UNTIL must be UTC when DTSTART has a time, so it
+// is the last second of that day in the zone, converted to UTC.
+func rruleUntil(endDate, timeZone string) (string, bool) {
+	day, err := time.ParseInLocation("2006-01-02", endDate, resolveTimeZone(timeZone))
+	if err != nil {
+		return "", false
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Second).UTC().Format("20060102T150405Z"), true
+}
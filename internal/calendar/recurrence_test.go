@@ -0,0 +1,31 @@
+package calendar
+
+import "testing"
+
+func TestRRuleUntil(t *testing.T) {
+	tests := []struct {
+		name     string
+		endDate  string
+		timeZone string
+		want     string
+	}{
+		{name: "UTC", endDate: "2025-06-30", timeZone: "UTC", want: "20250630T235959Z"},
+		{name: "west of UTC runs into the next UTC day", endDate: "2025-06-30", timeZone: "Pacific Standard Time", want: "20250701T065959Z"},
+		{name: "east of UTC ends before UTC midnight", endDate: "2025-06-30", timeZone: "W. Europe Standard Time", want: "20250630T215959Z"},
+		{name: "IANA name", endDate: "2025-01-31", timeZone: "Europe/Oslo", want: "20250131T225959Z"},
+		{name: "no zone falls back to UTC", endDate: "2025-06-30", timeZone: "", want: "20250630T235959Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rruleUntil(tt.endDate, tt.timeZone)
+			if !ok || got != tt.want {
+				t.Errorf("rruleUntil(%q, %q) = %q, %v, want %q", tt.endDate, tt.timeZone, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := rruleUntil("not a date", "UTC"); ok {
+		t.Error("rruleUntil accepted an invalid end date")
+	}
+}
@@ -0,0 +1,35 @@
+package calendar
+
+import "time"
+
+// DefaultUrgentLead is how long before the start a meeting turns urgent when
+// its own reminder isn't used
+const DefaultUrgentLead = 5 * time.Minute
+
+var calendarReminders bool
+
+// SetCalendarReminders makes each event's own reminder decide when it turns
+// urgent and when it is notified about, instead of the fixed defaults.
+// Events without a reminder keep the defaults.
+func SetCalendarReminders(enabled bool) {
+	calendarReminders = enabled
+}
+
+// Reminder returns how long before the start the event's reminder fires,
+// and false when calendar reminders are off or the event has none
+func (e *Event) Reminder() (time.Duration, bool) {
+	if !calendarReminders || !e.ReminderOn || e.ReminderMinutes < 0 {
+		return 0, false
+	}
+	return time.Duration(e.ReminderMinutes) * time.Minute, true
+}
+
+// UrgentLead is how long before the start (or leave-by time) the event
+// turns urgent: its reminder when calendar reminders are on, otherwise five
+// minutes
+func (e *Event) UrgentLead() time.Duration {
+	if reminder, ok := e.Reminder(); ok {
+		return reminder
+	}
+	return DefaultUrgentLead
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+
+	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/places"
+)
+
+// Room is a bookable meeting room from the organization's directory
+type Room struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Building string `json:"building,omitempty"`
+	Floor    string `json:"floor,omitempty"`
+	Capacity int    `json:"capacity,omitempty"`
+}
+
+// RoomFinder is implemented by providers that can list meeting rooms
+type RoomFinder interface {
+	FindRooms(ctx context.Context) ([]Room, error)
+}
+
+// FindRooms lists the organization's meeting rooms through the places API.
+// This needs the Place.Read.All permission, which work accounts get from
+// their admin; personal accounts have no rooms.
+func (cs *CalendarService) FindRooms(ctx context.Context) ([]Room, error) {
+	requestConfiguration := &places.GraphRoomRequestBuilderGetRequestConfiguration{
+		QueryParameters: &places.GraphRoomRequestBuilderGetQueryParameters{
+			Top: intPtr(pageSize),
+		},
+	}
+
+	response, err := cs.client.Places().GraphRoom().Get(ctx, requestConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rooms: %w", err)
+	}
+
+	pageIterator, err := msgraphcore.NewPageIterator[models.Roomable](response, cs.client.GetAdapter(), models.CreateRoomCollectionResponseFromDiscriminatorValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page iterator: %w", err)
+	}
+
+	var rooms []Room
+	err = pageIterator.Iterate(ctx, func(room models.Roomable) bool {
+		rooms = append(rooms, convertRoom(room))
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to page rooms: %w", err)
+	}
+	return rooms, nil
+}
+
+func convertRoom(room models.Roomable) Room {
+	r := Room{
+		Name:     getStringValue(room.GetDisplayName()),
+		Email:    getStringValue(room.GetEmailAddress()),
+		Building: getStringValue(room.GetBuilding()),
+		Floor:    getStringValue(room.GetFloorLabel()),
+	}
+	if r.Floor == "" && room.GetFloorNumber() != nil {
+		r.Floor = fmt.Sprint(*room.GetFloorNumber())
+	}
+	if room.GetCapacity() != nil {
+		r.Capacity = int(*room.GetCapacity())
+	}
+	return r
+}
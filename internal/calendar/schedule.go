@@ -0,0 +1,168 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// schedulesPerRequest is how many mailboxes one getSchedule call asks for
+const schedulesPerRequest = 20
+
+// BusyPeriod is a stretch of time a person or room is not free
+type BusyPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Status is busy, tentative, oof or workingElsewhere
+	Status string `json:"status"`
+}
+
+// Schedule is the free/busy information of one mailbox
+type Schedule struct {
+	Email string
+	Busy  []BusyPeriod
+	// Error is set when the mailbox's schedule couldn't be read
+	Error string
+}
+
+// ScheduleReader is implemented by providers that can read the free/busy
+// of other people and of rooms
+type ScheduleReader interface {
+	GetSchedules(ctx context.Context, emails []string, start, end time.Time) ([]Schedule, error)
+}
+
+// FreeBetween reports whether nothing on the schedule overlaps [start, end)
+func (s Schedule) FreeBetween(start, end time.Time) bool {
+	for _, busy := range s.Busy {
+		if busy.Start.Before(end) && busy.End.After(start) {
+			return false
+		}
+	}
+	return true
+}
+
+// BusyUntil returns when the busy stretch around t ends, following
+// back-to-back bookings, or t itself when the schedule is free at t
+func (s Schedule) BusyUntil(t time.Time) time.Time {
+	busy := make([]BusyPeriod, len(s.Busy))
+	copy(busy, s.Busy)
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	until := t
+	for _, period := range busy {
+		if !period.Start.After(until) && period.End.After(until) {
+			until = period.End
+		}
+	}
+	return until
+}
+
+// NextBusy returns when the schedule is next busy at or after t, or the
+// zero time when nothing is booked after t
+func (s Schedule) NextBusy(t time.Time) time.Time {
+	var next time.Time
+	for _, period := range s.Busy {
+		if !period.End.After(t) {
+			continue
+		}
+		start := period.Start
+		if start.Before(t) {
+			start = t
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// GetSchedules reads the free/busy of the given mailboxes between start and
+// end through getSchedule. Free time isn't listed, only busy periods.
+func (cs *CalendarService) GetSchedules(ctx context.Context, emails []string, start, end time.Time) ([]Schedule, error) {
+	var schedules []Schedule
+	for len(emails) > 0 {
+		batch := emails[:min(len(emails), schedulesPerRequest)]
+		emails = emails[len(batch):]
+
+		body := users.NewItemCalendarGetSchedulePostRequestBody()
+		body.SetSchedules(batch)
+		body.SetStartTime(toDateTimeTimeZone(start))
+		body.SetEndTime(toDateTimeTimeZone(end))
+
+		response, err := cs.client.Me().Calendar().GetSchedule().PostAsGetSchedulePostResponse(ctx, body, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schedules: %w", err)
+		}
+
+		for _, info := range response.GetValue() {
+			schedule := Schedule{Email: getStringValue(info.GetScheduleId())}
+			if info.GetError() != nil {
+				schedule.Error = getStringValue(info.GetError().GetMessage())
+			}
+			for _, item := range info.GetScheduleItems() {
+				status := "busy"
+				if item.GetStatus() != nil {
+					status = item.GetStatus().String()
+				}
+				if status == models.FREE_FREEBUSYSTATUS.String() {
+					continue
+				}
+				schedule.Busy = append(schedule.Busy, BusyPeriod{
+					Start:  parseDateTimeTimeZone(item.GetStart(), false),
+					End:    parseDateTimeTimeZone(item.GetEnd(), false),
+					Status: status,
+				})
+			}
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// BusyPeriods turns the blocking events among events into busy periods, so
+// my own calendar can be combined with other people's schedules
+func BusyPeriods(events []Event) []BusyPeriod {
+	var busy []BusyPeriod
+	for _, event := range events {
+		if !event.IsBlockingEvent() {
+			continue
+		}
+		busy = append(busy, BusyPeriod{Start: event.Start, End: event.End, Status: event.ShowAs})
+	}
+	return busy
+}
+
+// FreeSlots returns every gap of at least minDuration between from and until
+// that none of the busy periods overlap
+func FreeSlots(busy []BusyPeriod, from, until time.Time, minDuration time.Duration) []FreeSlot {
+	sorted := make([]BusyPeriod, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var slots []FreeSlot
+	cursor := from
+	for _, period := range sorted {
+		if !period.End.After(cursor) {
+			continue
+		}
+		if !period.Start.Before(until) {
+			break
+		}
+		if period.Start.Sub(cursor) >= minDuration {
+			slots = append(slots, FreeSlot{Start: cursor, End: period.Start})
+		}
+		cursor = period.End
+	}
+	if until.Sub(cursor) >= minDuration {
+		slots = append(slots, FreeSlot{Start: cursor, End: until})
+	}
+	return slots
+}
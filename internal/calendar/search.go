@@ -0,0 +1,64 @@
+package calendar
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// maxSearchResults caps how many matches a search returns
+const maxSearchResults = 200
+
+// MatchesSearch reports whether every word of query appears in the event's
+// subject, body, organizer, attendees or location, ignoring case
+func (e *Event) MatchesSearch(query string) bool {
+	words := matchWords(query)
+	if len(words) == 0 {
+		return false
+	}
+
+	haystack := strings.ToLower(strings.Join(append([]string{e.Subject, e.BodyText, e.Organizer, e.Location}, e.Attendees...), "\n"))
+	for _, word := range words {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchingEvents returns the events matching query, for providers that
+// can't search themselves
+func MatchingEvents(events []Event, query string) []Event {
+	var matches []Event
+	for _, event := range events {
+		if event.MatchesSearch(query) {
+			matches = append(matches, event)
+		}
+	}
+	return matches
+}
+
+// SearchEvents returns the events between start and end matching query.
+// Graph's $search doesn't cover calendar events and the calendar view
+// can't $filter on the body or attendees, so the view is paged through and
+// matched here; only matches are kept, so long ranges aren't cut short by
+// the event cap.
+func (cs *CalendarService) SearchEvents(ctx context.Context, query string, start, end time.Time) ([]Event, error) {
+	startStr := start.UTC().Format("2006-01-02T15:04:05.000Z")
+	endStr := end.UTC().Format("2006-01-02T15:04:05.000Z")
+
+	var result []Event
+	err := cs.iterateCalendarView(ctx, startStr, endStr, func(event Event) bool {
+		if event.MatchesSearch(query) {
+			result = append(result, event)
+		}
+		return len(result) < maxSearchResults
+	})
+	if err != nil {
+		return nil, err
+	}
+	cs.applyCategoryColors(ctx, result)
+	cs.sortMerged(result)
+
+	return HideCancelled(Dedupe(result)), nil
+}
@@ -0,0 +1,60 @@
+package calendar
+
+import "time"
+
+// Meeting statuses, in the order a meeting goes through them. They double
+// as the waybar classes of the bar.
+const (
+	// StatusUpcoming is more than 15 minutes before the start
+	StatusUpcoming = "upcoming"
+	// StatusSoon is within 15 minutes of the start
+	StatusSoon = "soon"
+	// StatusUrgent is within the reminder lead of the start, or of leaving
+	// for in-person meetings with leave-by on
+	StatusUrgent = "urgent"
+	// StatusCurrent is while the meeting runs
+	StatusCurrent = "current"
+	// StatusPast is after the meeting ended
+	StatusPast = "past"
+)
+
+// SoonWithin is how long before the start a meeting is soon
+const SoonWithin = 15 * time.Minute
+
+// GetStatus returns where the meeting is in its lifecycle at Now()
+func (e *Event) GetStatus() string {
+	now := Now()
+	if now.After(e.End) {
+		return StatusPast
+	}
+	if now.After(e.Start) && now.Before(e.End) {
+		return StatusCurrent
+	}
+
+	// For in-person meetings with leave-by on, urgency counts down to
+	// leaving rather than to the start
+	timeUntil := e.LeaveBy().Sub(now)
+	if timeUntil <= e.UrgentLead() {
+		return StatusUrgent
+	}
+	if timeUntil <= SoonWithin {
+		return StatusSoon
+	}
+	return StatusUpcoming
+}
+
+// IsImminent reports whether the meeting is running or about to start, which
+// is when clicking the bar joins it
+func (e *Event) IsImminent() bool {
+	status := e.GetStatus()
+	return status == StatusCurrent || status == StatusUrgent
+}
+
+// IsPending reports whether status is one of a meeting that hasn't ended
+func IsPending(status string) bool {
+	switch status {
+	case StatusUpcoming, StatusSoon, StatusUrgent, StatusCurrent:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+// subscriptionResource is what change subscriptions watch: every event on
+// the user's calendars, series and exceptions included
+const subscriptionResource = "me/events"
+
+// Subscribe asks Graph to POST a change notification to notificationURL
+// whenever an event on the user's calendar is created, updated or deleted,
+// until expires. Graph checks that the URL answers its validation request
+// before the subscription is created. It returns the subscription ID.
+func (cs *CalendarService) Subscribe(ctx context.Context, notificationURL, clientState string, expires time.Time) (string, error) {
+	changeType := "created,updated,deleted"
+	resource := subscriptionResource
+
+	body := models.NewSubscription()
+	body.SetChangeType(&changeType)
+	body.SetNotificationUrl(&notificationURL)
+	body.SetResource(&resource)
+	body.SetExpirationDateTime(&expires)
+	body.SetClientState(&clientState)
+
+	created, err := cs.client.Subscriptions().Post(ctx, body, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create change subscription: %w", err)
+	}
+	return getStringValue(created.GetId()), nil
+}
+
+// RenewSubscription moves a subscription's expiry to expires
+func (cs *CalendarService) RenewSubscription(ctx context.Context, id string, expires time.Time) error {
+	body := models.NewSubscription()
+	body.SetExpirationDateTime(&expires)
+
+	if _, err := cs.client.Subscriptions().BySubscriptionId(id).Patch(ctx, body, nil); err != nil {
+		return fmt.Errorf("failed to renew change subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription stops a subscription's notifications
+func (cs *CalendarService) DeleteSubscription(ctx context.Context, id string) error {
+	if err := cs.client.Subscriptions().BySubscriptionId(id).Delete(ctx, nil); err != nil {
+		return fmt.Errorf("failed to delete change subscription: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// DaySummary describes a day's blocking meetings for the morning digest
+type DaySummary struct {
+	// Meetings are the blocking meetings the user hasn't declined, by start
+	Meetings []Event
+	// First and Last are the start of the first and end of the last meeting
+	First time.Time
+	Last  time.Time
+	// Busy is the time spent in meetings, counting overlaps once
+	Busy time.Duration
+	// LargestFree is the longest gap between from and until, nil when the
+	// whole window is booked
+	LargestFree *FreeSlot
+}
+
+// Summarize summarizes the blocking meetings in events, looking for free
+// time between from and until (e.g. the working day)
+func Summarize(events []Event, from, until time.Time) DaySummary {
+	var summary DaySummary
+	for _, event := range events {
+		if event.IsBlockingEvent() && !event.IsDeclined() {
+			summary.Meetings = append(summary.Meetings, event)
+		}
+	}
+	sort.SliceStable(summary.Meetings, func(i, j int) bool {
+		return summary.Meetings[i].Start.Before(summary.Meetings[j].Start)
+	})
+
+	var busyUntil time.Time
+	for _, meeting := range summary.Meetings {
+		if summary.First.IsZero() {
+			summary.First = meeting.Start
+		}
+		if meeting.End.After(summary.Last) {
+			summary.Last = meeting.End
+		}
+
+		start := meeting.Start
+		if start.Before(busyUntil) {
+			start = busyUntil
+		}
+		if meeting.End.After(start) {
+			summary.Busy += meeting.End.Sub(start)
+			busyUntil = meeting.End
+		}
+	}
+
+	// Walk the gaps in the window, each found by asking for any free slot
+	// after the previous one
+	cursor := from
+	for cursor.Before(until) {
+		slot := NextFreeSlot(summary.Meetings, cursor, until, time.Minute)
+		if slot == nil {
+			break
+		}
+		if summary.LargestFree == nil || slot.GetDuration() > summary.LargestFree.GetDuration() {
+			summary.LargestFree = slot
+		}
+		cursor = slot.End
+	}
+
+	return summary
+}
@@ -0,0 +1,45 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+
+	"calendar-widget/internal/auth"
+)
+
+// addGuestTenants creates a service per guest tenant, signed in as the same
+// account, whose calendar views are merged into this one's
+func (cs *CalendarService) addGuestTenants(tenants []auth.GuestTenant, allowInteractive, forceRefresh bool) error {
+	for _, tenant := range tenants {
+		if tenant.TenantID == "" {
+			return fmt.Errorf("guest tenant %q has no tenant_id", tenant.Name)
+		}
+
+		client, err := newGraphClient(&nonInteractiveCredential{
+			allowInteractive: allowInteractive,
+			forceRefresh:     forceRefresh,
+			tenantID:         tenant.TenantID,
+		})
+		if err != nil {
+			return err
+		}
+		cs.merged = append(cs.merged, &CalendarService{
+			client:      client,
+			maxEvents:   cs.maxEvents,
+			accountType: auth.AccountTypeWork,
+			tenant:      tenant.Label(),
+		})
+	}
+	return nil
+}
+
+// sortMerged orders events by start once merged calendars' events have
+// been appended after the user's own
+func (cs *CalendarService) sortMerged(events []Event) {
+	if len(cs.merged) == 0 {
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+}
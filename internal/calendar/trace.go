@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redactedParams are query parameters that carry opaque state or secrets
+var redactedParams = []string{"$deltatoken", "$skiptoken", "deltatoken", "skiptoken", "code", "access_token"}
+
+var traceOutput io.Writer
+
+// SetTrace makes every Graph request made by services created afterwards log
+// its URL, status, latency and item count to w. Pass nil to disable.
+func SetTrace(w io.Writer) {
+	traceOutput = w
+}
+
+// traceTransport logs each request attempt. Authorization headers are never
+// printed and token-like query parameters are redacted.
+type traceTransport struct {
+	base http.RoundTripper
+	out  io.Writer
+}
+
+func (tt *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	resp, err := tt.base.RoundTrip(req)
+	latency := time.Since(started).Round(time.Millisecond)
+
+	target := redactURL(req.URL)
+	if err != nil {
+		fmt.Fprintf(tt.out, "→ %s %s: error after %s: %v\n", req.Method, target, latency, err)
+		return resp, err
+	}
+
+	line := fmt.Sprintf("→ %s %s: %d in %s", req.Method, target, resp.StatusCode, latency)
+	if count, ok := countItems(resp); ok {
+		line += fmt.Sprintf(", %d items", count)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		line += ", Retry-After " + retryAfter
+	}
+	if requestID := resp.Header.Get("request-id"); requestID != "" {
+		line += ", request-id " + requestID
+	}
+	fmt.Fprintln(tt.out, line)
+
+	return resp, nil
+}
+
+// countItems reports the length of a Graph collection's "value" array. The
+// body is buffered and restored so the SDK can still read it.
+func countItems(resp *http.Response) (int, bool) {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return 0, false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+
+	var collection struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(body, &collection); err != nil || collection.Value == nil {
+		return 0, false
+	}
+	return len(collection.Value), true
+}
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for _, param := range redactedParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
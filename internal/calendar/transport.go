@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"calendar-widget/internal/network"
+
+	khttp "github.com/microsoft/kiota-http-go"
+	msgraphsdk "github.com/microsoftgraph/msgraph-sdk-go"
+	msgraphcore "github.com/microsoftgraph/msgraph-sdk-go-core"
+)
+
+const (
+	// maxRetries is how many times a throttled or failed request is retried
+	maxRetries = 4
+	// retryBaseDelay is the first backoff step; later steps double it
+	retryBaseDelay = 500 * time.Millisecond
+	// retryMaxDelay caps a single wait. A longer Retry-After is not waited
+	// out, so the caller can report that Graph is rate limiting us.
+	retryMaxDelay = 30 * time.Second
+)
+
+// newHTTPClient builds the Graph HTTP client with our retry layer in place of
+// the Kiota retry handler, on top of the shared proxy/CA aware transport
+func newHTTPClient() *http.Client {
+	options := msgraphsdk.GetDefaultClientOptions()
+
+	var middlewares []khttp.Middleware
+	for _, middleware := range msgraphcore.GetDefaultMiddlewaresWithOptions(&options) {
+		if _, isRetry := middleware.(*khttp.RetryHandler); isRetry {
+			continue
+		}
+		middlewares = append(middlewares, middleware)
+	}
+
+	client := msgraphcore.GetDefaultClient(&options, middlewares...)
+	var base http.RoundTripper = khttp.NewCustomTransportWithParentTransport(network.Transport(), middlewares...)
+	if traceOutput != nil {
+		base = &traceTransport{base: base, out: traceOutput}
+	}
+	client.Transport = &retryTransport{base: base}
+	return client
+}
+
+// retryTransport retries throttled (429/503) and transient failures with
+// jittered exponential backoff, honoring Retry-After when Graph sends it
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := rt.base.RoundTrip(attemptReq)
+		if attempt >= maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		if delay > retryMaxDelay {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	// Requests with a body can only be replayed if it can be re-read
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		// Network errors are only safe to retry for reads
+		return req.Method == http.MethodGet
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return req.Method == http.MethodGet
+	}
+	return false
+}
+
+// retryDelay prefers the server's Retry-After and otherwise backs off
+// exponentially with jitter
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			if seconds, err := strconv.Atoi(retryAfter); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+			if date, err := http.ParseTime(retryAfter); err == nil {
+				return time.Until(date)
+			}
+		}
+	}
+
+	backoff := retryBaseDelay << attempt
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// statusCoder is implemented by Kiota API errors
+type statusCoder interface {
+	GetStatusCode() int
+}
+
+// IsRateLimited reports whether err is Graph refusing requests because of throttling
+func IsRateLimited(err error) bool {
+	var apiErr statusCoder
+	if errors.As(err, &apiErr) {
+		return apiErr.GetStatusCode() == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// ErrorSummary names err in a word or two for status lines: the HTTP status
+// code for API errors, "timeout" or "offline" for network trouble, and the
+// error text otherwise
+func ErrorSummary(err error) string {
+	var apiErr statusCoder
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.GetStatusCode())
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "offline"
+	}
+	return err.Error()
+}
@@ -0,0 +1,130 @@
+package calendar
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// travelCommandHorizon limits the travel command to meetings starting soon;
+// later meetings use the fixed buffer
+const travelCommandHorizon = 3 * time.Hour
+
+// travelEstimateTTL is how long a travel command result is reused, so a
+// long-running daemon follows changing traffic
+const travelEstimateTTL = 15 * time.Minute
+
+var (
+	travelBuffer  time.Duration
+	travelCommand string
+
+	// travelEstimates caches command results by location. Status is
+	// computed from several of the daemon's goroutines at once.
+	travelMu        sync.Mutex
+	travelEstimates = make(map[string]*travelEstimate)
+)
+
+// travelEstimate is a travel command result, or one still being computed
+type travelEstimate struct {
+	duration time.Duration
+	// at is when the command finished; zero while it runs
+	at time.Time
+	// done is closed once duration is known
+	done chan struct{}
+}
+
+// SetTravel enables leave-by for in-person meetings. buffer is a fixed
+// travel time; command, when set, is run with the location as its last
+// argument and prints the travel time ("25m" or minutes). Both empty
+// disables leave-by.
+func SetTravel(buffer time.Duration, command string) {
+	travelBuffer = buffer
+	travelCommand = strings.TrimSpace(command)
+	travelMu.Lock()
+	travelEstimates = make(map[string]*travelEstimate)
+	travelMu.Unlock()
+}
+
+// IsInPerson reports whether the event has a physical location to travel to
+func (e *Event) IsInPerson() bool {
+	location := strings.ToLower(strings.TrimSpace(e.Location))
+	if location == "" || e.IsAllDay || e.IsTeams || e.ZoomLink != "" {
+		return false
+	}
+	return !strings.Contains(location, "://") && !strings.Contains(location, "teams meeting")
+}
+
+// TravelTime returns how long it takes to get to the event, or 0 for online
+// meetings and when leave-by is off
+func (e *Event) TravelTime() time.Duration {
+	if (travelBuffer <= 0 && travelCommand == "") || !e.IsInPerson() {
+		return 0
+	}
+
+	if travelCommand != "" {
+		if until := e.Start.Sub(Now()); until > 0 && until <= travelCommandHorizon {
+			if estimate, ok := estimateTravel(e.Location); ok {
+				return estimate
+			}
+		}
+	}
+
+	return travelBuffer
+}
+
+// LeaveBy returns when you need to leave for the event; for online meetings
+// it is the start time
+func (e *Event) LeaveBy() time.Time {
+	return e.Start.Add(-e.TravelTime())
+}
+
+// estimateTravel runs the travel command for a location, falling back to the
+// buffer (ok is false) when it fails or prints something unparsable. Callers
+// asking for a location whose command is running wait for its result
+// instead of running it again.
+func estimateTravel(location string) (time.Duration, bool) {
+	travelMu.Lock()
+	entry, ok := travelEstimates[location]
+	if ok && (entry.at.IsZero() || time.Since(entry.at) < travelEstimateTTL) {
+		travelMu.Unlock()
+		<-entry.done
+		return entry.duration, entry.duration > 0
+	}
+	entry = &travelEstimate{done: make(chan struct{})}
+	travelEstimates[location] = entry
+	command := travelCommand
+	travelMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fields := strings.Fields(command)
+	out, err := exec.CommandContext(ctx, fields[0], append(fields[1:], location)...).Output()
+
+	var estimate time.Duration
+	if err == nil {
+		estimate = parseTravelTime(strings.TrimSpace(string(out)))
+	}
+
+	travelMu.Lock()
+	entry.duration = estimate
+	entry.at = time.Now()
+	travelMu.Unlock()
+	close(entry.done)
+
+	return estimate, estimate > 0
+}
+
+// parseTravelTime accepts a Go duration ("25m", "1h10m") or whole minutes
+func parseTravelTime(value string) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTravelTimeConcurrent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the travel command is a shell script")
+	}
+
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "travel.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho run >> "+runs+"\nsleep 0.2\necho 25m\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	SetClock(FixedClock(now))
+	defer SetClock(nil)
+	SetTravel(10*time.Minute, script)
+	defer SetTravel(0, "")
+
+	event := Event{Subject: "Offsite", Location: "Main Street 4", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+
+	var wg sync.WaitGroup
+	results := make([]time.Duration, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = event.TravelTime()
+		}()
+	}
+	wg.Wait()
+
+	for i, got := range results {
+		if got != 25*time.Minute {
+			t.Errorf("caller %d: travel time %v, want 25m", i, got)
+		}
+	}
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "run"); n != 1 {
+		t.Errorf("travel command ran %d times, want once", n)
+	}
+}
+
+func TestEstimateTravelExpires(t *testing.T) {
+	SetTravel(0, "false")
+	defer SetTravel(0, "")
+
+	travelMu.Lock()
+	done := make(chan struct{})
+	close(done)
+	travelEstimates["Main Street 4"] = &travelEstimate{duration: 25 * time.Minute, at: time.Now().Add(-travelEstimateTTL - time.Second), done: done}
+	travelMu.Unlock()
+
+	if estimate, ok := estimateTravel("Main Street 4"); ok {
+		t.Errorf("got the expired estimate %v, want the command to run again", estimate)
+	}
+}
@@ -0,0 +1,48 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Copy puts text on the clipboard. Under Wayland it uses wl-copy; otherwise
+// (or when wl-copy is missing) it sends an OSC 52 escape sequence to the
+// terminal, which most modern terminals and tmux forward to the system
+// clipboard, including over SSH.
+func Copy(text string) error {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if path, err := exec.LookPath("wl-copy"); err == nil {
+			cmd := exec.Command(path)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err == nil {
+				return nil
+			}
+		}
+	}
+
+	return copyOSC52(text)
+}
+
+// copyOSC52 writes the OSC 52 "set clipboard" sequence to the controlling
+// terminal
+func copyOSC52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no clipboard available: wl-copy is not usable and there is no terminal for OSC 52")
+	}
+	defer tty.Close()
+
+	sequence := fmt.Sprintf("\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	// tmux only passes the sequence through when wrapped in its DCS escape
+	if os.Getenv("TMUX") != "" {
+		sequence = "\x1bPtmux;\x1b" + sequence + "\x1b\\"
+	}
+
+	if _, err := tty.WriteString(sequence); err != nil {
+		return fmt.Errorf("failed to write to terminal: %w", err)
+	}
+	return nil
+}
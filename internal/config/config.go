@@ -0,0 +1,492 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"calendar-widget/internal/xdg"
+)
+
+// Config holds the user's display preferences. It lives next to the auth
+// config so that `setup` can rewrite credentials without touching it.
+type Config struct {
+	// Locale selects output language and date conventions, e.g. "da-DK".
+	// Empty uses LC_ALL/LC_TIME/LANG.
+	Locale string `json:"locale,omitempty"`
+	// TimeFormat is "12h" or "24h". Empty uses the locale's clock.
+	TimeFormat string          `json:"time_format,omitempty"`
+	Countdown  CountdownConfig `json:"countdown"`
+	Autojoin   AutojoinConfig  `json:"autojoin"`
+	Click      ClickConfig     `json:"click"`
+	Tooltip    TooltipConfig   `json:"tooltip"`
+	// NextMeetings configures the multi display mode
+	NextMeetings NextMeetingsConfig `json:"next_meetings"`
+	// Layout picks the layout preset of the TUI and the bar
+	Layout LayoutConfig `json:"layout"`
+	// Truncate controls how long subjects are cut short in the bar
+	Truncate TruncateConfig `json:"truncate"`
+	// Terminal is used to launch the TUI from click actions (default $TERMINAL)
+	Terminal string `json:"terminal,omitempty"`
+	// Openers are command templates for opening links by kind (teams, zoom,
+	// outlook, web), e.g. "chromium --app={{.URL}}". Kinds without one use xdg-open.
+	Openers map[string]string `json:"openers,omitempty"`
+	// Pango enables rich markup (bold subject, dim time, colored status)
+	// in waybar text and tooltips
+	Pango bool `json:"pango,omitempty"`
+	// CategoryColors looks up Outlook category colors for cat-<color>
+	// classes and tooltip titles, which needs MailboxSettings.Read
+	CategoryColors bool `json:"category_colors,omitempty"`
+	// Privacy hides the subject, location and attendees of events marked
+	// private or confidential in the bar and tooltips
+	Privacy bool `json:"privacy,omitempty"`
+	// Sounds plays alert sounds from the daemon as meetings turn urgent and start
+	Sounds SoundsConfig `json:"sounds"`
+	// Presence has the daemon announce meeting starts according to whether
+	// the user is at the keyboard
+	Presence PresenceConfig `json:"presence"`
+	// AutoStatus publishes "In a meeting until ..." to Slack and a state file
+	AutoStatus AutoStatusConfig `json:"auto_status"`
+	// MQTT publishes the next meeting to a broker for Home Assistant
+	MQTT MQTTConfig `json:"mqtt"`
+	// Webhooks are posted to by the daemon as meetings start and end
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Hooks are commands the daemon runs as meetings move through their
+	// lifecycle
+	Hooks []Hook `json:"hooks,omitempty"`
+	// Push sends meeting reminders to a phone through ntfy or Gotify
+	Push PushConfig `json:"push"`
+	// Changes controls the daemon's notifications about calendar changes
+	Changes ChangesConfig `json:"changes"`
+	// Refresh bounds the daemon's adaptive refresh schedule
+	Refresh RefreshConfig `json:"refresh"`
+	// Live has the daemon refresh as soon as Graph reports a change, through
+	// a relay
+	Live LiveConfig `json:"live"`
+	// AutoPrivacy lets the daemon turn privacy mode on while the screen is shared
+	AutoPrivacy AutoPrivacyConfig `json:"auto_privacy"`
+	// Theme colors the TUI, agenda and other terminal output
+	Theme ThemeConfig `json:"theme"`
+	// Icons overrides status indicators by status name (current, urgent,
+	// soon, upcoming, past, event)
+	Icons map[string]string `json:"icons,omitempty"`
+	// BlockingShowAs lists the showAs values (free, tentative, busy, oof,
+	// workingElsewhere, unknown) that count as blocking. Empty uses the defaults.
+	BlockingShowAs []string `json:"blocking_show_as,omitempty"`
+	// Proxy is an explicit proxy URL for Graph and sign-in requests. Empty
+	// uses HTTP_PROXY/HTTPS_PROXY from the environment.
+	Proxy string `json:"proxy,omitempty"`
+	// CACertPath is a PEM bundle to trust in addition to the system roots
+	CACertPath string `json:"ca_cert_path,omitempty"`
+	// LeaveBy makes in-person meetings urgent when it is time to leave
+	LeaveBy LeaveByConfig `json:"leave_by"`
+	// Notes opens or creates meeting notes from a click or the TUI
+	Notes NotesConfig `json:"notes"`
+	// Rooms are the email addresses of the meeting rooms `rooms` checks.
+	// Empty lists every room in the organization's directory.
+	Rooms []string `json:"rooms,omitempty"`
+	// GroupCalendars are Microsoft 365 groups, by ID or display name, whose
+	// calendars are shown alongside your own
+	GroupCalendars []string `json:"group_calendars,omitempty"`
+	// LookaheadDays is how far ahead upcoming events and the next meeting
+	// are searched (0 uses 7 days)
+	LookaheadDays int `json:"lookahead_days,omitempty"`
+	// MaxEvents caps how many events are paged through per query (0 uses the built-in cap)
+	MaxEvents int `json:"max_events,omitempty"`
+	// CalendarReminders uses each event's Outlook reminder instead of the
+	// fixed five minutes for turning urgent and for notifications
+	CalendarReminders bool `json:"calendar_reminders,omitempty"`
+	// PercentageHorizon is how long before a meeting the waybar percentage
+	// starts rising from 0, e.g. "2h" (empty uses 1h)
+	PercentageHorizon string `json:"percentage_horizon,omitempty"`
+	// SingleClass emits only the status class, as a string, instead of the
+	// class list (status, provider, category, conflict, ...)
+	SingleClass bool `json:"single_class,omitempty"`
+	// ClassRules add waybar classes to meetings matching a keyword, so
+	// special meeting types can be styled in CSS
+	ClassRules []ClassRule `json:"class_rules,omitempty"`
+	// Cancelled is "hide" (the default) to drop cancelled meetings still on
+	// the calendar, or "show" to strike them through with a cancelled class
+	Cancelled string `json:"cancelled,omitempty"`
+}
+
+// CountdownConfig holds text/template formats for the countdown display.
+// Templates receive a CountdownData value.
+type CountdownConfig struct {
+	Hours   string `json:"hours"`
+	Minutes string `json:"minutes"`
+	Urgent  string `json:"urgent"`
+	Current string `json:"current"`
+}
+
+// AutojoinConfig controls the daemon's automatic meeting join. It is opt-in.
+type AutojoinConfig struct {
+	Enabled bool `json:"enabled"`
+	// LeadSeconds is how long before the start time the link is opened
+	LeadSeconds int `json:"lead_seconds"`
+	// CancelSeconds is how long the cancellable notification is shown first
+	CancelSeconds int `json:"cancel_seconds"`
+}
+
+// SoundsConfig controls the daemon's alert sounds. It is opt-in.
+type SoundsConfig struct {
+	Enabled bool `json:"enabled"`
+	// Files maps the status a meeting moves to (soon, urgent, current) to
+	// the sound file played; an empty path plays nothing
+	Files map[string]string `json:"files,omitempty"`
+	// QuietHours such as "22:00-07:00" mutes sounds every day
+	QuietHours string `json:"quiet_hours,omitempty"`
+	// Command plays the file given as its last argument. Empty tries
+	// pw-play, paplay and aplay.
+	Command string `json:"command,omitempty"`
+}
+
+// PresenceConfig controls the daemon's idle-aware start notifications. It
+// is opt-in. Durations are strings such as "2m".
+type PresenceConfig struct {
+	Enabled bool `json:"enabled"`
+	// IdleAfter is how long without input counts as away
+	IdleAfter string `json:"idle_after"`
+	// RepeatEvery is how often the notification repeats while away
+	RepeatEvery string `json:"repeat_every"`
+	// RepeatFor is how long after the start repeating stops
+	RepeatFor string `json:"repeat_for"`
+}
+
+// AutoStatusConfig controls the daemon's meeting status publishing. It is
+// opt-in; each target is used when configured.
+type AutoStatusConfig struct {
+	Enabled bool `json:"enabled"`
+	// SlackToken is a Slack user token with the users.profile.write scope
+	SlackToken string `json:"slack_token,omitempty"`
+	// SlackWebhook is a Slack workflow webhook used instead of a token
+	SlackWebhook string `json:"slack_webhook,omitempty"`
+	// SlackEmoji is shown next to the status text
+	SlackEmoji string `json:"slack_emoji"`
+	// File receives the state as JSON. Empty uses meeting-status.json in
+	// the state directory, ~/.local/state/calendar-widget by default.
+	File string `json:"file,omitempty"`
+}
+
+// MQTTConfig controls the daemon's MQTT publisher. It is opt-in.
+type MQTTConfig struct {
+	Enabled bool `json:"enabled"`
+	// Broker is a URL such as tcp://homeassistant.local:1883 or ssl://host:8883
+	Broker   string `json:"broker"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// ClientID defaults to calendar-widget-<hostname>
+	ClientID string `json:"client_id,omitempty"`
+	// Topic is the base topic for state and availability
+	Topic string `json:"topic"`
+	// DiscoveryPrefix is Home Assistant's discovery prefix
+	DiscoveryPrefix string `json:"discovery_prefix"`
+}
+
+// Webhook is a URL the daemon posts JSON to on the events it subscribes to
+type Webhook struct {
+	URL string `json:"url"`
+	// Events are meeting-starting, meeting-started, meeting-ended and
+	// auth-required. Empty subscribes to all of them.
+	Events []string `json:"events,omitempty"`
+	// Headers are added to each request, e.g. for authorization
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Hook is a command run when a meeting moves from one lifecycle state to
+// another: none, upcoming, soon, urgent, current or past. An empty From or
+// To matches any state.
+type Hook struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Command string `json:"command"`
+}
+
+// PushConfig sends meeting reminders to a phone through ntfy or Gotify
+type PushConfig struct {
+	// Service is "ntfy" or "gotify"; empty turns pushing off
+	Service string `json:"service,omitempty"`
+	// Server is the server's base URL (ntfy defaults to https://ntfy.sh)
+	Server string `json:"server,omitempty"`
+	// Topic is the ntfy topic to publish to
+	Topic string `json:"topic,omitempty"`
+	// Token is an ntfy access token or a Gotify application token
+	Token string `json:"token,omitempty"`
+	// IdleOnly skips the reminders from the notify command, so only the
+	// daemon's escalated start notifications are pushed while you are away
+	// from the keyboard (needs presence)
+	IdleOnly bool `json:"idle_only,omitempty"`
+}
+
+// ChangesConfig controls notifications about events changing between the
+// daemon's refreshes
+type ChangesConfig struct {
+	// NotifyMoved sends a notification such as "Standup moved to 10:30"
+	// when an upcoming meeting gets another time or location
+	NotifyMoved bool `json:"notify_moved,omitempty"`
+	// NotifyAdded sends "New meeting added: ..." when a meeting starting
+	// within a day shows up on the calendar, so last-minute invites don't go
+	// unnoticed. It is on by default.
+	NotifyAdded bool `json:"notify_added"`
+}
+
+// RefreshConfig bounds how often the daemon refreshes. It refreshes every
+// MinInterval once a meeting is soon, and waits up to MaxInterval during
+// free stretches.
+type RefreshConfig struct {
+	MinInterval string `json:"min_interval"`
+	MaxInterval string `json:"max_interval"`
+}
+
+// LiveConfig points the daemon at a relay for Graph change notifications
+type LiveConfig struct {
+	// NotificationURL is the relay's public HTTPS URL that Graph posts
+	// change notifications to
+	NotificationURL string `json:"notification_url,omitempty"`
+	// PollURL is where the daemon long-polls the relay for them
+	PollURL string `json:"poll_url,omitempty"`
+	// Token is sent as a bearer token when polling
+	Token string `json:"token,omitempty"`
+}
+
+// AutoPrivacyConfig controls screen sharing detection in the daemon
+type AutoPrivacyConfig struct {
+	Enabled bool `json:"enabled"`
+	// Command exits with status 0 while the screen is being shared. Empty
+	// asks PipeWire (pw-dump) for active screencast streams.
+	Command string `json:"command,omitempty"`
+}
+
+// LeaveByConfig sets the travel time to meetings with a physical location
+type LeaveByConfig struct {
+	// Buffer is a fixed travel time such as "20m". Empty disables leave-by
+	// unless Command is set.
+	Buffer string `json:"buffer,omitempty"`
+	// Command prints the travel time to the location passed as its last
+	// argument, e.g. a script calling a routing API. Failures fall back to Buffer.
+	Command string `json:"command,omitempty"`
+}
+
+// NotesConfig derives a notes file or URL from an event
+type NotesConfig struct {
+	// Template is a text/template for a file path or a URL, e.g.
+	// "obsidian://new?name={{.Date}}-{{.Subject}}" or "~/notes/{{.Date}}-{{.Subject}}.md"
+	Template string `json:"template,omitempty"`
+	// Content is the template for new notes files (URLs are opened as is)
+	Content string `json:"content,omitempty"`
+}
+
+// Cancelled meeting handling
+const (
+	CancelledHide = "hide"
+	CancelledShow = "show"
+)
+
+// Class rule fields
+const (
+	ClassFieldSubject   = "subject"
+	ClassFieldLocation  = "location"
+	ClassFieldOrganizer = "organizer"
+	ClassFieldCategory  = "category"
+)
+
+// ClassRule adds Class to meetings whose Field contains Keyword
+// (case-insensitive)
+type ClassRule struct {
+	Keyword string `json:"keyword"`
+	// Field is subject (the default), location, organizer or category
+	Field string `json:"field,omitempty"`
+	Class string `json:"class"`
+}
+
+// NextMeetingsConfig controls how many meetings the multi display mode
+// shows inline and what goes between them
+type NextMeetingsConfig struct {
+	Count     int    `json:"count"`
+	Separator string `json:"separator"`
+}
+
+// LayoutConfig holds the layout preset of each mode: "micro" (icon and
+// minutes), "compact" (icon and title), "full" (icon, start time, title and
+// location) or "" for the standard icon, title and countdown
+type LayoutConfig struct {
+	TUI    string `json:"tui,omitempty"`
+	Waybar string `json:"waybar,omitempty"`
+}
+
+// ThemeConfig picks the terminal colors
+type ThemeConfig struct {
+	// Name is "dark" (the default), "light" or "auto" to follow the
+	// terminal's background
+	Name string `json:"name,omitempty"`
+	// Palette imports colors from a pywal colors.json or a base16 scheme file
+	Palette string `json:"palette,omitempty"`
+	// Colors overrides single colors by role (accent, muted, dim, error,
+	// urgent, soon, upcoming, current and their *_text), e.g. "#FF5555" or "9"
+	Colors map[string]string `json:"colors,omitempty"`
+}
+
+// TruncateConfig controls how long subjects are shortened
+type TruncateConfig struct {
+	// MaxWidth is the widest a subject in the bar may be, in terminal
+	// columns including the ellipsis (0 uses 45)
+	MaxWidth int `json:"max_width"`
+	// Ellipsis ends a shortened subject, e.g. "…"; "" cuts without a marker
+	Ellipsis string `json:"ellipsis"`
+}
+
+// Tooltip styles
+const (
+	TooltipList  = "list"
+	TooltipTable = "table"
+)
+
+// TooltipConfig controls the layout of the waybar tooltip
+type TooltipConfig struct {
+	// Style is "list" (one line per event) or "table" (aligned columns
+	// grouped by day, including upcoming days)
+	Style string `json:"style"`
+	// MaxRows caps the number of event rows in the table (0 means no limit)
+	MaxRows int `json:"max_rows"`
+	// ShowAttendees adds attendee count, organizer and my response status
+	// after each title, e.g. "Standup (8 ppl, J. Smith, ✓)"
+	ShowAttendees bool `json:"show_attendees"`
+}
+
+// Click actions
+const (
+	ActionOpenMeeting = "open-meeting"
+	ActionTUI         = "tui"
+	ActionRefresh     = "refresh"
+	ActionOutlook     = "outlook"
+	ActionSnooze      = "snooze"
+	ActionNotes       = "notes"
+	ActionNone        = "none"
+)
+
+// ClickConfig maps waybar mouse buttons to click actions
+type ClickConfig struct {
+	Left       string `json:"left"`
+	Middle     string `json:"middle"`
+	Right      string `json:"right"`
+	ScrollUp   string `json:"scroll-up"`
+	ScrollDown string `json:"scroll-down"`
+	// Signal is the waybar RTMIN+N signal sent after a refresh or snooze action
+	Signal int `json:"signal"`
+	// SnoozeFor is how long the snooze action mutes the widget
+	SnoozeFor string `json:"snooze_for"`
+}
+
+// Action returns the action configured for the given button
+func (cc ClickConfig) Action(button string) (string, error) {
+	var action string
+	switch button {
+	case "left":
+		action = cc.Left
+	case "middle":
+		action = cc.Middle
+	case "right":
+		action = cc.Right
+	case "scroll-up":
+		action = cc.ScrollUp
+	case "scroll-down":
+		action = cc.ScrollDown
+	default:
+		return "", fmt.Errorf("unknown button %q", button)
+	}
+
+	if action == "" {
+		return ActionNone, nil
+	}
+	return action, nil
+}
+
+func Default() *Config {
+	return &Config{
+		Countdown: CountdownConfig{
+			Hours:   "{{.Subject}} in {{.Hours}}h{{.Minutes}}m",
+			Minutes: "{{.Subject}} in {{.Minutes}}m",
+			Urgent:  "{{.Subject}} in {{.Minutes}}m{{.Seconds}}s",
+			Current: "{{.Subject}} now",
+		},
+		Autojoin: AutojoinConfig{
+			LeadSeconds:   60,
+			CancelSeconds: 15,
+		},
+		Presence: PresenceConfig{
+			IdleAfter:   "2m",
+			RepeatEvery: "1m",
+			RepeatFor:   "10m",
+		},
+		AutoStatus: AutoStatusConfig{
+			SlackEmoji: ":spiral_calendar_pad:",
+		},
+		MQTT: MQTTConfig{
+			Broker:          "tcp://localhost:1883",
+			Topic:           "calendar-widget",
+			DiscoveryPrefix: "homeassistant",
+		},
+		Sounds: SoundsConfig{
+			Files: map[string]string{
+				"urgent":  "/usr/share/sounds/freedesktop/stereo/bell.oga",
+				"current": "/usr/share/sounds/freedesktop/stereo/complete.oga",
+			},
+		},
+		Changes: ChangesConfig{
+			NotifyAdded: true,
+		},
+		Refresh: RefreshConfig{
+			MinInterval: "30s",
+			MaxInterval: "5m",
+		},
+		Click: ClickConfig{
+			Left:       ActionOpenMeeting,
+			Middle:     ActionOutlook,
+			Right:      ActionTUI,
+			ScrollUp:   ActionNone,
+			ScrollDown: ActionNone,
+			Signal:     8,
+			SnoozeFor:  "1h",
+		},
+		Tooltip: TooltipConfig{
+			Style:   TooltipList,
+			MaxRows: 15,
+		},
+		NextMeetings: NextMeetingsConfig{
+			Count:     3,
+			Separator: " · ",
+		},
+		Truncate: TruncateConfig{
+			MaxWidth: 45,
+			Ellipsis: "...",
+		},
+	}
+}
+
+func GetSettingsPath() string {
+	return xdg.ConfigPath("settings.json")
+}
+
+// Load reads the settings file at path (or the default location when path is
+// empty). Missing files and missing keys fall back to Default().
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = GetSettingsPath()
+	}
+
+	config := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+
+	return config, nil
+}
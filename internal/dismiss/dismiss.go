@@ -0,0 +1,88 @@
+// Package dismiss remembers meetings whose reminder was dismissed, so
+// notifications and alert sounds stop for them.
+package dismiss
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/xdg"
+)
+
+// State maps dismissed meetings to when they end, after which they are
+// forgotten
+type State struct {
+	Events map[string]time.Time `json:"events"`
+}
+
+func GetDismissedPath() string {
+	return xdg.StatePath("dismissed.json")
+}
+
+func Load() (*State, error) {
+	state := &State{Events: map[string]time.Time{}}
+
+	data, err := os.ReadFile(GetDismissedPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read dismissed reminders: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse dismissed reminders: %w", err)
+	}
+	if state.Events == nil {
+		state.Events = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// Add records event's reminder as dismissed, dropping meetings that have ended
+func Add(event calendar.Event) error {
+	state, err := Load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for k, end := range state.Events {
+		if !end.After(now) {
+			delete(state.Events, k)
+		}
+	}
+	state.Events[key(event)] = event.End
+
+	path := GetDismissedPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dismissed reminders: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Active reports whether event's reminder has been dismissed
+func Active(event calendar.Event) bool {
+	state, err := Load()
+	if err != nil {
+		return false
+	}
+	_, ok := state.Events[key(event)]
+	return ok
+}
+
+// key identifies one occurrence of a meeting. The start is included
+// because providers without per-occurrence IDs repeat the ID.
+func key(event calendar.Event) string {
+	return event.ID + "@" + event.Start.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,237 @@
+// Package homeassistant publishes the next meeting to an MQTT broker, with
+// Home Assistant discovery so the sensors show up without configuration.
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/mqtt"
+	"calendar-widget/internal/selection"
+)
+
+const (
+	// nodeID groups the discovered entities under one device
+	nodeID = "calendar_widget"
+	// pingEvery stays well inside mqtt.KeepAlive
+	pingEvery = 30 * time.Second
+	// retryEvery is how long to wait before reconnecting to the broker
+	retryEvery = 30 * time.Second
+)
+
+// State is published, retained, to <topic>/state
+type State struct {
+	// Status is the meeting's status (upcoming, soon, urgent, current) or
+	// "none" without a meeting ahead
+	Status   string    `json:"status"`
+	Subject  string    `json:"subject"`
+	Start    time.Time `json:"start,omitzero"`
+	End      time.Time `json:"end,omitzero"`
+	Location string    `json:"location,omitempty"`
+}
+
+// Current returns the state for the meeting the bar would show
+func Current(events []calendar.Event, now time.Time) State {
+	event := calendar.SelectNextMeeting(events, now, selection.Meetings)
+	if event == nil {
+		return State{Status: "none"}
+	}
+
+	state := State{
+		Status:   event.GetStatus(),
+		Subject:  event.Subject,
+		Start:    event.Start,
+		End:      event.End,
+		Location: event.Location,
+	}
+	if event.IsPrivate() {
+		state.Subject = i18n.T("Busy")
+		state.Location = ""
+	}
+	return state
+}
+
+// Publisher keeps the broker up to date with the daemon's events
+type Publisher struct {
+	opts            mqtt.Options
+	topic           string
+	discoveryPrefix string
+	onError         func(err error)
+
+	mu     sync.Mutex
+	events []calendar.Event
+
+	client      *mqtt.Client
+	last        *State
+	lastPing    time.Time
+	lastAttempt time.Time
+}
+
+// NewPublisher publishes below topic, announcing the sensors under
+// discoveryPrefix (usually "homeassistant")
+func NewPublisher(opts mqtt.Options, topic, discoveryPrefix string, onError func(err error)) *Publisher {
+	opts.Will = &mqtt.Message{Topic: topic + "/availability", Payload: []byte("offline"), Retain: true}
+	return &Publisher{
+		opts:            opts,
+		topic:           topic,
+		discoveryPrefix: discoveryPrefix,
+		onError:         onError,
+	}
+}
+
+// Update replaces the set of events the publisher follows
+func (p *Publisher) Update(events []calendar.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = events
+}
+
+// Run publishes state changes every second until ctx is done, then marks
+// the sensors unavailable
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if p.client != nil {
+				p.client.Publish(p.availability("offline"))
+				p.client.Close()
+			}
+			return
+		case <-ticker.C:
+			if err := p.tick(ctx); err != nil {
+				p.onError(err)
+				p.disconnect()
+			}
+		}
+	}
+}
+
+func (p *Publisher) tick(ctx context.Context) error {
+	if p.client == nil {
+		if time.Since(p.lastAttempt) < retryEvery {
+			return nil
+		}
+		p.lastAttempt = time.Now()
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	state := Current(p.events, calendar.Now())
+	p.mu.Unlock()
+
+	if p.last == nil || *p.last != state {
+		payload, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to marshal meeting state: %w", err)
+		}
+		if err := p.client.Publish(mqtt.Message{Topic: p.topic + "/state", Payload: payload, Retain: true}); err != nil {
+			return err
+		}
+		p.last = &state
+	}
+
+	if time.Since(p.lastPing) >= pingEvery {
+		if err := p.client.Ping(); err != nil {
+			return err
+		}
+		p.lastPing = time.Now()
+	}
+	return nil
+}
+
+func (p *Publisher) connect(ctx context.Context) error {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mqtt.Connect(connectCtx, p.opts)
+	if err != nil {
+		return err
+	}
+	p.client = client
+	p.lastPing = time.Now()
+
+	for _, msg := range p.discovery() {
+		if err := client.Publish(msg); err != nil {
+			return err
+		}
+	}
+	return client.Publish(p.availability("online"))
+}
+
+// disconnect drops a broken connection so the next tick reconnects and
+// republishes everything
+func (p *Publisher) disconnect() {
+	if p.client != nil {
+		p.client.Close()
+	}
+	p.client = nil
+	p.last = nil
+}
+
+func (p *Publisher) availability(value string) mqtt.Message {
+	return mqtt.Message{Topic: p.topic + "/availability", Payload: []byte(value), Retain: true}
+}
+
+// discovery returns the retained config messages announcing the sensors
+func (p *Publisher) discovery() []mqtt.Message {
+	entities := []struct {
+		component string
+		id        string
+		config    map[string]any
+	}{
+		{"sensor", "next_meeting", map[string]any{
+			"name":                  "Next meeting",
+			"icon":                  "mdi:calendar-clock",
+			"value_template":        "{{ value_json.subject }}",
+			"json_attributes_topic": p.topic + "/state",
+		}},
+		{"sensor", "meeting_status", map[string]any{
+			"name":           "Meeting status",
+			"icon":           "mdi:calendar-alert",
+			"value_template": "{{ value_json.status }}",
+		}},
+		{"sensor", "next_meeting_start", map[string]any{
+			"name":           "Next meeting start",
+			"device_class":   "timestamp",
+			"value_template": "{{ value_json.start | default(None) }}",
+		}},
+		{"binary_sensor", "in_meeting", map[string]any{
+			"name":           "In meeting",
+			"icon":           "mdi:account-group",
+			"value_template": "{{ 'ON' if value_json.status == 'current' else 'OFF' }}",
+		}},
+	}
+
+	device := map[string]any{
+		"identifiers":  []string{nodeID},
+		"name":         "Calendar widget",
+		"manufacturer": "calendar-widget",
+	}
+
+	var messages []mqtt.Message
+	for _, entity := range entities {
+		config := entity.config
+		config["unique_id"] = nodeID + "_" + entity.id
+		config["state_topic"] = p.topic + "/state"
+		config["availability_topic"] = p.topic + "/availability"
+		config["device"] = device
+
+		payload, _ := json.Marshal(config)
+		messages = append(messages, mqtt.Message{
+			Topic:   fmt.Sprintf("%s/%s/%s/%s/config", p.discoveryPrefix, entity.component, nodeID, entity.id),
+			Payload: payload,
+			Retain:  true,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,94 @@
+// Package hooks runs user commands as meetings move through their
+// lifecycle, e.g. to start a recording when a meeting starts or to dim the
+// lights when one turns urgent.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"calendar-widget/internal/alerts"
+	"calendar-widget/internal/config"
+)
+
+// timeout bounds each hook command so a stuck script doesn't pile up
+const timeout = time.Minute
+
+// Validate checks that every hook has a command and known states
+func Validate(hooks []config.Hook) error {
+	for i, hook := range hooks {
+		if hook.Command == "" {
+			return fmt.Errorf("hook %d has no command", i+1)
+		}
+		for _, state := range []string{hook.From, hook.To} {
+			if state != "" && !alerts.IsState(state) {
+				return fmt.Errorf("hook %d: unknown state %q", i+1, state)
+			}
+		}
+	}
+	return nil
+}
+
+// Runner runs the hooks matching each transition
+type Runner struct {
+	hooks   []config.Hook
+	onError func(err error)
+}
+
+func NewRunner(hooks []config.Hook, onError func(err error)) *Runner {
+	return &Runner{hooks: hooks, onError: onError}
+}
+
+// Run runs every hook matching transition with sh, one after the other.
+// The meeting is passed in CALENDAR_WIDGET_* environment variables.
+func (r *Runner) Run(ctx context.Context, transition alerts.Transition) {
+	for _, hook := range r.hooks {
+		if !matches(hook, transition) {
+			continue
+		}
+		if err := run(ctx, hook.Command, transition); err != nil {
+			r.onError(err)
+		}
+	}
+}
+
+func matches(hook config.Hook, transition alerts.Transition) bool {
+	return (hook.From == "" || hook.From == transition.From) && (hook.To == "" || hook.To == transition.To)
+}
+
+func run(ctx context.Context, command string, transition alerts.Transition) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), environment(transition)...)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if out = bytes.TrimSpace(out); len(out) > 0 {
+		return fmt.Errorf("hook %q failed: %w: %s", command, err, out)
+	}
+	return fmt.Errorf("hook %q failed: %w", command, err)
+}
+
+// environment describes the transition and its meeting to the command
+func environment(transition alerts.Transition) []string {
+	event := transition.Event
+	return []string{
+		"CALENDAR_WIDGET_FROM=" + transition.From,
+		"CALENDAR_WIDGET_TO=" + transition.To,
+		"CALENDAR_WIDGET_ID=" + event.ID,
+		"CALENDAR_WIDGET_SUBJECT=" + event.Subject,
+		"CALENDAR_WIDGET_START=" + event.Start.Format(time.RFC3339),
+		"CALENDAR_WIDGET_END=" + event.End.Format(time.RFC3339),
+		"CALENDAR_WIDGET_LOCATION=" + event.Location,
+		"CALENDAR_WIDGET_JOIN_URL=" + event.GetJoinLink(),
+		"CALENDAR_WIDGET_PRIVATE=" + strconv.FormatBool(event.IsPrivate()),
+	}
+}
@@ -0,0 +1,382 @@
+package i18n
+
+import "time"
+
+var locales = map[string]*Locale{
+	"en": {
+		Tag:       "en",
+		Days:      [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		DayMonth:  "2/1",
+		Clock24:   true,
+		Months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		WeekStart: time.Monday,
+	},
+	"en-us": {
+		Tag:       "en-US",
+		Days:      [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		DayMonth:  "1/2",
+		Clock24:   false,
+		Months:    [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		WeekStart: time.Sunday,
+	},
+	"da": {
+		Tag:       "da",
+		Days:      [7]string{"søn", "man", "tir", "ons", "tor", "fre", "lør"},
+		DayMonth:  "2/1",
+		Clock24:   true,
+		Months:    [12]string{"januar", "februar", "marts", "april", "maj", "juni", "juli", "august", "september", "oktober", "november", "december"},
+		WeekStart: time.Monday,
+		Messages: map[string]string{
+			"No upcoming meetings":            "Ingen kommende møder",
+			"No meetings":                     "Ingen møder",
+			"No meetings today":               "Ingen møder i dag",
+			"No meetings scheduled for today": "Ingen møder planlagt i dag",
+			"Today's Schedule":                "Dagens program",
+			"Upcoming Events":                 "Kommende begivenheder",
+			"Tomorrow":                        "I morgen",
+			"All day":                         "Hele dagen",
+			"%d ppl":                          "%d pers.",
+			"in %dm":                          "om %dm",
+			"in %dh%dm":                       "om %dt%dm",
+			"... and %d more events":          "... og %d flere begivenheder",
+			"Click to open meeting link":      "Klik for at åbne mødelinket",
+			"Teams meeting - will open directly in Teams": "Teams-møde - åbnes direkte i Teams",
+			"Will open in browser":                        "Åbnes i browseren",
+			"Auth Required":                               "Login påkrævet",
+			"Click to authenticate":                       "Klik for at logge ind",
+			"Auth Error":                                  "Loginfejl",
+			"Failed to create calendar service":           "Kunne ikke oprette kalendertjenesten",
+			"Calendar Error":                              "Kalenderfejl",
+			"Rate Limited":                                "Begrænset",
+			"Microsoft Graph is throttling requests, will retry on the next refresh": "Microsoft Graph begrænser forespørgsler, prøver igen ved næste opdatering",
+			"No free time today": "Ingen ledig tid i dag",
+			"Free rest of day":   "Ledig resten af dagen",
+			"Free until %s":      "Ledig til %s",
+			"Next free: %s–%s":   "Næste ledige: %s–%s",
+			"No calendar cache":  "Ingen kalendercache",
+			"Start the cache daemon: calendar-widget daemon": "Start cache-dæmonen: calendar-widget daemon",
+			"Last updated %s":  "Sidst opdateret %s",
+			"Snoozed until %s": "Udsat til %s",
+			"Error: %v":        "Fejl: %v",
+			"Starts at %s":     "Starter kl. %s",
+			"Conflict":         "Konflikt",
+			"leave in %dm":     "afgang om %dm",
+			"leave in %dh%dm":  "afgang om %dh%dm",
+			"leave by %s":      "tag af sted kl. %s",
+			"Busy":             "Optaget",
+			"Dial-in: %s":      "Ring ind: %s",
+			"ID %s#":           "ID %s#",
+			"passcode %s#":     "adgangskode %s#",
+			"Organizer: %s":    "Arrangør: %s",
+			"Location: %s":     "Sted: %s",
+			"Attendees (%d)":   "Deltagere (%d)",
+			"Join":             "Deltag",
+			"enter join · m map · o Outlook · y copy link · n notes · ↑/↓ scroll · esc back": "enter deltag · m kort · o Outlook · y kopiér link · n noter · ↑/↓ rul · esc tilbage",
+			"Started at %s":                  "Startede kl. %s",
+			"In a meeting until %s":          "I møde indtil %s",
+			"%s left":                        "%s tilbage",
+			"Back-to-back: %s at %s":         "Lige efter: %s kl. %s",
+			"Free %s-%s":                     "Ledig %s-%s",
+			"%d meetings today":              "%d møder i dag",
+			"1 meeting today":                "1 møde i dag",
+			"First at %s, last ends %s":      "Første kl. %s, sidste slutter %s",
+			"%s in meetings":                 "%s i møder",
+			"Largest free block: %s-%s (%s)": "Største ledige blok: %s-%s (%s)",
+			"Files attached to the invite":   "Filer vedhæftet invitationen",
+			"Documents":                      "Dokumenter",
+			"Could not open link":            "Kunne ikke åbne linket",
+			"now":                            "nu",
+			"in %d days":                     "om %d dage",
+			"No meeting in the next %d days matches %q": "Intet møde de næste %d dage matcher %q",
+			"in 1 day":                            "om 1 dag",
+			"No meetings match %q":                "Ingen møder matcher %q",
+			"Free":                                "Ledig",
+			"Busy until %s":                       "Optaget til %s",
+			"floor %s":                            "etage %s",
+			"No free rooms":                       "Ingen ledige lokaler",
+			"No common free time found":           "Ingen fælles ledig tid fundet",
+			"free until %s":                       "ledig til %s",
+			"meetings":                            "møder",
+			"all-day":                             "heldags",
+			"%d failed refreshes, last: %s at %s": "%d mislykkede opdateringer, senest: %s kl. %s",
+			"updated":                             "opdateret",
+			"%s moved to %s":                      "%s flyttet til %s",
+			"Was %s":                              "Var %s",
+			"no location":                         "intet sted",
+			"New meeting added: %s":               "Nyt møde tilføjet: %s",
+			"Join %s":                             "Deltag i %s",
+			"Refresh":                             "Opdater",
+			"Quit":                                "Afslut",
+		},
+	},
+	"de": {
+		Tag:       "de",
+		Days:      [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		DayMonth:  "2.1.",
+		Clock24:   true,
+		Months:    [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		WeekStart: time.Monday,
+		Messages: map[string]string{
+			"No upcoming meetings":            "Keine anstehenden Termine",
+			"No meetings":                     "Keine Termine",
+			"No meetings today":               "Heute keine Termine",
+			"No meetings scheduled for today": "Für heute sind keine Termine geplant",
+			"Today's Schedule":                "Heutiger Zeitplan",
+			"Upcoming Events":                 "Anstehende Termine",
+			"Tomorrow":                        "Morgen",
+			"All day":                         "Ganztägig",
+			"%d ppl":                          "%d Pers.",
+			"in %dm":                          "in %d Min.",
+			"in %dh%dm":                       "in %d Std. %d Min.",
+			"... and %d more events":          "... und %d weitere Termine",
+			"Click to open meeting link":      "Klicken, um den Besprechungslink zu öffnen",
+			"Teams meeting - will open directly in Teams": "Teams-Besprechung - öffnet direkt in Teams",
+			"Will open in browser":                        "Öffnet im Browser",
+			"Auth Required":                               "Anmeldung erforderlich",
+			"Click to authenticate":                       "Zum Anmelden klicken",
+			"Auth Error":                                  "Anmeldefehler",
+			"Failed to create calendar service":           "Kalenderdienst konnte nicht erstellt werden",
+			"Calendar Error":                              "Kalenderfehler",
+			"Rate Limited":                                "Gedrosselt",
+			"Microsoft Graph is throttling requests, will retry on the next refresh": "Microsoft Graph drosselt Anfragen, neuer Versuch bei der nächsten Aktualisierung",
+			"No free time today": "Heute keine freie Zeit",
+			"Free rest of day":   "Rest des Tages frei",
+			"Free until %s":      "Frei bis %s",
+			"Next free: %s–%s":   "Nächste freie Zeit: %s–%s",
+			"No calendar cache":  "Kein Kalender-Cache",
+			"Start the cache daemon: calendar-widget daemon": "Cache-Dienst starten: calendar-widget daemon",
+			"Last updated %s":  "Zuletzt aktualisiert %s",
+			"Snoozed until %s": "Stummgeschaltet bis %s",
+			"Error: %v":        "Fehler: %v",
+			"Starts at %s":     "Beginnt um %s",
+			"Conflict":         "Konflikt",
+			"leave in %dm":     "los in %dm",
+			"leave in %dh%dm":  "los in %dh%dm",
+			"leave by %s":      "losgehen um %s",
+			"Busy":             "Beschäftigt",
+			"Dial-in: %s":      "Einwahl: %s",
+			"ID %s#":           "ID %s#",
+			"passcode %s#":     "Kenncode %s#",
+			"Organizer: %s":    "Organisator: %s",
+			"Location: %s":     "Ort: %s",
+			"Attendees (%d)":   "Teilnehmer (%d)",
+			"Join":             "Teilnehmen",
+			"enter join · m map · o Outlook · y copy link · n notes · ↑/↓ scroll · esc back": "Enter teilnehmen · m Karte · o Outlook · y Link kopieren · n Notizen · ↑/↓ scrollen · Esc zurück",
+			"Started at %s":                  "Begonnen um %s",
+			"In a meeting until %s":          "In einem Meeting bis %s",
+			"%s left":                        "noch %s",
+			"Back-to-back: %s at %s":         "Direkt danach: %s um %s",
+			"Free %s-%s":                     "Frei %s-%s",
+			"%d meetings today":              "%d Termine heute",
+			"1 meeting today":                "1 Termin heute",
+			"First at %s, last ends %s":      "Erster um %s, letzter endet %s",
+			"%s in meetings":                 "%s in Terminen",
+			"Largest free block: %s-%s (%s)": "Größter freier Block: %s-%s (%s)",
+			"Files attached to the invite":   "An die Einladung angehängte Dateien",
+			"Documents":                      "Dokumente",
+			"Could not open link":            "Link konnte nicht geöffnet werden",
+			"now":                            "jetzt",
+			"in %d days":                     "in %d Tagen",
+			"No meeting in the next %d days matches %q": "Kein Termin in den nächsten %d Tagen passt zu %q",
+			"in 1 day":                            "in 1 Tag",
+			"No meetings match %q":                "Keine Termine passen zu %q",
+			"Free":                                "Frei",
+			"Busy until %s":                       "Belegt bis %s",
+			"floor %s":                            "Etage %s",
+			"No free rooms":                       "Keine freien Räume",
+			"No common free time found":           "Keine gemeinsame freie Zeit gefunden",
+			"free until %s":                       "frei bis %s",
+			"meetings":                            "Termine",
+			"all-day":                             "ganztägig",
+			"%d failed refreshes, last: %s at %s": "%d fehlgeschlagene Aktualisierungen, zuletzt: %s um %s",
+			"updated":                             "aktualisiert",
+			"%s moved to %s":                      "%s verschoben auf %s",
+			"Was %s":                              "War %s",
+			"no location":                         "kein Ort",
+			"New meeting added: %s":               "Neues Meeting hinzugefügt: %s",
+			"Join %s":                             "%s beitreten",
+			"Refresh":                             "Aktualisieren",
+			"Quit":                                "Beenden",
+		},
+	},
+	"fr": {
+		Tag:       "fr",
+		Days:      [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		DayMonth:  "2/1",
+		Clock24:   true,
+		Months:    [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		WeekStart: time.Monday,
+		Messages: map[string]string{
+			"No upcoming meetings":            "Aucune réunion à venir",
+			"No meetings":                     "Aucune réunion",
+			"No meetings today":               "Aucune réunion aujourd'hui",
+			"No meetings scheduled for today": "Aucune réunion prévue aujourd'hui",
+			"Today's Schedule":                "Programme du jour",
+			"Upcoming Events":                 "Événements à venir",
+			"Tomorrow":                        "Demain",
+			"All day":                         "Toute la journée",
+			"%d ppl":                          "%d pers.",
+			"in %dm":                          "dans %d min",
+			"in %dh%dm":                       "dans %d h %d min",
+			"... and %d more events":          "... et %d autres événements",
+			"Click to open meeting link":      "Cliquez pour ouvrir le lien de la réunion",
+			"Teams meeting - will open directly in Teams": "Réunion Teams - s'ouvrira directement dans Teams",
+			"Will open in browser":                        "S'ouvrira dans le navigateur",
+			"Auth Required":                               "Connexion requise",
+			"Click to authenticate":                       "Cliquez pour vous connecter",
+			"Auth Error":                                  "Erreur de connexion",
+			"Failed to create calendar service":           "Impossible de créer le service de calendrier",
+			"Calendar Error":                              "Erreur de calendrier",
+			"Rate Limited":                                "Limité",
+			"Microsoft Graph is throttling requests, will retry on the next refresh": "Microsoft Graph limite les requêtes, nouvel essai à la prochaine actualisation",
+			"No free time today": "Pas de temps libre aujourd'hui",
+			"Free rest of day":   "Libre le reste de la journée",
+			"Free until %s":      "Libre jusqu'à %s",
+			"Next free: %s–%s":   "Prochain créneau libre : %s–%s",
+			"No calendar cache":  "Pas de cache de calendrier",
+			"Start the cache daemon: calendar-widget daemon": "Lancez le démon de cache : calendar-widget daemon",
+			"Last updated %s":  "Dernière mise à jour %s",
+			"Snoozed until %s": "En sourdine jusqu'à %s",
+			"Error: %v":        "Erreur : %v",
+			"Starts at %s":     "Commence à %s",
+			"Conflict":         "Conflit",
+			"leave in %dm":     "départ dans %dm",
+			"leave in %dh%dm":  "départ dans %dh%dm",
+			"leave by %s":      "partir à %s",
+			"Busy":             "Occupé",
+			"Dial-in: %s":      "Accès téléphonique : %s",
+			"ID %s#":           "ID %s#",
+			"passcode %s#":     "code secret %s#",
+			"Organizer: %s":    "Organisateur : %s",
+			"Location: %s":     "Lieu : %s",
+			"Attendees (%d)":   "Participants (%d)",
+			"Join":             "Rejoindre",
+			"enter join · m map · o Outlook · y copy link · n notes · ↑/↓ scroll · esc back": "entrée rejoindre · m carte · o Outlook · y copier le lien · n notes · ↑/↓ défiler · échap retour",
+			"Started at %s":                  "A commencé à %s",
+			"In a meeting until %s":          "En réunion jusqu'à %s",
+			"%s left":                        "%s restantes",
+			"Back-to-back: %s at %s":         "Enchaîné : %s à %s",
+			"Free %s-%s":                     "Libre %s-%s",
+			"%d meetings today":              "%d réunions aujourd'hui",
+			"1 meeting today":                "1 réunion aujourd'hui",
+			"First at %s, last ends %s":      "Première à %s, dernière finit à %s",
+			"%s in meetings":                 "%s en réunion",
+			"Largest free block: %s-%s (%s)": "Plus grand créneau libre : %s-%s (%s)",
+			"Files attached to the invite":   "Fichiers joints à l'invitation",
+			"Documents":                      "Documents",
+			"Could not open link":            "Impossible d'ouvrir le lien",
+			"now":                            "maintenant",
+			"in %d days":                     "dans %d jours",
+			"No meeting in the next %d days matches %q": "Aucune réunion dans les %d prochains jours ne correspond à %q",
+			"in 1 day":                            "dans 1 jour",
+			"No meetings match %q":                "Aucune réunion ne correspond à %q",
+			"Free":                                "Libre",
+			"Busy until %s":                       "Occupée jusqu'à %s",
+			"floor %s":                            "étage %s",
+			"No free rooms":                       "Aucune salle libre",
+			"No common free time found":           "Aucun créneau libre commun trouvé",
+			"free until %s":                       "libre jusqu'à %s",
+			"meetings":                            "réunions",
+			"all-day":                             "toute la journée",
+			"%d failed refreshes, last: %s at %s": "%d actualisations échouées, dernière : %s à %s",
+			"updated":                             "mis à jour",
+			"%s moved to %s":                      "%s déplacé à %s",
+			"Was %s":                              "Était %s",
+			"no location":                         "aucun lieu",
+			"New meeting added: %s":               "Nouvelle réunion ajoutée : %s",
+			"Join %s":                             "Rejoindre %s",
+			"Refresh":                             "Actualiser",
+			"Quit":                                "Quitter",
+		},
+	},
+	"es": {
+		Tag:       "es",
+		Days:      [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		DayMonth:  "2/1",
+		Clock24:   true,
+		Months:    [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		WeekStart: time.Monday,
+		Messages: map[string]string{
+			"No upcoming meetings":            "No hay reuniones próximas",
+			"No meetings":                     "Sin reuniones",
+			"No meetings today":               "Sin reuniones hoy",
+			"No meetings scheduled for today": "No hay reuniones programadas para hoy",
+			"Today's Schedule":                "Agenda de hoy",
+			"Upcoming Events":                 "Próximos eventos",
+			"Tomorrow":                        "Mañana",
+			"All day":                         "Todo el día",
+			"%d ppl":                          "%d pers.",
+			"in %dm":                          "en %d min",
+			"in %dh%dm":                       "en %d h %d min",
+			"... and %d more events":          "... y %d eventos más",
+			"Click to open meeting link":      "Haz clic para abrir el enlace de la reunión",
+			"Teams meeting - will open directly in Teams": "Reunión de Teams - se abrirá directamente en Teams",
+			"Will open in browser":                        "Se abrirá en el navegador",
+			"Auth Required":                               "Inicio de sesión requerido",
+			"Click to authenticate":                       "Haz clic para iniciar sesión",
+			"Auth Error":                                  "Error de inicio de sesión",
+			"Failed to create calendar service":           "No se pudo crear el servicio de calendario",
+			"Calendar Error":                              "Error de calendario",
+			"Rate Limited":                                "Limitado",
+			"Microsoft Graph is throttling requests, will retry on the next refresh": "Microsoft Graph está limitando las solicitudes, se reintentará en la próxima actualización",
+			"No free time today": "Sin tiempo libre hoy",
+			"Free rest of day":   "Libre el resto del día",
+			"Free until %s":      "Libre hasta las %s",
+			"Next free: %s–%s":   "Próximo hueco libre: %s–%s",
+			"No calendar cache":  "Sin caché de calendario",
+			"Start the cache daemon: calendar-widget daemon": "Inicia el demonio de caché: calendar-widget daemon",
+			"Last updated %s":  "Última actualización %s",
+			"Snoozed until %s": "Silenciado hasta las %s",
+			"Error: %v":        "Error: %v",
+			"Starts at %s":     "Empieza a las %s",
+			"Conflict":         "Conflicto",
+			"leave in %dm":     "salir en %dm",
+			"leave in %dh%dm":  "salir en %dh%dm",
+			"leave by %s":      "salir a las %s",
+			"Busy":             "Ocupado",
+			"Dial-in: %s":      "Acceso telefónico: %s",
+			"ID %s#":           "ID %s#",
+			"passcode %s#":     "código de acceso %s#",
+			"Organizer: %s":    "Organizador: %s",
+			"Location: %s":     "Ubicación: %s",
+			"Attendees (%d)":   "Asistentes (%d)",
+			"Join":             "Unirse",
+			"enter join · m map · o Outlook · y copy link · n notes · ↑/↓ scroll · esc back": "intro unirse · m mapa · o Outlook · y copiar enlace · n notas · ↑/↓ desplazar · esc volver",
+			"Started at %s":                  "Empezó a las %s",
+			"In a meeting until %s":          "En una reunión hasta las %s",
+			"%s left":                        "quedan %s",
+			"Back-to-back: %s at %s":         "Sin pausa: %s a las %s",
+			"Free %s-%s":                     "Libre %s-%s",
+			"%d meetings today":              "%d reuniones hoy",
+			"1 meeting today":                "1 reunión hoy",
+			"First at %s, last ends %s":      "Primera a las %s, la última termina a las %s",
+			"%s in meetings":                 "%s en reuniones",
+			"Largest free block: %s-%s (%s)": "Mayor bloque libre: %s-%s (%s)",
+			"Files attached to the invite":   "Archivos adjuntos a la invitación",
+			"Documents":                      "Documentos",
+			"Could not open link":            "No se pudo abrir el enlace",
+			"now":                            "ahora",
+			"in %d days":                     "en %d días",
+			"No meeting in the next %d days matches %q": "Ninguna reunión en los próximos %d días coincide con %q",
+			"in 1 day":                            "en 1 día",
+			"No meetings match %q":                "Ninguna reunión coincide con %q",
+			"Free":                                "Libre",
+			"Busy until %s":                       "Ocupada hasta las %s",
+			"floor %s":                            "planta %s",
+			"No free rooms":                       "No hay salas libres",
+			"No common free time found":           "No se encontró tiempo libre en común",
+			"free until %s":                       "libre hasta las %s",
+			"meetings":                            "reuniones",
+			"all-day":                             "todo el día",
+			"%d failed refreshes, last: %s at %s": "%d actualizaciones fallidas, la última: %s a las %s",
+			"updated":                             "actualizado",
+			"%s moved to %s":                      "%s movido a %s",
+			"Was %s":                              "Era %s",
+			"no location":                         "sin ubicación",
+			"New meeting added: %s":               "Nueva reunión añadida: %s",
+			"Join %s":                             "Unirse a %s",
+			"Refresh":                             "Actualizar",
+			"Quit":                                "Salir",
+		},
+	},
+}
@@ -0,0 +1,149 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Locale is a translation bundle plus the date conventions that go with it
+type Locale struct {
+	Tag string
+	// Messages maps English source strings to their translation. Missing
+	// entries fall back to English.
+	Messages map[string]string
+	// Days holds abbreviated weekday names, Sunday first
+	Days [7]string
+	// DayMonth is the Go layout for a numeric day and month, e.g. "2/1"
+	DayMonth string
+	// Clock24 is whether the locale uses a 24-hour clock
+	Clock24 bool
+	// Months holds full month names, January first
+	Months [12]string
+	// WeekStart is the first day of the week in month views
+	WeekStart time.Weekday
+}
+
+var (
+	current = locales["en"]
+	// clock24 overrides the locale's clock when set
+	clock24 *bool
+)
+
+// SetLocale selects the active locale by tag ("da-DK", "de", "en_US.UTF-8").
+// An empty tag is read from LC_ALL, LC_TIME or LANG. Unknown tags fall back to
+// the language alone and then to English.
+func SetLocale(tag string) {
+	if tag == "" {
+		tag = localeFromEnvironment()
+	}
+	current = Lookup(tag)
+}
+
+// SetTimeFormat forces a "12h" or "24h" clock. An empty format uses the
+// locale's default.
+func SetTimeFormat(format string) error {
+	switch format {
+	case "":
+		clock24 = nil
+	case "24h":
+		value := true
+		clock24 = &value
+	case "12h":
+		value := false
+		clock24 = &value
+	default:
+		return fmt.Errorf("invalid time format %q: use 12h or 24h", format)
+	}
+	return nil
+}
+
+// TimeFormat returns the format forced by SetTimeFormat, or "" when the
+// locale's clock is used
+func TimeFormat() string {
+	switch {
+	case clock24 == nil:
+		return ""
+	case *clock24:
+		return "24h"
+	default:
+		return "12h"
+	}
+}
+
+// Current returns the active locale
+func Current() *Locale {
+	return current
+}
+
+// Lookup finds the best matching locale for tag
+func Lookup(tag string) *Locale {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+
+	if locale, ok := locales[tag]; ok {
+		return locale
+	}
+	if i := strings.Index(tag, "-"); i >= 0 {
+		if locale, ok := locales[tag[:i]]; ok {
+			return locale
+		}
+	}
+	return locales["en"]
+}
+
+func localeFromEnvironment() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if value := os.Getenv(name); value != "" && value != "C" && value != "POSIX" {
+			return value
+		}
+	}
+	return "en"
+}
+
+// T translates an English source string and formats it with args
+func T(message string, args ...any) string {
+	if translated, ok := current.Messages[message]; ok {
+		message = translated
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Weekday returns the abbreviated localized name of t's weekday
+func Weekday(t time.Time) string {
+	return current.Days[t.Weekday()]
+}
+
+// Month returns the localized name of t's month
+func Month(t time.Time) string {
+	return current.Months[t.Month()-1]
+}
+
+// WeekStart returns the first day of the week
+func WeekStart() time.Weekday {
+	return current.WeekStart
+}
+
+// FormatTime renders a clock time using the configured 12h/24h clock
+func FormatTime(t time.Time) string {
+	use24 := current.Clock24
+	if clock24 != nil {
+		use24 = *clock24
+	}
+	if use24 {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04 PM")
+}
+
+// FormatDate renders a short date such as "Mon 24/9"
+func FormatDate(t time.Time) string {
+	return Weekday(t) + " " + t.Format(current.DayMonth)
+}
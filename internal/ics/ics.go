@@ -0,0 +1,127 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	dateFormat     = "20060102"
+	// maxLineOctets is the RFC 5545 line length limit before folding
+	maxLineOctets = 75
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// Write serializes events as an iCalendar (RFC 5545) document. Series masters
+// get an RRULE and exceptions a RECURRENCE-ID; join links go into URL.
+func Write(w io.Writer, events []calendar.Event) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(dateTimeFormat)
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//calendar-widget//EN")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+
+	for _, event := range events {
+		writeEvent(bw, event, stamp)
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+func writeEvent(w *bufio.Writer, event calendar.Event, stamp string) {
+	uid := event.ICalUID
+	if uid == "" {
+		uid = event.ID
+	}
+
+	writeLine(w, "BEGIN:VEVENT")
+	writeLine(w, "UID:"+uid)
+	writeLine(w, "DTSTAMP:"+stamp)
+
+	if event.IsAllDay {
+		writeLine(w, "DTSTART;VALUE=DATE:"+event.Start.Format(dateFormat))
+		writeLine(w, "DTEND;VALUE=DATE:"+event.End.Format(dateFormat))
+	} else {
+		writeLine(w, "DTSTART:"+event.Start.UTC().Format(dateTimeFormat))
+		writeLine(w, "DTEND:"+event.End.UTC().Format(dateTimeFormat))
+	}
+
+	if event.Recurrence != "" {
+		writeLine(w, "RRULE:"+event.Recurrence)
+	}
+	if event.Type == calendar.EventTypeException && !event.OriginalStart.IsZero() {
+		if event.IsAllDay {
+			writeLine(w, "RECURRENCE-ID;VALUE=DATE:"+event.OriginalStart.Format(dateFormat))
+		} else {
+			writeLine(w, "RECURRENCE-ID:"+event.OriginalStart.UTC().Format(dateTimeFormat))
+		}
+	}
+
+	writeLine(w, "SUMMARY:"+textEscaper.Replace(event.Subject))
+	if event.Location != "" {
+		writeLine(w, "LOCATION:"+textEscaper.Replace(event.Location))
+	}
+	if link := event.GetJoinLink(); link != "" {
+		writeLine(w, "URL:"+link)
+	} else if event.WebLink != "" {
+		writeLine(w, "URL:"+event.WebLink)
+	}
+	if len(event.Categories) > 0 {
+		categories := make([]string, len(event.Categories))
+		for i, category := range event.Categories {
+			categories[i] = textEscaper.Replace(category)
+		}
+		writeLine(w, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	if event.ShowAs == "free" {
+		writeLine(w, "TRANSP:TRANSPARENT")
+	} else {
+		writeLine(w, "TRANSP:OPAQUE")
+	}
+	if event.ShowAs == "tentative" {
+		writeLine(w, "STATUS:TENTATIVE")
+	}
+
+	writeLine(w, "END:VEVENT")
+}
+
+// writeLine writes a content line with CRLF endings, folding it at 75
+// octets without splitting UTF-8 sequences
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
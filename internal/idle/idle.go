@@ -0,0 +1,141 @@
+// Package idle tracks whether the user is at the keyboard, using the
+// Wayland idle-notify protocol through swayidle when available and logind's
+// idle hint otherwise.
+package idle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logindPollInterval is how often logind's idle hint is read
+const logindPollInterval = 10 * time.Second
+
+// Monitor reports whether the user has been idle for longer than a threshold
+type Monitor struct {
+	after time.Duration
+
+	mu   sync.Mutex
+	idle bool
+}
+
+// NewMonitor returns a monitor that counts the user as idle after the given
+// time without input
+func NewMonitor(after time.Duration) *Monitor {
+	return &Monitor{after: after}
+}
+
+// Idle reports whether the user is currently idle
+func (m *Monitor) Idle() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.idle
+}
+
+func (m *Monitor) set(idle bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idle = idle
+}
+
+// Run follows the idle state until ctx is done. On Wayland with swayidle
+// installed it gets idle and resume events from the compositor; otherwise
+// it polls logind.
+func (m *Monitor) Run(ctx context.Context) error {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := exec.LookPath("swayidle"); err == nil {
+			return m.runSwayidle(ctx)
+		}
+	}
+	return m.pollLogind(ctx)
+}
+
+// runSwayidle has swayidle print a line when the user goes idle or comes back
+func (m *Monitor) runSwayidle(ctx context.Context) error {
+	seconds := max(1, int(m.after.Seconds()))
+	cmd := exec.CommandContext(ctx, "swayidle", "-w",
+		"timeout", fmt.Sprint(seconds), "echo idle",
+		"resume", "echo active")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run swayidle: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run swayidle: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		switch strings.TrimSpace(scanner.Text()) {
+		case "idle":
+			m.set(true)
+		case "active":
+			m.set(false)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("swayidle exited: %w", err)
+	}
+	return nil
+}
+
+// pollLogind reads the session's IdleHint, which screen lockers and idle
+// daemons set, and its IdleSinceHint to apply the threshold
+func (m *Monitor) pollLogind(ctx context.Context) error {
+	session := os.Getenv("XDG_SESSION_ID")
+	if session == "" {
+		session = "self"
+	}
+
+	ticker := time.NewTicker(logindPollInterval)
+	defer ticker.Stop()
+
+	for {
+		idle, err := logindIdle(session, m.after)
+		if err != nil {
+			return err
+		}
+		m.set(idle)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func logindIdle(session string, after time.Duration) (bool, error) {
+	out, err := exec.Command("loginctl", "show-session", session, "-p", "IdleHint", "-p", "IdleSinceHint").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read logind idle hint: %w", err)
+	}
+
+	var idle bool
+	var since time.Time
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		switch key {
+		case "IdleHint":
+			idle = value == "yes"
+		case "IdleSinceHint":
+			if usec, err := strconv.ParseInt(value, 10, 64); err == nil && usec > 0 {
+				since = time.UnixMicro(usec)
+			}
+		}
+	}
+
+	if idle && !since.IsZero() {
+		return time.Since(since) >= after, nil
+	}
+	return idle, nil
+}
@@ -0,0 +1,219 @@
+// Package live refreshes the daemon within seconds of calendar changes. It
+// keeps a Microsoft Graph change subscription pointed at a relay, a small
+// public web service that receives Graph's webhooks, and long-polls the
+// relay for them, so no port has to be opened on the desktop.
+package live
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/network"
+)
+
+const (
+	// subscriptionLifetime is how long each subscription is requested for;
+	// Graph allows a little under three days for events
+	subscriptionLifetime = 48 * time.Hour
+	// renewBefore is how long before expiry the subscription is renewed
+	renewBefore = 6 * time.Hour
+	// pollTimeout bounds one long-poll; relays should answer 204 sooner
+	pollTimeout = 90 * time.Second
+	// maxNotificationSize caps how much of a relay response is read
+	maxNotificationSize = 1 << 20
+	minBackoff          = 5 * time.Second
+	maxBackoff          = 5 * time.Minute
+)
+
+// Enabled reports whether a relay is configured
+func Enabled(settings config.LiveConfig) bool {
+	return settings.NotificationURL != "" || settings.PollURL != ""
+}
+
+// Validate checks that both relay URLs are set and usable. Graph only posts
+// notifications to HTTPS URLs.
+func Validate(settings config.LiveConfig) error {
+	if settings.NotificationURL == "" || settings.PollURL == "" {
+		return fmt.Errorf("live.notification_url and live.poll_url must both be set")
+	}
+	if u, err := url.Parse(settings.NotificationURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("live.notification_url must be an https URL, got %q", settings.NotificationURL)
+	}
+	if u, err := url.Parse(settings.PollURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("live.poll_url must be an http(s) URL, got %q", settings.PollURL)
+	}
+	return nil
+}
+
+// Listener keeps the change subscription alive and calls onChange when the
+// relay passes on a notification for it
+type Listener struct {
+	subscriber  calendar.ChangeSubscriber
+	settings    config.LiveConfig
+	clientState string
+	onChange    func()
+	onError     func(error)
+}
+
+// NewListener returns a listener for settings. The subscription's client
+// state is random, so notifications for other subscriptions sharing the
+// relay are ignored.
+func NewListener(subscriber calendar.ChangeSubscriber, settings config.LiveConfig, onChange func(), onError func(error)) (*Listener, error) {
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		return nil, fmt.Errorf("failed to generate client state: %w", err)
+	}
+	return &Listener{
+		subscriber:  subscriber,
+		settings:    settings,
+		clientState: hex.EncodeToString(state),
+		onChange:    onChange,
+		onError:     onError,
+	}, nil
+}
+
+// Run subscribes and polls the relay until ctx is done, then deletes the
+// subscription. It returns once the subscription is deleted.
+func (l *Listener) Run(ctx context.Context) {
+	subscribed := make(chan struct{})
+	go func() {
+		defer close(subscribed)
+		l.keepSubscribed(ctx)
+	}()
+	defer func() { <-subscribed }()
+
+	backoff := minBackoff
+	for ctx.Err() == nil {
+		changed, err := l.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			l.onError(err)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+		backoff = minBackoff
+		if changed {
+			l.onChange()
+		}
+	}
+}
+
+// keepSubscribed creates the subscription and renews it before it expires,
+// creating a new one when renewing fails, e.g. because Graph dropped it
+func (l *Listener) keepSubscribed(ctx context.Context) {
+	id := ""
+	backoff := minBackoff
+	for {
+		expires := time.Now().Add(subscriptionLifetime)
+		var err error
+		if id == "" {
+			id, err = l.subscriber.Subscribe(ctx, l.settings.NotificationURL, l.clientState, expires)
+		} else if err = l.subscriber.RenewSubscription(ctx, id, expires); err != nil {
+			id = ""
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wait := subscriptionLifetime - renewBefore
+		if err != nil {
+			l.onError(err)
+			wait = backoff
+			backoff = min(backoff*2, maxBackoff)
+		} else {
+			backoff = minBackoff
+		}
+		if !sleep(ctx, wait) {
+			break
+		}
+	}
+
+	if id != "" {
+		deleteCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := l.subscriber.DeleteSubscription(deleteCtx, id); err != nil {
+			l.onError(err)
+		}
+	}
+}
+
+// notifications is the body Graph posts to the relay, which passes it on
+type notifications struct {
+	Value []struct {
+		ClientState string `json:"clientState"`
+	} `json:"value"`
+}
+
+// poll waits for the relay to pass on a notification. The relay answers
+// 204 when nothing arrived before its timeout, and 200 with the body Graph
+// posted otherwise. A body that isn't Graph's counts as a change.
+func (l *Listener) poll(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.settings.PollURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create relay request: %w", err)
+	}
+	if l.settings.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+l.settings.Token)
+	}
+
+	resp, err := network.Client().Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			// The relay held the request longer than we wait; poll again
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to poll relay: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		return false, nil
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("relay %s returned %s", l.settings.PollURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxNotificationSize))
+	if err != nil {
+		return false, fmt.Errorf("failed to read relay response: %w", err)
+	}
+	var body notifications
+	if json.Unmarshal(data, &body) != nil || len(body.Value) == 0 {
+		return true, nil
+	}
+	for _, notification := range body.Value {
+		if notification.ClientState == l.clientState {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,81 @@
+package live
+
+import (
+	"calendar-widget/internal/config"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubscriber records subscription calls. Deleting is slow, like a
+// request to Graph.
+type fakeSubscriber struct {
+	mu         sync.Mutex
+	subscribed chan struct{}
+	deleted    []string
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, notificationURL, clientState string, expires time.Time) (string, error) {
+	close(f.subscribed)
+	return "subscription-1", nil
+}
+
+func (f *fakeSubscriber) RenewSubscription(ctx context.Context, id string, expires time.Time) error {
+	return nil
+}
+
+func (f *fakeSubscriber) DeleteSubscription(ctx context.Context, id string) error {
+	time.Sleep(50 * time.Millisecond)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestRunDeletesSubscriptionBeforeReturning(t *testing.T) {
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer relay.Close()
+
+	subscriber := &fakeSubscriber{subscribed: make(chan struct{})}
+	listener, err := NewListener(subscriber, config.LiveConfig{
+		NotificationURL: "https://relay.example.com/notify",
+		PollURL:         relay.URL,
+	}, func() {}, func(err error) { t.Errorf("unexpected error: %v", err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		listener.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-subscriber.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("never subscribed")
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return")
+	}
+	subscriber.mu.Lock()
+	defer subscriber.mu.Unlock()
+	if len(subscriber.deleted) != 1 || subscriber.deleted[0] != "subscription-1" {
+		t.Errorf("deleted %v when Run returned, want the subscription", subscriber.deleted)
+	}
+}
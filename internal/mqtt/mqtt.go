@@ -0,0 +1,245 @@
+// Package mqtt is a minimal MQTT 3.1.1 client. It only publishes at QoS 0,
+// which is all the daemon needs to push state to a broker.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// KeepAlive is the keep alive interval announced to the broker. Ping must
+// be called more often than this.
+const KeepAlive = 60 * time.Second
+
+// maxStringLength is the longest string MQTT can encode behind its two byte
+// length prefix
+const maxStringLength = 65535
+
+const (
+	packetConnect    = 1 << 4
+	packetConnack    = 2 << 4
+	packetPublish    = 3 << 4
+	packetPingreq    = 12 << 4
+	packetDisconnect = 14 << 4
+)
+
+// Message is a message to publish, also used for the last will
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Options describe how to connect to a broker
+type Options struct {
+	// Broker is a URL such as tcp://localhost:1883 or ssl://host:8883
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	// Will is published by the broker if the connection drops
+	Will *Message
+}
+
+// Client is a connection to a broker
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Connect opens a connection to the broker and waits for it to accept
+func Connect(ctx context.Context, opts Options) (*Client, error) {
+	connect, err := connectPacket(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(opts.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MQTT broker URL: %w", err)
+	}
+
+	var dialer net.Dialer
+	var conn net.Conn
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		conn, err = dialer.DialContext(ctx, "tcp", hostPort(u, "1883"))
+	case "ssl", "tls", "mqtts":
+		tlsDialer := tls.Dialer{NetDialer: &dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", hostPort(u, "8883"))
+	default:
+		return nil, fmt.Errorf("unsupported MQTT broker scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	return handshake(ctx, conn, connect)
+}
+
+// handshake sends the CONNECT packet on conn and waits for the broker to
+// accept it. conn is closed if it doesn't.
+func handshake(ctx context.Context, conn net.Conn, connect []byte) (*Client, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(connect); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	if err := readConnack(bufio.NewReader(conn)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	client := &Client{conn: conn}
+	go client.discardIncoming()
+	return client, nil
+}
+
+// Publish sends a message at QoS 0
+func (c *Client) Publish(msg Message) error {
+	body, err := appendString(nil, msg.Topic)
+	if err != nil {
+		return err
+	}
+	body = append(body, msg.Payload...)
+
+	header := byte(packetPublish)
+	if msg.Retain {
+		header |= 1
+	}
+	return c.write(packet(header, body))
+}
+
+// Ping keeps the connection alive
+func (c *Client) Ping() error {
+	return c.write(packet(packetPingreq, nil))
+}
+
+// Close disconnects cleanly, so the broker doesn't publish the will
+func (c *Client) Close() error {
+	c.write(packet(packetDisconnect, nil))
+	return c.conn.Close()
+}
+
+func (c *Client) write(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(data); err != nil {
+		return fmt.Errorf("failed to write to MQTT broker: %w", err)
+	}
+	return nil
+}
+
+// discardIncoming drains ping responses until the connection closes
+func (c *Client) discardIncoming() {
+	io.Copy(io.Discard, c.conn)
+}
+
+func connectPacket(opts Options) ([]byte, error) {
+	var flags byte = 0x02 // clean session
+	if opts.Will != nil {
+		flags |= 0x04
+		if opts.Will.Retain {
+			flags |= 0x20
+		}
+	}
+	if opts.Username != "" {
+		flags |= 0x80
+		if opts.Password != "" {
+			flags |= 0x40
+		}
+	}
+
+	body, _ := appendString(nil, "MQTT")
+	body = append(body, 4, flags) // protocol level 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(KeepAlive/time.Second))
+
+	fields := []string{opts.ClientID}
+	if opts.Will != nil {
+		fields = append(fields, opts.Will.Topic, string(opts.Will.Payload))
+	}
+	if opts.Username != "" {
+		fields = append(fields, opts.Username)
+		if opts.Password != "" {
+			fields = append(fields, opts.Password)
+		}
+	}
+	for _, field := range fields {
+		var err error
+		if body, err = appendString(body, field); err != nil {
+			return nil, err
+		}
+	}
+	return packet(packetConnect, body), nil
+}
+
+func readConnack(r *bufio.Reader) error {
+	header, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("failed to read MQTT connection reply: %w", err)
+	}
+	if header != packetConnack {
+		return fmt.Errorf("unexpected MQTT packet 0x%02x", header)
+	}
+
+	reply := make([]byte, 3) // length, session present, return code
+	if _, err := io.ReadFull(r, reply); err != nil {
+		return fmt.Errorf("failed to read MQTT connection reply: %w", err)
+	}
+	switch reply[2] {
+	case 0:
+		return nil
+	case 4, 5:
+		return errors.New("MQTT broker rejected the username or password")
+	default:
+		return fmt.Errorf("MQTT broker refused the connection (code %d)", reply[2])
+	}
+}
+
+// packet prefixes body with the fixed header and remaining length
+func packet(header byte, body []byte) []byte {
+	data := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		data = append(data, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(data, body...)
+}
+
+// appendString appends s with its length prefix, failing for strings too
+// long for the prefix rather than sending a truncated length
+func appendString(data []byte, s string) ([]byte, error) {
+	if len(s) > maxStringLength {
+		return nil, fmt.Errorf("MQTT string of %d bytes is longer than %d", len(s), maxStringLength)
+	}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(s)))
+	return append(data, s...), nil
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
@@ -0,0 +1,256 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnectPacket(t *testing.T) {
+	// Variable header shared by every CONNECT: protocol name, level 4 and the
+	// flags byte, followed by the 60s keep alive
+	header := func(flags byte) []byte {
+		return []byte{0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04, flags, 0x00, 0x3c}
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []byte
+	}{
+		{
+			name: "client ID only",
+			opts: Options{ClientID: "cw"},
+			want: concat([]byte{0x10, 0x0e}, header(0x02), []byte{0x00, 0x02, 'c', 'w'}),
+		},
+		{
+			name: "username without password",
+			opts: Options{ClientID: "cw", Username: "u"},
+			want: concat([]byte{0x10, 0x11}, header(0x82), []byte{0x00, 0x02, 'c', 'w', 0x00, 0x01, 'u'}),
+		},
+		{
+			name: "retained will and credentials",
+			opts: Options{ClientID: "cw", Username: "u", Password: "p", Will: &Message{Topic: "t", Payload: []byte("off"), Retain: true}},
+			want: concat([]byte{0x10, 0x1c}, header(0xe6), []byte{
+				0x00, 0x02, 'c', 'w',
+				0x00, 0x01, 't',
+				0x00, 0x03, 'o', 'f', 'f',
+				0x00, 0x01, 'u',
+				0x00, 0x01, 'p',
+			}),
+		},
+		{
+			name: "will without retain",
+			opts: Options{ClientID: "cw", Will: &Message{Topic: "t", Payload: []byte("off")}},
+			want: concat([]byte{0x10, 0x16}, header(0x06), []byte{0x00, 0x02, 'c', 'w', 0x00, 0x01, 't', 0x00, 0x03, 'o', 'f', 'f'}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := connectPacket(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got  % x\nwant % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectPacketRejectsLongStrings(t *testing.T) {
+	if _, err := connectPacket(Options{ClientID: "cw", Password: "p", Username: strings.Repeat("u", maxStringLength+1)}); err == nil {
+		t.Error("connectPacket accepted a username longer than an MQTT string")
+	}
+	if _, err := connectPacket(Options{ClientID: "cw", Will: &Message{Topic: "t", Payload: make([]byte, maxStringLength)}}); err != nil {
+		t.Errorf("connectPacket rejected a will payload of the maximum length: %v", err)
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	got, err := appendString([]byte{0xff}, "abc")
+	if err != nil || !bytes.Equal(got, []byte{0xff, 0x00, 0x03, 'a', 'b', 'c'}) {
+		t.Errorf("got % x, %v", got, err)
+	}
+
+	got, err = appendString(nil, strings.Repeat("a", maxStringLength))
+	if err != nil || got[0] != 0xff || got[1] != 0xff {
+		t.Errorf("maximum length: got prefix % x, %v", got[:2], err)
+	}
+
+	if _, err := appendString(nil, strings.Repeat("a", maxStringLength+1)); err == nil {
+		t.Error("appendString accepted a string longer than its length prefix")
+	}
+}
+
+func TestPacketRemainingLength(t *testing.T) {
+	tests := []struct {
+		length int
+		want   []byte
+	}{
+		{length: 0, want: []byte{0x00}},
+		{length: 127, want: []byte{0x7f}},
+		{length: 128, want: []byte{0x80, 0x01}},
+		{length: 16383, want: []byte{0xff, 0x7f}},
+		{length: 16384, want: []byte{0x80, 0x80, 0x01}},
+		{length: 2097152, want: []byte{0x80, 0x80, 0x80, 0x01}},
+	}
+
+	for _, tt := range tests {
+		got := packet(packetPublish, make([]byte, tt.length))
+		if got[0] != packetPublish {
+			t.Errorf("length %d: header 0x%02x, want 0x%02x", tt.length, got[0], packetPublish)
+		}
+		if prefix := got[1 : 1+len(tt.want)]; !bytes.Equal(prefix, tt.want) {
+			t.Errorf("length %d: remaining length % x, want % x", tt.length, prefix, tt.want)
+		}
+		if len(got) != 1+len(tt.want)+tt.length {
+			t.Errorf("length %d: packet is %d bytes", tt.length, len(got))
+		}
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	opts := Options{ClientID: "cw", Username: "u", Password: "p"}
+	connect, err := connectPacket(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, broker := net.Pipe()
+	defer broker.Close()
+	received := make(chan [][]byte, 1)
+	go fakeBroker(t, broker, 0, received)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := handshake(ctx, client, connect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Publish(Message{Topic: "cw/state", Payload: []byte("busy"), Retain: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	packets := <-received
+	want := [][]byte{
+		connect,
+		{0x31, 0x0e, 0x00, 0x08, 'c', 'w', '/', 's', 't', 'a', 't', 'e', 'b', 'u', 's', 'y'},
+		{0xc0, 0x00},
+		{0xe0, 0x00},
+	}
+	if len(packets) != len(want) {
+		t.Fatalf("broker got %d packets, want %d", len(packets), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(packets[i], want[i]) {
+			t.Errorf("packet %d: got % x, want % x", i, packets[i], want[i])
+		}
+	}
+}
+
+func TestHandshakeRefused(t *testing.T) {
+	tests := []struct {
+		name string
+		code byte
+		want string
+	}{
+		{name: "bad credentials", code: 5, want: "username or password"},
+		{name: "other refusal", code: 2, want: "code 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, broker := net.Pipe()
+			defer broker.Close()
+			go fakeBroker(t, broker, tt.code, nil)
+
+			connect, _ := connectPacket(Options{ClientID: "cw"})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := handshake(ctx, client, connect)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// fakeBroker answers the CONNECT on conn with a CONNACK carrying code and,
+// once the client accepted, collects the packets it sends until it
+// disconnects
+func fakeBroker(t *testing.T, conn net.Conn, code byte, received chan<- [][]byte) {
+	r := bufio.NewReader(conn)
+	connect, err := readPacket(r)
+	if err != nil {
+		t.Errorf("broker: %v", err)
+		return
+	}
+	if _, err := conn.Write([]byte{packetConnack, 0x02, 0x00, code}); err != nil {
+		t.Errorf("broker: %v", err)
+		return
+	}
+	if received == nil {
+		return
+	}
+
+	packets := [][]byte{connect}
+	for {
+		p, err := readPacket(r)
+		if err != nil {
+			break
+		}
+		packets = append(packets, p)
+		if p[0] == packetDisconnect {
+			break
+		}
+	}
+	received <- packets
+}
+
+// readPacket reads one whole packet, fixed header included
+func readPacket(r *bufio.Reader) ([]byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data := []byte{header}
+
+	length, multiplier := 0, 1
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, digit)
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return append(data, body...), nil
+}
+
+func concat(parts ...[]byte) []byte {
+	var result []byte
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
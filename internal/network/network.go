@@ -0,0 +1,76 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Options configure outbound HTTP for Microsoft Graph and sign-in
+type Options struct {
+	// Proxy is an http, https or socks5 proxy URL. Empty uses
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment.
+	Proxy string
+	// CACertPath is a PEM bundle trusted in addition to the system roots,
+	// e.g. a corporate TLS inspection CA
+	CACertPath string
+	// Insecure disables TLS certificate verification. Debugging only.
+	Insecure bool
+}
+
+var transport http.RoundTripper = http.DefaultTransport
+
+// Configure builds the shared transport from opts. It must be called before
+// any Graph or sign-in client is created.
+func Configure(opts Options) error {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CACertPath != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(opts.CACertPath)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", opts.CACertPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.Insecure {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	t.TLSClientConfig = tlsConfig
+
+	transport = t
+	return nil
+}
+
+// Transport returns the shared transport honoring the configured proxy and CAs
+func Transport() http.RoundTripper {
+	return transport
+}
+
+// Client returns an HTTP client on the shared transport
+func Client() *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Timeout:   100 * time.Second,
+	}
+}
@@ -0,0 +1,154 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+)
+
+// DefaultContent is written to new notes files when no content template is set
+const DefaultContent = "# {{.Subject}}\n\n{{.Date}} {{.Time}}{{if .Location}} · {{.Location}}{{end}}\n{{if .Attendees}}\nAttendees: {{join .Attendees \", \"}}\n{{end}}\n## Notes\n\n"
+
+// Data is what notes templates receive
+type Data struct {
+	Subject   string
+	Date      string // 2006-01-02
+	Time      string // 15:04
+	Organizer string
+	Location  string
+	Attendees []string
+	JoinLink  string
+	WebLink   string
+}
+
+// schemeRegex matches templates that produce a URL, e.g. obsidian:// or https://
+var schemeRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// unsafeFileChars are replaced in values used to build file names
+var unsafeFileChars = regexp.MustCompile(`[/\\:*?"<>|]+`)
+
+// Target renders the notes template for event. Values are escaped for URL
+// templates and made safe for file names otherwise, so "{{.Subject}}" can be
+// used directly in both.
+func Target(event calendar.Event, notesConfig config.NotesConfig) (string, error) {
+	if notesConfig.Template == "" {
+		return "", fmt.Errorf("no notes template configured, set notes.template in settings.json")
+	}
+
+	urlTemplate := isURL(notesConfig.Template)
+	escape := func(s string) string {
+		return strings.TrimSpace(unsafeFileChars.ReplaceAllString(s, "-"))
+	}
+	if urlTemplate {
+		escape = escapeURLValue
+	}
+
+	data := newData(event)
+	data.Subject = escape(data.Subject)
+	data.Organizer = escape(data.Organizer)
+	data.Location = escape(data.Location)
+
+	target, err := render(notesConfig.Template, data)
+	if err != nil {
+		return "", err
+	}
+	if !urlTemplate {
+		target = expandHome(target)
+	}
+	return target, nil
+}
+
+// Open opens the notes for event, creating the file first when the
+// template names a file that doesn't exist yet
+func Open(event calendar.Event, notesConfig config.NotesConfig, open func(string) error) error {
+	target, err := Target(event, notesConfig)
+	if err != nil {
+		return err
+	}
+
+	if !isURL(target) {
+		if err := create(target, event, notesConfig.Content); err != nil {
+			return err
+		}
+	}
+
+	return open(target)
+}
+
+// create writes a new notes file from the content template. Existing
+// notes are left alone.
+func create(path string, event calendar.Event, contentTemplate string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	if contentTemplate == "" {
+		contentTemplate = DefaultContent
+	}
+	content, err := render(contentTemplate, newData(event))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to create notes file: %w", err)
+	}
+	return nil
+}
+
+// escapeURLValue escapes s for use anywhere in a URL. Query values are the
+// common case, so "&", "=", "+" and "#" are escaped too; spaces become %20
+// rather than "+", which apps decoding the value as a path would keep.
+func escapeURLValue(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// isURL reports whether s starts with a URL scheme rather than a path
+func isURL(s string) bool {
+	return schemeRegex.MatchString(s) && !filepath.IsAbs(s)
+}
+
+func newData(event calendar.Event) Data {
+	return Data{
+		Subject:   event.Subject,
+		Date:      event.Start.Format("2006-01-02"),
+		Time:      event.Start.Format("15:04"),
+		Organizer: event.Organizer,
+		Location:  event.Location,
+		Attendees: event.Attendees,
+		JoinLink:  event.GetJoinLink(),
+		WebLink:   event.WebLink,
+	}
+}
+
+func render(text string, data Data) (string, error) {
+	tmpl, err := template.New("notes").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid notes template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render notes template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
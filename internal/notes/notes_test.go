@@ -0,0 +1,60 @@
+package notes
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTarget(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC)
+	home, _ := os.UserHomeDir()
+
+	tests := []struct {
+		name     string
+		subject  string
+		template string
+		want     string
+	}{
+		{
+			name:     "ampersand in a query",
+			subject:  "R&D sync",
+			template: "obsidian://new?vault=work&name={{.Date}}-{{.Subject}}",
+			want:     "obsidian://new?vault=work&name=2025-06-02-R%26D%20sync",
+		},
+		{
+			name:     "hash, plus and equals in a query",
+			subject:  "C# + Go = fun",
+			template: "obsidian://new?name={{.Subject}}",
+			want:     "obsidian://new?name=C%23%20%2B%20Go%20%3D%20fun",
+		},
+		{
+			name:     "slash and question mark in a path",
+			subject:  "Q3/Q4 review?",
+			template: "https://notes.example.com/{{.Subject}}",
+			want:     "https://notes.example.com/Q3%2FQ4%20review%3F",
+		},
+		{
+			name:     "file name",
+			subject:  "R&D: Q3/Q4 + C#",
+			template: "~/notes/{{.Date}}-{{.Subject}}.md",
+			want:     filepath.Join(home, "notes", "2025-06-02-R&D- Q3-Q4 + C#.md"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := calendar.Event{Subject: tt.subject, Start: start}
+			got, err := Target(event, config.NotesConfig{Template: tt.template})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Target() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
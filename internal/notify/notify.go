@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const appName = "calendar-widget"
+
+// Send shows a desktop notification using notify-send
+func Send(summary, body string) error {
+	cmd := exec.Command("notify-send", "--app-name="+appName, summary, body)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	return nil
+}
+
+// Urgency levels understood by notification daemons
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// SendUrgent shows a notification with the given urgency. Silent asks the
+// notification daemon not to play its own sound.
+func SendUrgent(summary, body, urgency string, silent bool) error {
+	args := []string{"--app-name=" + appName, "--urgency=" + urgency}
+	if silent {
+		args = append(args, "--hint=boolean:suppress-sound:true")
+	}
+	cmd := exec.Command("notify-send", append(args, summary, body)...)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	return nil
+}
+
+// SendWithAction shows a notification with a single action button and waits
+// until it is clicked, dismissed or timeout passes. It reports whether the
+// action was invoked.
+func SendWithAction(ctx context.Context, summary, body, action string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "notify-send",
+		"--app-name="+appName,
+		fmt.Sprintf("--expire-time=%d", timeout.Milliseconds()),
+		"--action=action="+action,
+		summary, body)
+
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		// Timed out without the user acting on it
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)) == "action", nil
+}
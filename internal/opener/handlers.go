@@ -0,0 +1,87 @@
+package opener
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// launchGrace is how long a handler gets to fail before it is assumed to
+// have opened the link. Handlers that hand off to a running browser exit
+// well within it; some stay running until the program they started exits.
+const launchGrace = 2 * time.Second
+
+// handlers build the command lines tried in order by openWithHandlers. A
+// handler returns nil when it can't be used for a link.
+var handlers = []func(link string) []string{
+	func(link string) []string { return []string{"xdg-open", link} },
+	func(link string) []string { return []string{"gio", "open", link} },
+	func(link string) []string { return []string{"handlr", "open", link} },
+	gtkLaunch,
+}
+
+// openWithHandlers tries each installed handler until one opens link
+func openWithHandlers(link string) error {
+	var errs []error
+	for _, handler := range handlers {
+		args := handler(link)
+		if args == nil {
+			continue
+		}
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		if err := launch(args); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return nil
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("no link handler installed (install xdg-utils, glib2 or handlr, or set openers in settings.json)")
+	}
+	return errors.Join(errs...)
+}
+
+// gtkLaunch starts the desktop's default application for the link's URL
+// scheme directly, which works without a portal or xdg-open
+func gtkLaunch(link string) []string {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme == "" || u.Scheme == "file" {
+		return nil
+	}
+	out, err := exec.Command("xdg-mime", "query", "default", "x-scheme-handler/"+u.Scheme).Output()
+	if err != nil {
+		return nil
+	}
+	app := strings.TrimSpace(string(out))
+	if app == "" {
+		return nil
+	}
+	return []string{"gtk-launch", app, link}
+}
+
+// launch starts args and waits up to launchGrace for it to fail
+func launch(args []string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", args[0], err)
+		}
+		return nil
+	case <-time.After(launchGrace):
+		return nil
+	}
+}
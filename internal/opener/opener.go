@@ -0,0 +1,165 @@
+// Package opener opens links with a command configured per meeting
+// provider, falling back to the desktop's default handler.
+package opener
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/notify"
+)
+
+// Link kinds a command can be configured for
+const (
+	Teams   = "teams"
+	Zoom    = "zoom"
+	Outlook = "outlook"
+	// Web is every other http(s) link
+	Web = "web"
+)
+
+// Kinds lists every link kind, for validating settings
+var Kinds = []string{Teams, Zoom, Outlook, Web}
+
+// Data is what command templates receive
+type Data struct {
+	URL  string
+	Kind string
+}
+
+// commands holds the parsed template of each argument, by link kind
+var commands = map[string][]*template.Template{}
+
+// SetCommands configures the command template used for each link kind, e.g.
+// {"teams": "teams-for-linux --url {{.URL}}", "web": "chromium --app={{.URL}}"}.
+// Each whitespace-separated argument is rendered on its own and the command
+// runs without a shell, so links can't inject arguments or commands.
+func SetCommands(templates map[string]string) error {
+	parsed := map[string][]*template.Template{}
+	for kind, command := range templates {
+		if !slices.Contains(Kinds, kind) {
+			return fmt.Errorf("unknown opener %q, expected one of %s", kind, strings.Join(Kinds, ", "))
+		}
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields {
+			tmpl, err := template.New(kind).Parse(field)
+			if err != nil {
+				return fmt.Errorf("invalid %s opener: %w", kind, err)
+			}
+			// Catch misspelled fields now rather than on the first click
+			if err := tmpl.Execute(&bytes.Buffer{}, Data{URL: "https://example.com", Kind: kind}); err != nil {
+				return fmt.Errorf("invalid %s opener: %w", kind, err)
+			}
+			parsed[kind] = append(parsed[kind], tmpl)
+		}
+	}
+	commands = parsed
+	return nil
+}
+
+// KindOf classifies a link by its host. Links that aren't http(s), such as
+// tel: URIs and files, return "".
+func KindOf(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "teams.microsoft.com" || host == "teams.live.com" || strings.HasSuffix(host, ".teams.microsoft.com"):
+		return Teams
+	case host == "zoom.us" || strings.HasSuffix(host, ".zoom.us"):
+		return Zoom
+	case strings.HasPrefix(host, "outlook.") && (strings.HasSuffix(host, ".office.com") || strings.HasSuffix(host, ".live.com") || strings.HasSuffix(host, ".office365.com")):
+		return Outlook
+	default:
+		return Web
+	}
+}
+
+// Command returns the configured command line for link, or nil when there is
+// none for its kind
+func Command(link string) ([]string, error) {
+	kind := KindOf(link)
+	templates, ok := commands[kind]
+	if !ok {
+		return nil, nil
+	}
+
+	data := Data{URL: link, Kind: kind}
+	args := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s opener: %w", kind, err)
+		}
+		args = append(args, buf.String())
+	}
+	return args, nil
+}
+
+// Open opens link with its configured command. Without one, or when it
+// fails, Teams links open in the Teams app when it is installed and
+// everything else goes to the desktop's default handler. When nothing can
+// open the link a desktop notification says why, since clicks on the bar
+// have nowhere else to report errors.
+func Open(link string) error {
+	err := open(link)
+	if err != nil {
+		// Best effort: without a notification daemon the error is all we have
+		_ = notify.Send(i18n.T("Could not open link"), err.Error())
+	}
+	return err
+}
+
+func open(link string) error {
+	var errs []error
+
+	args, err := Command(link)
+	if err == nil && args != nil {
+		err = launch(args)
+		if err == nil {
+			return nil
+		}
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if KindOf(link) == Teams {
+		if err := openTeamsApp(link); err == nil {
+			return nil
+		}
+	}
+
+	if err := OpenDefault(link); err != nil {
+		errs = append(errs, err)
+		return fmt.Errorf("failed to open %s: %w", link, errors.Join(errs...))
+	}
+	return nil
+}
+
+// OpenDefault opens a URL or file with the desktop's default handler. On
+// Linux and the BSDs it tries xdg-open, gio, handlr and gtk-launch in turn,
+// since xdg-open alone fails in minimal Wayland sessions.
+func OpenDefault(link string) error {
+	switch runtime.GOOS {
+	case "windows":
+		return launch([]string{"rundll32", "url.dll,FileProtocolHandler", link})
+	case "darwin":
+		return launch([]string{"open", link})
+	default:
+		return openWithHandlers(link)
+	}
+}
@@ -0,0 +1,62 @@
+package opener
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// TeamsDeepLink converts a Teams join link such as
+// https://teams.microsoft.com/l/meetup-join/... into the msteams: link the
+// desktop app handles. ok is false for links that aren't Teams links.
+func TeamsDeepLink(link string) (deepLink string, ok bool) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host != "teams.microsoft.com" && !strings.HasSuffix(host, ".teams.microsoft.com") {
+		return "", false
+	}
+	if !strings.HasPrefix(u.EscapedPath(), "/l/") {
+		return "", false
+	}
+
+	deepLink = "msteams:" + u.EscapedPath()
+	if u.RawQuery != "" {
+		deepLink += "?" + u.RawQuery
+	}
+	return deepLink, true
+}
+
+// TeamsInstalled reports whether a desktop app handles msteams: links
+func TeamsInstalled() bool {
+	switch runtime.GOOS {
+	case "linux":
+		out, err := exec.Command("xdg-mime", "query", "default", "x-scheme-handler/msteams").Output()
+		return err == nil && strings.TrimSpace(string(out)) != ""
+	case "darwin":
+		for _, app := range []string{"/Applications/Microsoft Teams.app", "/Applications/Microsoft Teams (work or school).app"} {
+			if _, err := os.Stat(app); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openTeamsApp opens a Teams link in the desktop app, failing when the app
+// isn't installed so the caller can fall back to the browser
+func openTeamsApp(link string) error {
+	deepLink, ok := TeamsDeepLink(link)
+	if !ok {
+		return fmt.Errorf("%s is not a Teams join link", link)
+	}
+	if !TeamsInstalled() {
+		return fmt.Errorf("no app handles msteams: links")
+	}
+	return OpenDefault(deepLink)
+}
@@ -0,0 +1,169 @@
+// Package push sends meeting reminders to a phone through an ntfy or Gotify
+// server, for when the desktop notification goes unseen.
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/network"
+)
+
+// Push services
+const (
+	ServiceNtfy   = "ntfy"
+	ServiceGotify = "gotify"
+)
+
+// defaultNtfyServer is used when push.server is empty
+const defaultNtfyServer = "https://ntfy.sh"
+
+// Message is a notification to push
+type Message struct {
+	Title string
+	Body  string
+	// Link is opened when the notification is tapped, e.g. the join link
+	Link string
+	// Urgent raises the priority so the phone sounds even in quieter modes
+	Urgent bool
+}
+
+// NewMessage describes event for a push. Private meetings are pushed as
+// "Busy" without location or link, since the push server sees the message.
+func NewMessage(event calendar.Event, body string, urgent bool) Message {
+	if event.IsPrivate() {
+		return Message{Title: i18n.T("Busy"), Body: body, Urgent: urgent}
+	}
+	if event.Location != "" {
+		body += "\n" + event.Location
+	}
+	return Message{Title: event.Subject, Body: body, Link: event.GetJoinLink(), Urgent: urgent}
+}
+
+// Enabled reports whether a push service is configured
+func Enabled(settings config.PushConfig) bool {
+	return settings.Service != ""
+}
+
+// Validate checks that the configured service has what it needs
+func Validate(settings config.PushConfig) error {
+	switch settings.Service {
+	case "":
+		return nil
+	case ServiceNtfy:
+		if settings.Topic == "" {
+			return fmt.Errorf("push.topic is required for ntfy")
+		}
+	case ServiceGotify:
+		if settings.Server == "" || settings.Token == "" {
+			return fmt.Errorf("push.server and push.token are required for gotify")
+		}
+	default:
+		return fmt.Errorf("unknown push service %q, expected ntfy or gotify", settings.Service)
+	}
+	return nil
+}
+
+// Send pushes message through the configured service
+func Send(ctx context.Context, settings config.PushConfig, message Message) error {
+	if err := Validate(settings); err != nil {
+		return err
+	}
+
+	switch settings.Service {
+	case ServiceNtfy:
+		return sendNtfy(ctx, settings, message)
+	case ServiceGotify:
+		return sendGotify(ctx, settings, message)
+	}
+	return nil
+}
+
+// sendNtfy publishes as JSON to the server root, which keeps non-ASCII
+// titles intact (ntfy's header-based publishing doesn't)
+func sendNtfy(ctx context.Context, settings config.PushConfig, message Message) error {
+	server := settings.Server
+	if server == "" {
+		server = defaultNtfyServer
+	}
+
+	priority := 3
+	if message.Urgent {
+		priority = 5
+	}
+	body := map[string]any{
+		"topic":    settings.Topic,
+		"title":    message.Title,
+		"message":  message.Body,
+		"priority": priority,
+		"tags":     []string{"calendar"},
+	}
+	if message.Link != "" {
+		body["click"] = message.Link
+	}
+
+	return post(ctx, strings.TrimRight(server, "/"), body, func(req *http.Request) {
+		if settings.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+settings.Token)
+		}
+	})
+}
+
+// sendGotify posts to the application message endpoint. Gotify's Android
+// app makes a sound from priority 8 on.
+func sendGotify(ctx context.Context, settings config.PushConfig, message Message) error {
+	priority := 5
+	if message.Urgent {
+		priority = 8
+	}
+	body := map[string]any{
+		"title":    message.Title,
+		"message":  message.Body,
+		"priority": priority,
+	}
+	if message.Link != "" {
+		body["extras"] = map[string]any{
+			"client::notification": map[string]any{
+				"click": map[string]string{"url": message.Link},
+			},
+		}
+	}
+
+	return post(ctx, strings.TrimRight(settings.Server, "/")+"/message", body, func(req *http.Request) {
+		req.Header.Set("X-Gotify-Key", settings.Token)
+	})
+}
+
+func post(ctx context.Context, url string, body any, authorize func(req *http.Request)) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	authorize(req)
+
+	resp, err := network.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push notification: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("push server %s returned %s", url, resp.Status)
+	}
+	return nil
+}
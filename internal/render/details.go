@@ -0,0 +1,69 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+)
+
+// ResponseIcons abbreviate a response status in tooltip details
+var ResponseIcons = map[string]string{
+	"accepted":            "✓",
+	"tentativelyAccepted": "?",
+	"declined":            "✗",
+	"notResponded":        "!",
+}
+
+// AttachmentIndicator follows the title of meetings with attachments or
+// links to shared documents
+const AttachmentIndicator = "📎"
+
+// UpdatedBadge follows the title of meetings that were moved recently
+func UpdatedBadge() string {
+	return "✎ " + i18n.T("updated")
+}
+
+var showAttendees bool
+
+// SetShowAttendees toggles the attendee count, organizer and response
+// status details after each tooltip title
+func SetShowAttendees(enabled bool) {
+	showAttendees = enabled
+}
+
+// Details returns the raw "(8 ppl, J. Smith, ✓)" suffix for an event,
+// or "" when details are disabled or there is nothing to show
+func Details(event calendar.Event) string {
+	if !showAttendees {
+		return ""
+	}
+
+	var parts []string
+	if len(event.Attendees) > 0 {
+		parts = append(parts, i18n.T("%d ppl", len(event.Attendees)))
+	}
+	if event.Organizer != "" && event.ResponseStatus != "organizer" {
+		parts = append(parts, shortName(event.Organizer))
+	}
+	if icon, ok := ResponseIcons[event.ResponseStatus]; ok {
+		parts = append(parts, icon)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+}
+
+// shortName abbreviates "John Smith" to "J. Smith". Single names and
+// addresses are returned as is.
+func shortName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 || strings.Contains(name, "@") {
+		return name
+	}
+	first := []rune(fields[0])
+	return string(first[0]) + ". " + fields[len(fields)-1]
+}
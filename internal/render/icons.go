@@ -0,0 +1,42 @@
+package render
+
+import (
+	"calendar-widget/internal/calendar"
+)
+
+// defaultIcons are the status indicators used when settings don't override them
+var defaultIcons = map[string]string{
+	calendar.StatusCurrent:  "🟢",
+	calendar.StatusUrgent:   "🔴",
+	calendar.StatusSoon:     "🟡",
+	calendar.StatusUpcoming: "🔵",
+	calendar.StatusPast:     "⚫",
+	"event":                 "📅",
+}
+
+var icons = defaultIcons
+
+// SetIcons overrides status indicators, e.g. with Nerd Font glyphs or plain
+// ASCII. Statuses missing from overrides keep their default icon.
+func SetIcons(overrides map[string]string) {
+	icons = make(map[string]string, len(defaultIcons))
+	for status, icon := range defaultIcons {
+		icons[status] = icon
+	}
+	for status, icon := range overrides {
+		icons[status] = icon
+	}
+}
+
+// StatusIcon returns the indicator for an event status
+func StatusIcon(status string) string {
+	if icon, ok := icons[status]; ok {
+		return icon
+	}
+	return icons["event"]
+}
+
+// Icon returns the indicator for an event status, colored for the target
+func (t Target) Icon(status string) string {
+	return t.Status(status, StatusIcon(status))
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+)
+
+// TimeRange renders when an event runs, e.g. "09:30-10:00"
+func TimeRange(event calendar.Event) string {
+	return fmt.Sprintf("%s-%s", i18n.FormatTime(event.Start), i18n.FormatTime(event.End))
+}
+
+// StartDay renders when an event starts relative to today: "15:04",
+// "Tomorrow 15:04" or "Mon 24/9 15:04"
+func StartDay(event calendar.Event) string {
+	now := calendar.Now()
+	switch event.Start.Format("2006-01-02") {
+	case now.Format("2006-01-02"):
+		return i18n.FormatTime(event.Start)
+	case now.AddDate(0, 0, 1).Format("2006-01-02"):
+		return i18n.T("Tomorrow") + " " + i18n.FormatTime(event.Start)
+	default:
+		return i18n.FormatDate(event.Start) + " " + i18n.FormatTime(event.Start)
+	}
+}
+
+// EventLine renders one event of a schedule: its status icon, when, the
+// subject and what else is known about it, e.g.
+// "🟢 09:30-10:00 Daily Standup 📎 (Teams)"
+func EventLine(t Target, event calendar.Event, when string) string {
+	title := t.Category(event.Color, t.Bold(t.Cancelled(&event, t.Escape(event.Subject))))
+	if details := Details(event); details != "" {
+		title = title + " " + t.Dim(t.Escape(details))
+	}
+	if event.HasDocuments() {
+		title = title + " " + AttachmentIndicator
+	}
+	if event.Change != nil {
+		title = title + " " + t.Status(calendar.StatusSoon, t.Escape(UpdatedBadge()))
+	}
+	if event.IsTeams {
+		title = title + " (Teams)"
+	}
+
+	if event.Location != "" && !event.IsTeams {
+		title = title + " @ " + t.Escape(event.Location)
+	}
+	if event.TravelTime() > 0 && event.Start.After(calendar.Now()) {
+		title = title + " " + t.Dim("("+t.Escape(i18n.T("leave by %s", i18n.FormatTime(event.LeaveBy())))+")")
+	}
+
+	return fmt.Sprintf("%s %s %s", t.Icon(event.GetStatus()), t.Time(t.Escape(when)), title)
+}
+
+// ConflictMarker goes between double-booked rows in schedules
+func ConflictMarker(t Target) string {
+	return t.Status(calendar.StatusUrgent, "⚠ "+t.Escape(i18n.T("Conflict")))
+}
+
+// Schedule renders a day's events one EventLine each, with a conflict
+// marker before each event that overlaps the one before it
+func Schedule(t Target, events []calendar.Event) []string {
+	if len(events) == 0 {
+		return []string{t.Escape(i18n.T("No meetings today"))}
+	}
+
+	var lines []string
+	conflicts := calendar.ConflictsWithPrevious(events)
+	for i, event := range events {
+		if conflicts[i] {
+			lines = append(lines, ConflictMarker(t))
+		}
+		lines = append(lines, EventLine(t, event, TimeRange(event)))
+	}
+	return lines
+}
@@ -0,0 +1,234 @@
+// Package render formats events for the places the widget shows them: the
+// terminal, waybar's Pango markup, multiplexer status lines and plain text. Each output target styles
+// the same building blocks its own way, so a line of the schedule only has
+// to be put together once.
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Target is an output format
+type Target int
+
+const (
+	// Plain is unstyled text, e.g. for notifications
+	Plain Target = iota
+	// Pango is waybar's markup. Text is always escaped and cancelled
+	// meetings struck through; the other styles need SetPango.
+	Pango
+	// ANSI is the terminal, styled by the current theme
+	ANSI
+	// Tmux is tmux's #[...] style markup for status lines
+	Tmux
+	// TrueColor is ANSI escape codes with the fixed status colors, for
+	// status bars that pass them through, e.g. zellij's zjstatus. Unlike
+	// ANSI it doesn't depend on the output being a terminal.
+	TrueColor
+)
+
+// statusColors are the foreground colors used for status icons in Pango mode
+var statusColors = map[string]string{
+	calendar.StatusCurrent:  "#a6e3a1",
+	calendar.StatusUrgent:   "#f38ba8",
+	calendar.StatusSoon:     "#f9e2af",
+	calendar.StatusUpcoming: "#89b4fa",
+	calendar.StatusPast:     "#6c7086",
+}
+
+// categoryColors approximate Outlook's category palette for tooltip titles
+var categoryColors = map[string]string{
+	"red":           "#e74856",
+	"orange":        "#ff8c00",
+	"brown":         "#ab620d",
+	"yellow":        "#fff100",
+	"green":         "#47d041",
+	"teal":          "#30c6cc",
+	"olive":         "#73aa24",
+	"blue":          "#00bcf2",
+	"purple":        "#8764b8",
+	"cranberry":     "#f495bf",
+	"steel":         "#a0aeb2",
+	"darksteel":     "#4f6bed",
+	"gray":          "#b1adab",
+	"darkgray":      "#5d5a58",
+	"black":         "#000000",
+	"darkred":       "#a4262c",
+	"darkorange":    "#ca5010",
+	"darkbrown":     "#8e562e",
+	"darkyellow":    "#c19c00",
+	"darkgreen":     "#107c10",
+	"darkteal":      "#038387",
+	"darkolive":     "#498205",
+	"darkblue":      "#0078d4",
+	"darkpurple":    "#5c2e91",
+	"darkcranberry": "#c239b3",
+}
+
+// StatusColor returns the "#rrggbb" color of an event status, e.g. for
+// drawing an icon in it
+func StatusColor(status string) (string, bool) {
+	color, ok := statusColors[status]
+	return color, ok
+}
+
+var pangoEnabled bool
+
+// SetPango toggles rich Pango markup (bold subjects, dim times, colored
+// status icons) in waybar text and tooltips
+func SetPango(enabled bool) {
+	pangoEnabled = enabled
+}
+
+// Styles are the terminal styles of the ANSI target
+type Styles struct {
+	Bold lipgloss.Style
+	// Time styles the time column of schedule lines
+	Time lipgloss.Style
+	Dim  lipgloss.Style
+	// Status colors text by event status
+	Status map[string]lipgloss.Style
+}
+
+var styles = Styles{
+	Bold: lipgloss.NewStyle().Bold(true),
+	Time: lipgloss.NewStyle().MarginRight(1),
+	Dim:  lipgloss.NewStyle().Faint(true),
+}
+
+// SetStyles sets the ANSI target's styles, usually from the current theme
+func SetStyles(s Styles) {
+	styles = s
+}
+
+var pangoEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+)
+
+// tmuxEscaper doubles "#", which starts formats and styles in tmux
+var tmuxEscaper = strings.NewReplacer("#", "##")
+
+// Escape makes calendar text safe to embed in the target's markup.
+// Waybar parses text and tooltips as markup in every mode, so all
+// user-controlled strings must pass through here before being wrapped.
+func (t Target) Escape(s string) string {
+	switch t {
+	case Pango:
+		return pangoEscaper.Replace(s)
+	case Tmux:
+		return tmuxEscaper.Replace(s)
+	}
+	return s
+}
+
+// Bold emphasizes already escaped text
+func (t Target) Bold(s string) string {
+	switch {
+	case t == ANSI:
+		return styles.Bold.Render(s)
+	case t == Pango && pangoEnabled:
+		return "<b>" + s + "</b>"
+	case t == Tmux:
+		return "#[bold]" + s + "#[nobold]"
+	case t == TrueColor:
+		return "\x1b[1m" + s + "\x1b[22m"
+	}
+	return s
+}
+
+// Dim fades already escaped text
+func (t Target) Dim(s string) string {
+	switch {
+	case t == ANSI:
+		return styles.Dim.Render(s)
+	case t == Pango && pangoEnabled:
+		return "<span alpha='60%'>" + s + "</span>"
+	case t == Tmux:
+		return "#[dim]" + s + "#[nodim]"
+	case t == TrueColor:
+		return "\x1b[2m" + s + "\x1b[22m"
+	}
+	return s
+}
+
+// Time styles the time column of a schedule line
+func (t Target) Time(s string) string {
+	if t == ANSI {
+		return styles.Time.Render(s)
+	}
+	return t.Dim(s)
+}
+
+// Cancelled strikes already escaped text through for cancelled meetings.
+// Waybar parses markup in every mode, so this doesn't wait for SetPango.
+func (t Target) Cancelled(event *calendar.Event, s string) string {
+	if !event.IsCancelled {
+		return s
+	}
+	switch t {
+	case ANSI:
+		return lipgloss.NewStyle().Strikethrough(true).Render(s)
+	case Pango:
+		return "<s>" + s + "</s>"
+	case Tmux:
+		return "#[strikethrough]" + s + "#[nostrikethrough]"
+	case TrueColor:
+		return "\x1b[9m" + s + "\x1b[29m"
+	}
+	return s
+}
+
+// Status colors already escaped text by event status
+func (t Target) Status(status, s string) string {
+	switch {
+	case t == ANSI:
+		if style, ok := styles.Status[status]; ok {
+			return style.Render(s)
+		}
+	case t == Pango && pangoEnabled:
+		if color, ok := statusColors[status]; ok {
+			return fmt.Sprintf("<span color='%s'>%s</span>", color, s)
+		}
+	case t == Tmux || t == TrueColor:
+		if color, ok := statusColors[status]; ok {
+			return t.color(color, s)
+		}
+	}
+	return s
+}
+
+// Category colors already escaped text with an Outlook category color
+func (t Target) Category(color, s string) string {
+	hex, ok := categoryColors[color]
+	switch {
+	case !ok:
+		return s
+	case t == ANSI:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(hex)).Render(s)
+	case t == Pango && pangoEnabled:
+		return fmt.Sprintf("<span color='%s'>%s</span>", hex, s)
+	case t == Tmux || t == TrueColor:
+		return t.color(hex, s)
+	}
+	return s
+}
+
+// color sets the foreground of s to a "#rrggbb" color in the Tmux and
+// TrueColor targets
+func (t Target) color(hex, s string) string {
+	if t == Tmux {
+		return "#[fg=" + hex + "]" + s + "#[fg=default]"
+	}
+	var r, g, b uint8
+	fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[39m", r, g, b, s)
+}
@@ -0,0 +1,172 @@
+package screenshare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"calendar-widget/internal/xdg"
+)
+
+// staleAfter is how long a state written by the daemon is trusted. A daemon
+// that stopped updating it must not leave privacy mode on.
+const staleAfter = time.Minute
+
+// State is the last screen sharing check written by the daemon
+type State struct {
+	Sharing   bool      `json:"sharing"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+func GetStatePath() string {
+	return xdg.StatePath("screenshare.json")
+}
+
+func Load() (*State, error) {
+	data, err := os.ReadFile(GetStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Daemon isn't watching
+		}
+		return nil, fmt.Errorf("failed to read screen sharing state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse screen sharing state: %w", err)
+	}
+
+	return &state, nil
+}
+
+func Save(state *State) error {
+	statePath := GetStatePath()
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal screen sharing state: %w", err)
+	}
+
+	return os.WriteFile(statePath, data, 0600)
+}
+
+func Clear() error {
+	if err := os.Remove(GetStatePath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove screen sharing state: %w", err)
+	}
+	return nil
+}
+
+// Active reports whether the daemon recently saw the screen being shared
+func Active() bool {
+	state, err := Load()
+	if err != nil || state == nil {
+		return false
+	}
+	return state.Sharing && time.Since(state.CheckedAt) < staleAfter
+}
+
+// Detect reports whether the screen is being shared or cast. A configured
+// command is run through sh and sharing means it exited with status 0;
+// otherwise PipeWire is asked for active screencast streams.
+func Detect(ctx context.Context, command string) (bool, error) {
+	if command == "" {
+		return detectPipeWire(ctx)
+	}
+
+	err := exec.CommandContext(ctx, "sh", "-c", command).Run()
+	if err == nil {
+		return true, nil
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to run screen sharing command: %w", err)
+}
+
+// pipeWireObject is the part of a pw-dump entry needed to find screencasts
+type pipeWireObject struct {
+	Type string `json:"type"`
+	Info struct {
+		State string         `json:"state"`
+		Props map[string]any `json:"props"`
+	} `json:"info"`
+}
+
+// detectPipeWire looks for running video sources that aren't camera
+// devices. xdg-desktop-portal backends (wlr, hyprland, gnome, kde) publish
+// screencasts as such nodes only while a share is active.
+func detectPipeWire(ctx context.Context) (bool, error) {
+	out, err := exec.CommandContext(ctx, "pw-dump").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to run pw-dump: %w", err)
+	}
+
+	var objects []pipeWireObject
+	if err := json.Unmarshal(out, &objects); err != nil {
+		return false, fmt.Errorf("failed to parse pw-dump output: %w", err)
+	}
+
+	for _, object := range objects {
+		if object.Type != "PipeWire:Interface:Node" || object.Info.State != "running" {
+			continue
+		}
+		if object.Info.Props["media.class"] != "Video/Source" {
+			continue
+		}
+		// Cameras are backed by a device; screencast streams are not
+		if _, isDevice := object.Info.Props["device.id"]; isDevice {
+			continue
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Watch checks for screen sharing every interval until ctx is done, keeping
+// the state file current and calling onChange when sharing starts or stops.
+// The state is cleared on return.
+func Watch(ctx context.Context, interval time.Duration, command string, onChange func(sharing bool)) {
+	defer Clear()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var sharing, reportedError bool
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		now, err := Detect(checkCtx, command)
+		cancel()
+
+		if err != nil {
+			// Keep the last known state, but only complain once
+			if !reportedError {
+				fmt.Fprintf(os.Stderr, "Screen sharing detection failed: %v\n", err)
+				reportedError = true
+			}
+			now = sharing
+		}
+
+		if err := Save(&State{Sharing: now, CheckedAt: time.Now()}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if now != sharing {
+			sharing = now
+			onChange(sharing)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package selection decides which event the bar shows and clicks act on.
+// The bar, the countdown, the click handler, the TUI and every provider's
+// GetNextMeeting share it so they always agree on "the" meeting.
+package selection
+
+import "time"
+
+// Options controls which events may be selected. The zero value only
+// considers blocking meetings the user hasn't declined.
+type Options struct {
+	// IncludeNonBlocking falls back to all-day, long and free events when no
+	// blocking meeting has the same status
+	IncludeNonBlocking bool
+	// IncludeDeclined also considers meetings the user has declined
+	IncludeDeclined bool
+}
+
+var (
+	// Display is used for the bar, the countdown and clicks, so a click
+	// opens the meeting the bar shows
+	Display = Options{IncludeNonBlocking: true}
+	// Meetings is used where only a real meeting makes sense, e.g. the TUI's
+	// join, copy and notes keys
+	Meetings = Options{}
+)
+
+// Candidate is what selection needs to know about an event
+type Candidate struct {
+	// Status is the event's status: current, urgent, soon, upcoming or past
+	Status   string
+	Start    time.Time
+	Blocking bool
+	Declined bool
+}
+
+// statusPriority is the order in which statuses are tried
+var statusPriority = []string{"current", "urgent", "soon", "upcoming"}
+
+// Best returns the index of the candidate to select, or -1 if none
+// qualifies. A running meeting wins over one that is about to start, and
+// within each status blocking meetings win over the rest. Ties go to the
+// earlier index, so candidates should be sorted by start time.
+func Best(candidates []Candidate, now time.Time, opts Options) int {
+	for _, targetStatus := range statusPriority {
+		// First pass: blocking events with this status
+		if i := first(candidates, now, targetStatus, opts, true); i >= 0 {
+			return i
+		}
+
+		// Second pass: any event with this status (all-day, long or free)
+		if opts.IncludeNonBlocking {
+			if i := first(candidates, now, targetStatus, opts, false); i >= 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func first(candidates []Candidate, now time.Time, targetStatus string, opts Options, blockingOnly bool) int {
+	for i, candidate := range candidates {
+		if candidate.Status != targetStatus {
+			continue
+		}
+		if blockingOnly && !candidate.Blocking {
+			continue
+		}
+		if candidate.Declined && !opts.IncludeDeclined {
+			continue
+		}
+		if targetStatus == "upcoming" && !candidate.Start.After(now) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
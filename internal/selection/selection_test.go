@@ -0,0 +1,137 @@
+package selection
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBest(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	in := func(minutes int) time.Time {
+		return now.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	tests := []struct {
+		name       string
+		candidates []Candidate
+		opts       Options
+		want       int
+	}{
+		{
+			name:       "none",
+			candidates: nil,
+			opts:       Display,
+			want:       -1,
+		},
+		{
+			name: "current wins over urgent",
+			candidates: []Candidate{
+				{Status: "urgent", Start: in(3), Blocking: true},
+				{Status: "current", Start: in(-10), Blocking: true},
+			},
+			want: 1,
+		},
+		{
+			name: "urgent wins over soon and upcoming",
+			candidates: []Candidate{
+				{Status: "upcoming", Start: in(60), Blocking: true},
+				{Status: "soon", Start: in(10), Blocking: true},
+				{Status: "urgent", Start: in(4), Blocking: true},
+			},
+			want: 2,
+		},
+		{
+			name: "ties go to the earlier index",
+			candidates: []Candidate{
+				{Status: "soon", Start: in(10), Blocking: true},
+				{Status: "soon", Start: in(10), Blocking: true},
+			},
+			want: 0,
+		},
+		{
+			name: "blocking wins within a status",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-60)},
+				{Status: "current", Start: in(-10), Blocking: true},
+			},
+			opts: Display,
+			want: 1,
+		},
+		{
+			name: "non-blocking current wins over blocking upcoming with the fallback",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-60)},
+				{Status: "upcoming", Start: in(60), Blocking: true},
+			},
+			opts: Display,
+			want: 0,
+		},
+		{
+			name: "non-blocking is skipped without the fallback",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-60)},
+				{Status: "upcoming", Start: in(60), Blocking: true},
+			},
+			opts: Meetings,
+			want: 1,
+		},
+		{
+			name: "only non-blocking without the fallback",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-60)},
+			},
+			opts: Meetings,
+			want: -1,
+		},
+		{
+			name: "declined is skipped",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-10), Blocking: true, Declined: true},
+				{Status: "soon", Start: in(10), Blocking: true},
+			},
+			opts: Display,
+			want: 1,
+		},
+		{
+			name: "declined is skipped by the fallback too",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-10), Declined: true},
+			},
+			opts: Display,
+			want: -1,
+		},
+		{
+			name: "declined is selected when included",
+			candidates: []Candidate{
+				{Status: "current", Start: in(-10), Blocking: true, Declined: true},
+				{Status: "soon", Start: in(10), Blocking: true},
+			},
+			opts: Options{IncludeDeclined: true},
+			want: 0,
+		},
+		{
+			name: "past is never selected",
+			candidates: []Candidate{
+				{Status: "past", Start: in(-60), Blocking: true},
+			},
+			opts: Display,
+			want: -1,
+		},
+		{
+			name: "upcoming must start after now",
+			candidates: []Candidate{
+				{Status: "upcoming", Start: now, Blocking: true},
+				{Status: "upcoming", Start: in(60), Blocking: true},
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Best(tt.candidates, now, tt.opts); got != tt.want {
+				t.Errorf("Best() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
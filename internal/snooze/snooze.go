@@ -0,0 +1,90 @@
+package snooze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"calendar-widget/internal/xdg"
+)
+
+// State is the persisted do-not-disturb window
+type State struct {
+	Until time.Time `json:"until"`
+}
+
+func GetSnoozePath() string {
+	return xdg.StatePath("snooze.json")
+}
+
+func Load() (*State, error) {
+	data, err := os.ReadFile(GetSnoozePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Not snoozed
+		}
+		return nil, fmt.Errorf("failed to read snooze state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse snooze state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Set snoozes urgent styling and notifications until the given time
+func Set(until time.Time) error {
+	snoozePath := GetSnoozePath()
+	if err := os.MkdirAll(filepath.Dir(snoozePath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(&State{Until: until}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snooze state: %w", err)
+	}
+
+	return os.WriteFile(snoozePath, data, 0600)
+}
+
+func Clear() error {
+	if err := os.Remove(GetSnoozePath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snooze state: %w", err)
+	}
+	return nil
+}
+
+// Active returns the end of the current snooze, or false if not snoozed
+func Active() (time.Time, bool) {
+	state, err := Load()
+	if err != nil || state == nil || !time.Now().Before(state.Until) {
+		return time.Time{}, false
+	}
+	return state.Until, true
+}
+
+// ParseUntil accepts either a duration ("1h30m") or a clock time today
+// ("14:30"), rolling over to tomorrow if the time has already passed
+func ParseUntil(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("snooze duration must be positive")
+		}
+		return now.Add(d), nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snooze time %q: use a duration like 1h or a time like 14:30", value)
+	}
+
+	until := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, nil
+}
@@ -0,0 +1,84 @@
+// Package sound plays alert sounds through whichever audio player is
+// installed, and knows when quiet hours mute them.
+package sound
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// players are tried in order when no command is configured: PipeWire,
+// PulseAudio, then ALSA
+var players = []string{"pw-play", "paplay", "aplay"}
+
+// Play plays file with command (the file is appended as its last argument)
+// or, when command is empty, with the first player found on $PATH. It
+// returns once the sound has finished.
+func Play(command, file string) error {
+	var args []string
+	if command != "" {
+		args = strings.Fields(command)
+	} else {
+		for _, player := range players {
+			if _, err := exec.LookPath(player); err == nil {
+				args = []string{player}
+				break
+			}
+		}
+		if args == nil {
+			return fmt.Errorf("no audio player found (tried %s)", strings.Join(players, ", "))
+		}
+	}
+
+	if err := exec.Command(args[0], append(args[1:], file)...).Run(); err != nil {
+		return fmt.Errorf("failed to play %s: %w", file, err)
+	}
+	return nil
+}
+
+// QuietHours is a daily time range, which may wrap past midnight
+type QuietHours struct {
+	start, end int // minutes after midnight
+	set        bool
+}
+
+// ParseQuietHours parses a range such as "22:00-07:00". An empty string
+// means no quiet hours.
+func ParseQuietHours(value string) (QuietHours, error) {
+	if value == "" {
+		return QuietHours{}, nil
+	}
+
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q, expected e.g. 22:00-07:00", value)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours %q: %w", value, err)
+	}
+
+	return QuietHours{
+		start: start.Hour()*60 + start.Minute(),
+		end:   end.Hour()*60 + end.Minute(),
+		set:   true,
+	}, nil
+}
+
+// Contains reports whether t falls within the quiet hours
+func (q QuietHours) Contains(t time.Time) bool {
+	if !q.set {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if q.start <= q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
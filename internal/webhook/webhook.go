@@ -0,0 +1,152 @@
+// Package webhook posts JSON to user-configured URLs when the daemon sees a
+// meeting start or end, or sign-in is needed.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/network"
+)
+
+// Events a webhook can subscribe to
+const (
+	// MeetingStarting fires when a meeting turns urgent
+	MeetingStarting = "meeting-starting"
+	MeetingStarted  = "meeting-started"
+	MeetingEnded    = "meeting-ended"
+	// AuthRequired fires when the daemon's refresh starts failing to sign in
+	AuthRequired = "auth-required"
+)
+
+// Events lists every event name, for validating settings
+var Events = []string{MeetingStarting, MeetingStarted, MeetingEnded, AuthRequired}
+
+// ForStatus returns the event fired when a meeting moves to status, or ""
+func ForStatus(status string) string {
+	switch status {
+	case calendar.StatusUrgent:
+		return MeetingStarting
+	case calendar.StatusCurrent:
+		return MeetingStarted
+	case calendar.StatusPast:
+		return MeetingEnded
+	}
+	return ""
+}
+
+// Payload is the JSON body posted to a webhook
+type Payload struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Meeting *Meeting  `json:"meeting,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Meeting describes the meeting an event is about
+type Meeting struct {
+	Subject   string    `json:"subject"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Location  string    `json:"location,omitempty"`
+	Organizer string    `json:"organizer,omitempty"`
+	JoinURL   string    `json:"join_url,omitempty"`
+	WebLink   string    `json:"web_link,omitempty"`
+	ShowAs    string    `json:"show_as,omitempty"`
+	Private   bool      `json:"private,omitempty"`
+}
+
+// NewMeeting describes event. Private meetings only keep their times.
+func NewMeeting(event calendar.Event) *Meeting {
+	if event.IsPrivate() {
+		return &Meeting{Start: event.Start, End: event.End, ShowAs: event.ShowAs, Private: true}
+	}
+	return &Meeting{
+		Subject:   event.Subject,
+		Start:     event.Start,
+		End:       event.End,
+		Location:  event.Location,
+		Organizer: event.Organizer,
+		JoinURL:   event.GetJoinLink(),
+		WebLink:   event.WebLink,
+		ShowAs:    event.ShowAs,
+	}
+}
+
+// Validate checks that every webhook has a URL and known events
+func Validate(hooks []config.Webhook) error {
+	for i, hook := range hooks {
+		if hook.URL == "" {
+			return fmt.Errorf("webhook %d has no url", i+1)
+		}
+		for _, event := range hook.Events {
+			if !slices.Contains(Events, event) {
+				return fmt.Errorf("webhook %d: unknown event %q", i+1, event)
+			}
+		}
+	}
+	return nil
+}
+
+// Dispatcher posts payloads to the webhooks subscribed to them
+type Dispatcher struct {
+	hooks   []config.Webhook
+	onError func(err error)
+}
+
+func NewDispatcher(hooks []config.Webhook, onError func(err error)) *Dispatcher {
+	return &Dispatcher{hooks: hooks, onError: onError}
+}
+
+// Fire posts payload to every webhook subscribed to its event. A webhook
+// without events receives all of them.
+func (d *Dispatcher) Fire(ctx context.Context, payload Payload) {
+	if payload.Time.IsZero() {
+		payload.Time = time.Now()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		d.onError(fmt.Errorf("failed to marshal webhook payload: %w", err))
+		return
+	}
+
+	for _, hook := range d.hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, payload.Event) {
+			continue
+		}
+		if err := post(ctx, hook, body); err != nil {
+			d.onError(err)
+		}
+	}
+}
+
+func post(ctx context.Context, hook config.Webhook, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := network.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook %s: %w", hook.URL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", hook.URL, resp.Status)
+	}
+	return nil
+}
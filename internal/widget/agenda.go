@@ -0,0 +1,66 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// RenderAgenda renders a multi-day agenda for the terminal, with one
+// section per day between start and end. Days without events are listed too
+// so gaps in the week stay visible.
+func RenderAgenda(events []calendar.Event, start, end time.Time) string {
+	var lines []string
+	now := calendar.Now()
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		nextDay := day.AddDate(0, 0, 1)
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, dayHeaderStyle.Render(dayHeader(day, now)))
+
+		var count int
+		for _, event := range events {
+			if !event.Start.Before(nextDay) || !event.End.After(day) {
+				continue
+			}
+			lines = append(lines, renderAgendaLine(event))
+			count++
+		}
+
+		if count == 0 {
+			lines = append(lines, noMeetingStyle.Render("  "+i18n.T("No meetings")))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderAgendaLine(event calendar.Event) string {
+	status := event.GetStatus()
+
+	timeStr := fmt.Sprintf("%s-%s", i18n.FormatTime(event.Start), i18n.FormatTime(event.End))
+	if event.IsAllDay {
+		timeStr = i18n.T("All day")
+	}
+
+	title := titleStyle.Strikethrough(event.IsCancelled).Render(event.Subject)
+	if status == calendar.StatusPast {
+		title = pastStyle.Strikethrough(event.IsCancelled).Render(event.Subject)
+	}
+
+	parts := []string{" ", render.StatusIcon(status), timeStyle.Render(padRight(timeStr, 11)), title}
+	if event.IsTeams {
+		parts = append(parts, teamsIndicatorStyle.Render("Teams"))
+	} else if event.Location != "" {
+		parts = append(parts, locationStyle.Render("@ "+event.Location))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,29 @@
+package widget
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// backToBackIcon marks a running meeting that runs straight into the next
+const backToBackIcon = "⏭"
+
+// markBackToBack flags output for a running meeting with no break before the
+// next one: an icon in the bar, a back-to-back class and the next meeting at
+// the top of the tooltip
+func markBackToBack(output *WaybarOutput, event *calendar.Event, events []calendar.Event) {
+	if event.GetStatus() != calendar.StatusCurrent {
+		return
+	}
+	next := calendar.NextBackToBack(*event, events)
+	if next == nil {
+		return
+	}
+
+	output.Text += " " + backToBackIcon
+	output.ExtraClasses = append(output.ExtraClasses, "back-to-back")
+
+	header := backToBackIcon + " " + render.Pango.Bold(render.Pango.Escape(i18n.T("Back-to-back: %s at %s", next.Subject, i18n.FormatTime(next.Start))))
+	output.Tooltip = header + "\n\n" + output.Tooltip
+}
@@ -0,0 +1,69 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// DefaultChipsWithin is how far ahead the chips display mode looks
+const DefaultChipsWithin = 4 * time.Hour
+
+// ChipEvents returns the meetings that get a chip of their own: running or
+// starting within the given window, all-day and free events left out
+func ChipEvents(upcomingEvents []calendar.Event, within time.Duration) []calendar.Event {
+	if within <= 0 {
+		within = DefaultChipsWithin
+	}
+
+	now := calendar.Now()
+	var chips []calendar.Event
+	for _, event := range upcomingEvents {
+		if event.IsAllDay || !event.IsBlockingEvent() || !event.End.After(now) {
+			continue
+		}
+		if event.Start.Sub(now) > within {
+			continue
+		}
+		chips = append(chips, event)
+	}
+	return chips
+}
+
+// RenderChips builds one waybar output per imminent meeting, for bars that
+// give each meeting its own module. Every chip gets a "chip-N" class.
+func RenderChips(upcomingEvents []calendar.Event, within time.Duration) []WaybarOutput {
+	events := ChipEvents(redactPrivate(upcomingEvents), within)
+
+	chips := make([]WaybarOutput, 0, len(events))
+	for i, event := range events {
+		chip := generateWaybarOutput(&event, false)
+
+		tooltip := []string{render.EventLine(render.Pango, event, render.TimeRange(event))}
+		if event.DialIn != nil {
+			tooltip = append(tooltip, "☎ "+render.Pango.Escape(i18n.T("Dial-in: %s", formatDialIn(event.DialIn))))
+		}
+		chip.Tooltip = strings.Join(tooltip, "\n")
+		chip.ExtraClasses = append(chip.ExtraClasses, fmt.Sprintf("chip-%d", i))
+		if calendar.HasConflict(event, upcomingEvents) {
+			chip.ExtraClasses = append(chip.ExtraClasses, "conflict")
+		}
+
+		chips = append(chips, chip)
+	}
+	return chips
+}
+
+// RenderChip returns the chip in the given slot, or an empty output (which
+// waybar hides) when there are fewer meetings
+func RenderChip(upcomingEvents []calendar.Event, within time.Duration, slot int) WaybarOutput {
+	chips := RenderChips(upcomingEvents, within)
+	if slot < 0 || slot >= len(chips) {
+		return WaybarOutput{Text: "", Class: "empty", Alt: "empty"}
+	}
+	return chips[slot]
+}
@@ -0,0 +1,102 @@
+package widget
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+)
+
+var classRules []config.ClassRule
+
+// meetingClasses returns the classes that describe a meeting beyond its
+// status: the online meeting provider ("teams", "zoom"), the category color
+// ("cat-red") and those added by class rules
+func meetingClasses(event *calendar.Event) []string {
+	var classes []string
+	if provider := event.GetProvider(); provider != "" {
+		classes = append(classes, provider)
+	}
+	if event.Color != "" {
+		classes = append(classes, "cat-"+event.Color)
+	}
+	if event.IsCancelled {
+		classes = append(classes, "cancelled")
+	}
+	if isEndingSoon(event) {
+		classes = append(classes, "ending-soon")
+	}
+	return append(classes, keywordClasses(event)...)
+}
+
+// SetClassRules sets the keyword rules that add classes to meetings in the
+// bar. Rules with an unknown field or no keyword or class are dropped and
+// reported in the returned error.
+func SetClassRules(rules []config.ClassRule) error {
+	classRules = nil
+
+	var invalid []string
+	for _, rule := range rules {
+		if rule.Field == "" {
+			rule.Field = config.ClassFieldSubject
+		}
+		switch {
+		case rule.Keyword == "" || rule.Class == "":
+			invalid = append(invalid, fmt.Sprintf("%q needs a keyword and a class", rule.Keyword))
+			continue
+		case !validClassField(rule.Field):
+			invalid = append(invalid, fmt.Sprintf("%q has unknown field %q", rule.Keyword, rule.Field))
+			continue
+		}
+		rule.Keyword = strings.ToLower(rule.Keyword)
+		classRules = append(classRules, rule)
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("ignoring class rules: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+func validClassField(field string) bool {
+	switch field {
+	case config.ClassFieldSubject, config.ClassFieldLocation, config.ClassFieldOrganizer, config.ClassFieldCategory:
+		return true
+	}
+	return false
+}
+
+// keywordClasses returns the classes of every rule the event matches, each
+// class once, in rule order
+func keywordClasses(event *calendar.Event) []string {
+	var classes []string
+	for _, rule := range classRules {
+		if matchesClassRule(event, rule) && !slices.Contains(classes, rule.Class) {
+			classes = append(classes, rule.Class)
+		}
+	}
+	return classes
+}
+
+func matchesClassRule(event *calendar.Event, rule config.ClassRule) bool {
+	var values []string
+	switch rule.Field {
+	case config.ClassFieldSubject:
+		values = []string{event.Subject}
+	case config.ClassFieldLocation:
+		values = []string{event.Location}
+	case config.ClassFieldOrganizer:
+		values = []string{event.Organizer}
+	case config.ClassFieldCategory:
+		values = event.Categories
+	}
+
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), rule.Keyword) {
+			return true
+		}
+	}
+	return false
+}
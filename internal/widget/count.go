@@ -0,0 +1,33 @@
+package widget
+
+import (
+	"fmt"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/render"
+)
+
+// generateCountOutput shows how many blocking meetings are left today, e.g.
+// "📅 4". The class follows the next of them.
+func generateCountOutput(todaysEvents []calendar.Event) WaybarOutput {
+	var remaining []calendar.Event
+	for _, event := range todaysEvents {
+		if event.IsBlockingEvent() && event.GetStatus() != calendar.StatusPast {
+			remaining = append(remaining, event)
+		}
+	}
+
+	output := WaybarOutput{
+		Text:    fmt.Sprintf("%s %d", render.StatusIcon("event"), len(remaining)),
+		Class:   "no-meeting",
+		Alt:     "no-meeting",
+		Tooltip: generateTooltipForSchedule(todaysEvents),
+	}
+	if len(remaining) > 0 {
+		status := remaining[0].GetStatus()
+		output.Class = status
+		output.Alt = status
+	}
+
+	return output
+}
@@ -0,0 +1,159 @@
+package widget
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+)
+
+// staleCacheAge is how old the daemon's snapshot may get before the
+// countdown warns that it is showing old data
+const staleCacheAge = 10 * time.Minute
+
+// failureNoticeAfter is how many refreshes must fail in a row before the
+// tooltip says so; a single failure is usually a blip
+const failureNoticeAfter = 2
+
+// maxFailureSummary caps the error summary in the tooltip, which can be a
+// whole error message when the error has no status code
+const maxFailureSummary = 40
+
+// CountdownData is passed to the countdown format templates
+type CountdownData struct {
+	Subject string
+	Start   string
+	End     string
+	Hours   int
+	Minutes int
+	Seconds int
+	// Remaining is the time a running meeting has left, e.g. "12m"
+	Remaining string
+}
+
+// RunWaybarCountdown prints a live countdown to the next meeting. It only
+// reads the daemon's event cache, so it is cheap enough for interval=1.
+func RunWaybarCountdown(config *Config) error {
+	snapshot, err := cache.Load()
+	if err != nil || snapshot == nil {
+		output := WaybarOutput{
+			Text:    i18n.T("No calendar cache"),
+			Class:   "error",
+			Alt:     "no-cache",
+			Tooltip: i18n.T("Start the cache daemon: calendar-widget daemon"),
+		}
+		jsonBytes, _ := json.Marshal(output)
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	snapshot.TodaysEvents = redactPrivate(snapshot.TodaysEvents)
+	snapshot.UpcomingEvents = redactPrivate(snapshot.UpcomingEvents)
+
+	output := applySnooze(generateCountdownOutput(snapshot, config.Settings))
+	jsonBytes, _ := json.Marshal(output)
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+func generateCountdownOutput(snapshot *cache.Snapshot, settings *config.Config) WaybarOutput {
+	tooltip := scheduleTooltip(settings, generateTooltipForSchedule(snapshot.TodaysEvents), snapshot.TodaysEvents, snapshot.UpcomingEvents)
+	var staleClasses []string
+	if snapshot.IsStale(staleCacheAge) {
+		tooltip += "\n\n⚠ " + i18n.T("Last updated %s", i18n.FormatTime(snapshot.UpdatedAt))
+		staleClasses = []string{"stale"}
+	}
+	if notice := failureNotice(snapshot.Failures); notice != "" {
+		if staleClasses == nil {
+			tooltip += "\n"
+		}
+		tooltip += "\n" + notice
+	}
+
+	displayEvent := calendar.SelectNextMeeting(snapshot.UpcomingEvents, calendar.Now(), selection.Display)
+	if displayEvent == nil {
+		return WaybarOutput{
+			Text:         i18n.T("No upcoming meetings"),
+			Class:        "no-meeting",
+			Alt:          "no-meeting",
+			Tooltip:      tooltip,
+			ExtraClasses: staleClasses,
+		}
+	}
+
+	status := displayEvent.GetStatus()
+	output := WaybarOutput{
+		Text:         render.Pango.Cancelled(displayEvent, render.Pango.Escape(formatCountdown(displayEvent, settings.Countdown))),
+		Class:        status,
+		Alt:          status,
+		Tooltip:      tooltip,
+		Percentage:   meetingPercentage(displayEvent),
+		ExtraClasses: meetingClasses(displayEvent),
+	}
+	if calendar.HasConflict(*displayEvent, snapshot.UpcomingEvents) {
+		output.ExtraClasses = append(output.ExtraClasses, "conflict")
+	}
+	markBackToBack(&output, displayEvent, snapshot.UpcomingEvents)
+	output.ExtraClasses = append(output.ExtraClasses, staleClasses...)
+	return output
+}
+
+// failureNotice describes the daemon's failed refreshes in one line, e.g.
+// "⚠ 3 failed refreshes, last: 401 at 10:32"
+func failureNotice(failures *cache.Failures) string {
+	if failures == nil || failures.Count < failureNoticeAfter {
+		return ""
+	}
+	summary := truncate(strings.Join(strings.Fields(failures.Last), " "), maxFailureSummary)
+	return "⚠ " + render.Pango.Escape(i18n.T("%d failed refreshes, last: %s at %s", failures.Count, summary, i18n.FormatTime(failures.LastAt)))
+}
+
+func formatCountdown(event *calendar.Event, formats config.CountdownConfig) string {
+	timeUntil := event.GetTimeUntil()
+	if timeUntil < 0 {
+		timeUntil = 0
+	}
+
+	data := CountdownData{
+		Subject:   truncate(event.Subject, maxSubjectWidth),
+		Start:     i18n.FormatTime(event.Start),
+		End:       i18n.FormatTime(event.End),
+		Hours:     int(timeUntil.Hours()),
+		Minutes:   int(timeUntil.Minutes()) % 60,
+		Seconds:   int(timeUntil.Seconds()) % 60,
+		Remaining: formatDuration(timeLeft(event)),
+	}
+
+	var format string
+	switch {
+	case event.GetStatus() == calendar.StatusCurrent:
+		format = formats.Current
+	case timeUntil >= time.Hour:
+		format = formats.Hours
+	case timeUntil >= 5*time.Minute:
+		format = formats.Minutes
+	default:
+		format = formats.Urgent
+	}
+
+	tmpl, err := template.New("countdown").Parse(format)
+	if err != nil {
+		return fmt.Sprintf("invalid countdown format: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("invalid countdown format: %v", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,177 @@
+package widget
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mapSearchURL is opened for in-person meeting locations
+const mapSearchURL = "https://www.openstreetmap.org/search?query="
+
+// mapLink returns a map search for the event's location, or "" for online
+// meetings and events without a location
+func mapLink(event calendar.Event) string {
+	if !event.IsInPerson() {
+		return ""
+	}
+	return mapSearchURL + url.QueryEscape(event.Location)
+}
+
+// updateDetail handles keys while the detail pane is open
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	event := *m.nextMeeting
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.detail = false
+	case "enter", "j":
+		return m, openMeetingCmd(event)
+	case "m":
+		if link := mapLink(event); link != "" {
+			return m, openURLCmd(link)
+		}
+	case "o":
+		if link := event.OutlookLink(); link != "" {
+			return m, openURLCmd(link)
+		}
+	case "y":
+		return m, copyLinkCmd(event)
+	case "n":
+		return m, openNotesCmd(event, m.config.notes())
+	case "up", "k":
+		if m.detailOffset > 0 {
+			m.detailOffset--
+		}
+	case "down":
+		if m.detailOffset < m.maxDetailOffset() {
+			m.detailOffset++
+		}
+	}
+	return m, nil
+}
+
+// viewDetail renders the detail pane, scrolled to the current offset and
+// cut to the window height so the key help stays visible
+func (m model) viewDetail() string {
+	lines := strings.Split(renderDetail(*m.nextMeeting, m.width), "\n")
+	help := noMeetingStyle.Render(i18n.T("enter join · m map · o Outlook · y copy link · n notes · ↑/↓ scroll · esc back"))
+
+	if m.height > 2 && len(lines) > m.height-2 {
+		// The meeting can change under the pane on refresh, so clamp again
+		offset := min(m.detailOffset, m.maxDetailOffset())
+		lines = lines[offset : offset+m.height-2]
+	}
+	return strings.Join(lines, "\n") + "\n\n" + help
+}
+
+// maxDetailOffset is how far the detail pane can scroll before its last
+// line reaches the bottom of the window
+func (m model) maxDetailOffset() int {
+	if m.height <= 2 {
+		return 0
+	}
+	lines := strings.Count(renderDetail(*m.nextMeeting, m.width), "\n") + 1
+	return max(0, lines-(m.height-2))
+}
+
+// renderDetail lays out everything known about one event: time, organizer,
+// location, attendees with their responses, join options and the body
+func renderDetail(event calendar.Event, width int) string {
+	var lines []string
+
+	lines = append(lines, titleStyle.Render(event.Subject))
+	when := fmt.Sprintf("%s %s–%s", i18n.FormatDate(event.Start), i18n.FormatTime(event.Start), i18n.FormatTime(event.End))
+	if event.IsAllDay {
+		when = i18n.FormatDate(event.Start)
+	}
+	lines = append(lines, render.StatusIcon(event.GetStatus())+" "+timeStyle.MarginRight(0).Render(when))
+	lines = append(lines, "")
+
+	if event.Organizer != "" {
+		lines = append(lines, i18n.T("Organizer: %s", event.Organizer))
+	}
+	if event.Location != "" {
+		lines = append(lines, i18n.T("Location: %s", event.Location))
+		if link := mapLink(event); link != "" {
+			lines = append(lines, "  "+linkStyle.Render(link))
+		}
+	}
+
+	if len(event.Attendees) > 0 {
+		lines = append(lines, "", sectionStyle.Render(i18n.T("Attendees (%d)", len(event.Attendees))))
+		for _, attendee := range event.Attendees {
+			icon, ok := render.ResponseIcons[event.AttendeeResponses[attendee]]
+			switch {
+			case attendee == event.Organizer:
+				icon = "★"
+			case !ok:
+				icon = "·"
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s", icon, attendee))
+		}
+	}
+
+	var join []string
+	if link := event.GetJoinLink(); link != "" {
+		provider := "Teams"
+		if !event.IsTeams {
+			provider = "Zoom"
+		}
+		join = append(join, fmt.Sprintf("  %s: %s", provider, linkStyle.Render(link)))
+	}
+	if event.DialIn != nil {
+		join = append(join, "  ☎ "+i18n.T("Dial-in: %s", formatDialIn(event.DialIn)))
+	}
+	if event.WebLink != "" {
+		join = append(join, fmt.Sprintf("  Outlook: %s", linkStyle.Render(event.WebLink)))
+	}
+	if len(join) > 0 {
+		lines = append(lines, "", sectionStyle.Render(i18n.T("Join")))
+		lines = append(lines, join...)
+	}
+
+	var documents []string
+	if event.HasAttachments {
+		documents = append(documents, "  "+render.AttachmentIndicator+" "+i18n.T("Files attached to the invite"))
+	}
+	for _, link := range event.DocumentLinks() {
+		documents = append(documents, "  "+linkStyle.Render(link))
+	}
+	if len(documents) > 0 {
+		lines = append(lines, "", sectionStyle.Render(i18n.T("Documents")))
+		lines = append(lines, documents...)
+	}
+
+	if body := event.BodyText; body != "" {
+		if width > 0 {
+			// Width pads every line to the full width, which only gets in the way here
+			wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(body), "\n")
+			for i, line := range wrapped {
+				wrapped[i] = strings.TrimRight(line, " ")
+			}
+			body = strings.Join(wrapped, "\n")
+		}
+		lines = append(lines, "", body)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func openURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := OpenURL(url); err != nil {
+			return errMsg(err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+)
+
+// RenderDigest renders the morning digest as a title and a plain text body,
+// used both for the notification and for printing in the terminal
+func RenderDigest(summary calendar.DaySummary) (string, string) {
+	count := len(summary.Meetings)
+	if count == 0 {
+		title := i18n.T("No meetings today")
+		if summary.LargestFree != nil {
+			return title, i18n.T("Free %s-%s", i18n.FormatTime(summary.LargestFree.Start), i18n.FormatTime(summary.LargestFree.End))
+		}
+		return title, ""
+	}
+
+	title := i18n.T("%d meetings today", count)
+	if count == 1 {
+		title = i18n.T("1 meeting today")
+	}
+
+	lines := []string{
+		i18n.T("First at %s, last ends %s", i18n.FormatTime(summary.First), i18n.FormatTime(summary.Last)),
+		i18n.T("%s in meetings", formatDuration(summary.Busy)),
+	}
+	if free := summary.LargestFree; free != nil {
+		lines = append(lines, i18n.T("Largest free block: %s-%s (%s)",
+			i18n.FormatTime(free.Start), i18n.FormatTime(free.End), formatDuration(free.GetDuration())))
+	} else {
+		lines = append(lines, i18n.T("No free time today"))
+	}
+
+	lines = append(lines, "")
+	for _, meeting := range redactPrivate(summary.Meetings) {
+		lines = append(lines, fmt.Sprintf("%s %s", i18n.FormatTime(meeting.Start), meeting.Subject))
+	}
+
+	return title, strings.Join(lines, "\n")
+}
@@ -0,0 +1,116 @@
+package widget
+
+import (
+	"html"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/snooze"
+)
+
+// EwwOutput is the JSON read by eww's defpoll or deflisten. Every field is
+// always present, so yuck expressions like data.next.subject never hit a
+// missing key; HasNext tells whether Next holds a meeting.
+type EwwOutput struct {
+	// Text and Class are the bar text and status class, as in waybar
+	Text    string     `json:"text"`
+	Class   string     `json:"class"`
+	HasNext bool       `json:"has_next"`
+	Next    EwwEvent   `json:"next"`
+	Events  []EwwEvent `json:"events"`
+	Snoozed bool       `json:"snoozed"`
+	// SnoozedUntil is a Unix time, 0 when not snoozed
+	SnoozedUntil int64 `json:"snoozed_until"`
+	// Error explains an error class, e.g. that sign-in is needed
+	Error string `json:"error"`
+}
+
+// EwwEvent is a meeting in EwwOutput. Times are Unix seconds so yuck's
+// formattime can show them; Time is the range in the configured format.
+type EwwEvent struct {
+	Subject      string `json:"subject"`
+	Start        int64  `json:"start"`
+	End          int64  `json:"end"`
+	Time         string `json:"time"`
+	MinutesUntil int    `json:"minutes_until"`
+	Status       string `json:"status"`
+	Icon         string `json:"icon"`
+	Location     string `json:"location"`
+	Link         string `json:"link"`
+	AllDay       bool   `json:"all_day"`
+	Cancelled    bool   `json:"cancelled"`
+	// Conflict is set when the meeting overlaps an earlier one today
+	Conflict bool `json:"conflict"`
+}
+
+// RenderEww builds the eww output: the next meeting as shown in the bar and
+// all of today's meetings with their fields, for popups drawn in yuck
+func RenderEww(cfg *Config, todaysEvents, upcomingEvents []calendar.Event) EwwOutput {
+	todaysEvents = redactPrivate(todaysEvents)
+	upcomingEvents = redactPrivate(upcomingEvents)
+
+	output := EwwOutput{
+		Text:   i18n.T("No upcoming meetings"),
+		Class:  "no-meeting",
+		Events: make([]EwwEvent, 0, len(todaysEvents)),
+	}
+	if displayEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display); displayEvent != nil {
+		bar := generateBarOutput(render.Plain, displayEvent, cfg.Display == DisplayRemaining)
+		output.Text = bar.Text
+		output.Class = bar.Class
+		output.HasNext = true
+		output.Next = ewwEvent(*displayEvent, false)
+	}
+
+	conflicts := calendar.ConflictsWithPrevious(todaysEvents)
+	for i, event := range todaysEvents {
+		output.Events = append(output.Events, ewwEvent(event, conflicts[i]))
+	}
+	return output
+}
+
+func ewwEvent(event calendar.Event, conflict bool) EwwEvent {
+	status := event.GetStatus()
+	link, _ := MeetingLink(event)
+	return EwwEvent{
+		Subject:      event.Subject,
+		Start:        event.Start.Unix(),
+		End:          event.End.Unix(),
+		Time:         render.TimeRange(event),
+		MinutesUntil: int(event.GetTimeUntil().Minutes()),
+		Status:       status,
+		Icon:         render.StatusIcon(status),
+		Location:     event.Location,
+		Link:         link,
+		AllDay:       event.IsAllDay,
+		Cancelled:    event.IsCancelled,
+		Conflict:     conflict,
+	}
+}
+
+// ewwOutput turns an error output built for waybar into the eww format
+func ewwOutput(output WaybarOutput) EwwOutput {
+	return EwwOutput{
+		Text:   html.UnescapeString(output.Text),
+		Class:  output.Class,
+		Events: []EwwEvent{},
+		Error:  html.UnescapeString(output.Tooltip),
+	}
+}
+
+// applyEwwSnooze marks the output as snoozed and mutes a pending meeting's
+// class, as applySnooze does for waybar
+func applyEwwSnooze(output EwwOutput) EwwOutput {
+	until, ok := snooze.Active()
+	if !ok {
+		return output
+	}
+	if calendar.IsPending(output.Class) {
+		output.Class = "muted"
+	}
+	output.Snoozed = true
+	output.SnoozedUntil = until.Unix()
+	return output
+}
@@ -0,0 +1,78 @@
+package widget
+
+import (
+	"fmt"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// Layout presets for the bar and the TUI. An empty layout keeps the standard
+// one: icon, title and countdown.
+const (
+	// LayoutMicro shows the icon and the minutes until the start (or left)
+	LayoutMicro = "micro"
+	// LayoutCompact shows the icon and the title
+	LayoutCompact = "compact"
+	// LayoutFull shows the icon, start time, title and location
+	LayoutFull = "full"
+)
+
+// ValidateLayout checks a layout name from settings or a flag
+func ValidateLayout(layout string) error {
+	switch layout {
+	case "", LayoutMicro, LayoutCompact, LayoutFull:
+		return nil
+	}
+	return fmt.Errorf("unknown layout %q, expected micro, compact or full", layout)
+}
+
+var waybarLayout string
+
+// SetWaybarLayout picks the layout of the bar text for the next and
+// remaining display modes and chips
+func SetWaybarLayout(layout string) error {
+	if err := ValidateLayout(layout); err != nil {
+		return err
+	}
+	waybarLayout = layout
+	return nil
+}
+
+// layoutBarText renders the bar text of meeting in one of the presets, or ""
+// for the standard layout
+func layoutBarText(t render.Target, meeting *calendar.Event, icon, status string) string {
+	switch waybarLayout {
+	case LayoutMicro:
+		return t.Status(status, icon) + " " + t.Bold(t.Escape(microTime(meeting)))
+	case LayoutCompact:
+		return formatBarText(t, icon, status, truncate(meeting.Subject, maxSubjectWidth), "")
+	case LayoutFull:
+		text := t.Status(status, icon) + " " + t.Dim(i18n.FormatTime(meeting.Start)) + " " +
+			t.Bold(t.Escape(truncate(meeting.Subject, maxSubjectWidth)))
+		if meeting.IsInPerson() {
+			text += " " + t.Dim(t.Escape("@ "+meeting.Location))
+		}
+		return text
+	}
+	return ""
+}
+
+// microTime is the minutes until meeting starts, or how long it has left
+// once running
+func microTime(meeting *calendar.Event) string {
+	switch meeting.GetStatus() {
+	case calendar.StatusCurrent:
+		return i18n.T("%s left", formatDuration(timeLeft(meeting)))
+	case calendar.StatusPast:
+		return ""
+	}
+	until := meeting.GetTimeUntil()
+	if until < 0 {
+		until = 0
+	}
+	// Round up so a meeting a few seconds away still reads "1m"
+	return formatDuration((until + time.Minute - 1).Truncate(time.Minute))
+}
@@ -0,0 +1,138 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// Day markers of the month view
+const (
+	meetingDayMarker = "•"
+	allDayMarker     = "◦"
+)
+
+// MonthRange returns the first instant of t's month and of the next one
+func MonthRange(t time.Time) (time.Time, time.Time) {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return first, first.AddDate(0, 1, 0)
+}
+
+// RunDateModule prints the date module: today's date in the bar and a
+// month view in the tooltip. It replaces the calendar of waybar's clock
+// module, so it prints the date even when events can't be fetched or the
+// calendar service can't be created.
+func (w *Widget) RunDateModule() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := calendar.Now()
+	from, until := MonthRange(now)
+	var events []calendar.Event
+	service, err := w.provider()
+	if err == nil {
+		events, err = service.GetEventsBetween(ctx, from, until)
+	}
+
+	output := RenderDateModule(events, now)
+	if err != nil {
+		output.Tooltip += "\n\n⚠️ " + render.Pango.Escape(err.Error())
+	}
+
+	jsonBytes, _ := json.Marshal(output)
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+// RenderDateModule renders the date module for now from the month's events
+func RenderDateModule(monthEvents []calendar.Event, now time.Time) WaybarOutput {
+	return WaybarOutput{
+		Text:    render.Pango.Escape(i18n.FormatDate(now)),
+		Tooltip: renderMonthView(monthEvents, now),
+		Class:   "date",
+		Alt:     "date",
+	}
+}
+
+// renderMonthView draws now's month as a grid, marking days with meetings
+// and days with only all-day events. Today is bold and underlined.
+func renderMonthView(monthEvents []calendar.Event, now time.Time) string {
+	first, next := MonthRange(now)
+	markers := dayMarkers(monthEvents, first, next)
+
+	title := i18n.Month(now) + " " + fmt.Sprint(now.Year())
+	const gridWidth = 7*3 - 1
+	pad := max((gridWidth-textWidth(title))/2, 0)
+	lines := []string{strings.Repeat(" ", pad) + "<b>" + render.Pango.Escape(title) + "</b>"}
+
+	weekStart := i18n.WeekStart()
+	var header []string
+	for i := range 7 {
+		day := []rune(i18n.Current().Days[(int(weekStart)+i)%7])
+		header = append(header, render.Pango.Escape(padRight(string(day[:min(2, len(day))]), 2)))
+	}
+	lines = append(lines, render.Pango.Dim(strings.Join(header, " ")))
+
+	var row strings.Builder
+	row.WriteString(strings.Repeat("   ", (int(first.Weekday())-int(weekStart)+7)%7))
+	for day := first; day.Before(next); day = day.AddDate(0, 0, 1) {
+		number := fmt.Sprintf("%2d", day.Day())
+		if day.Day() == now.Day() {
+			number = "<b><u>" + number + "</u></b>"
+		}
+		marker := markers[day.Day()]
+		if marker == "" {
+			marker = " "
+		}
+		row.WriteString(number + marker)
+
+		if day.AddDate(0, 0, 1).Weekday() == weekStart {
+			lines = append(lines, strings.TrimRight(row.String(), " "))
+			row.Reset()
+		}
+	}
+	if row.Len() > 0 {
+		lines = append(lines, strings.TrimRight(row.String(), " "))
+	}
+
+	lines = append(lines, "", render.Pango.Dim(meetingDayMarker+" "+render.Pango.Escape(i18n.T("meetings"))+"  "+allDayMarker+" "+render.Pango.Escape(i18n.T("all-day"))))
+	return "<tt>" + strings.Join(lines, "\n") + "</tt>"
+}
+
+// dayMarkers maps each day of the month from first to next that has events
+// to its marker: meetings win over all-day events
+func dayMarkers(monthEvents []calendar.Event, first, next time.Time) map[int]string {
+	markers := map[int]string{}
+	for _, event := range monthEvents {
+		if event.IsDeclined() {
+			continue
+		}
+		var marker string
+		switch {
+		case event.IsAllDay:
+			marker = allDayMarker
+		case event.IsBlockingEvent():
+			marker = meetingDayMarker
+		default:
+			continue
+		}
+
+		// Multi-day events mark every day they cover
+		startDay := time.Date(event.Start.Year(), event.Start.Month(), event.Start.Day(), 0, 0, 0, 0, first.Location())
+		for day := startDay; day.Before(next) && (day.Equal(startDay) || day.Before(event.End)); day = day.AddDate(0, 0, 1) {
+			if day.Before(first) {
+				continue
+			}
+			if marker == meetingDayMarker || markers[day.Day()] == "" {
+				markers[day.Day()] = marker
+			}
+		}
+	}
+	return markers
+}
@@ -0,0 +1,56 @@
+package widget
+
+import (
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+)
+
+// maxMultiSubject keeps each inline meeting short enough to fit several
+const maxMultiSubject = 20
+
+// generateMultiOutput shows the next meetings inline, e.g.
+// "10:00 Standup · 13:00 1:1". The class follows the first meeting.
+func generateMultiOutput(upcomingEvents []calendar.Event, todaysEvents []calendar.Event, settings config.NextMeetingsConfig) WaybarOutput {
+	now := calendar.Now()
+
+	var next []calendar.Event
+	for _, event := range upcomingEvents {
+		if event.IsAllDay || !event.End.After(now) {
+			continue
+		}
+		next = append(next, event)
+		if len(next) == settings.Count {
+			break
+		}
+	}
+
+	if len(next) == 0 {
+		return WaybarOutput{
+			Text:    i18n.T("No upcoming meetings"),
+			Class:   "no-meeting",
+			Alt:     "no-meeting",
+			Tooltip: generateTooltipForSchedule(todaysEvents),
+		}
+	}
+
+	parts := make([]string, len(next))
+	for i, event := range next {
+		subject := event.Subject
+		subject = truncate(subject, maxMultiSubject)
+		parts[i] = render.Pango.Dim(i18n.FormatTime(event.Start)) + " " + render.Pango.Bold(render.Pango.Cancelled(&event, render.Pango.Escape(subject)))
+	}
+
+	status := next[0].GetStatus()
+	return WaybarOutput{
+		Text:         strings.Join(parts, render.Pango.Escape(settings.Separator)),
+		Class:        status,
+		Alt:          status,
+		Tooltip:      generateTooltipForSchedule(todaysEvents),
+		Percentage:   meetingPercentage(&next[0]),
+		ExtraClasses: meetingClasses(&next[0]),
+	}
+}
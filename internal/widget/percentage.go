@@ -0,0 +1,42 @@
+package widget
+
+import (
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+// DefaultPercentageHorizon is how far ahead a meeting starts to count up
+const DefaultPercentageHorizon = time.Hour
+
+var percentageHorizon = DefaultPercentageHorizon
+
+// SetPercentageHorizon sets how long before a meeting its percentage starts
+// rising from 0. Zero or less keeps the default.
+func SetPercentageHorizon(horizon time.Duration) {
+	if horizon <= 0 {
+		horizon = DefaultPercentageHorizon
+	}
+	percentageHorizon = horizon
+}
+
+// meetingPercentage returns the waybar percentage for a meeting: how much
+// of it has passed while it runs, otherwise how close its start is within
+// the horizon (0 at the horizon or further out, 100 when it starts)
+func meetingPercentage(event *calendar.Event) int {
+	now := calendar.Now()
+
+	if !event.Start.After(now) {
+		duration := event.End.Sub(event.Start)
+		if duration <= 0 {
+			return 100
+		}
+		return clampPercentage(float64(now.Sub(event.Start)) / float64(duration))
+	}
+
+	return clampPercentage(1 - float64(event.Start.Sub(now))/float64(percentageHorizon))
+}
+
+func clampPercentage(fraction float64) int {
+	return int(min(max(fraction, 0), 1) * 100)
+}
@@ -0,0 +1,53 @@
+package widget
+
+import (
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+)
+
+var privacy bool
+
+// SetPrivacy hides the details of private and confidential events in the bar
+// and tooltips, e.g. while screen sharing
+func SetPrivacy(enabled bool) {
+	privacy = enabled
+}
+
+// redactPrivate returns events with private and confidential ones redacted
+// by RedactEvent while privacy mode is on
+func redactPrivate(events []calendar.Event) []calendar.Event {
+	if !privacy {
+		return events
+	}
+
+	redacted := make([]calendar.Event, len(events))
+	for i, event := range events {
+		redacted[i] = RedactEvent(event)
+	}
+	return redacted
+}
+
+// RedactEvent replaces the subject of a private or confidential event with
+// "Busy" and drops its location, people and body, including the location it
+// was moved from. Join links are kept so clicking still works. Other events
+// are returned as they are.
+func RedactEvent(event calendar.Event) calendar.Event {
+	if !event.IsPrivate() {
+		return event
+	}
+	event.Subject = i18n.T("Busy")
+	event.Location = ""
+	event.Body = ""
+	event.BodyText = ""
+	event.Organizer = ""
+	event.Attendees = nil
+	event.AttendeeResponses = nil
+	event.Categories = nil
+	event.Color = ""
+	if event.Change != nil {
+		change := *event.Change
+		change.PreviousLocation = ""
+		event.Change = &change
+	}
+	return event
+}
@@ -0,0 +1,38 @@
+package widget
+
+import (
+	"calendar-widget/internal/calendar"
+	"testing"
+	"time"
+)
+
+func TestRedactEvent(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	private := calendar.Event{
+		Subject:     "Salary review",
+		Location:    "HR office",
+		Sensitivity: "private",
+		TeamsLink:   "https://teams.microsoft.com/l/meetup-join/1",
+		Start:       start,
+		Change:      &calendar.EventChange{PreviousStart: start.Add(-time.Hour), PreviousLocation: "Room 1"},
+	}
+
+	redacted := RedactEvent(private)
+	if redacted.Subject == private.Subject || redacted.Location != "" {
+		t.Errorf("subject %q, location %q, want them hidden", redacted.Subject, redacted.Location)
+	}
+	if redacted.Change.PreviousLocation != "" || !redacted.Change.PreviousStart.Equal(start.Add(-time.Hour)) {
+		t.Errorf("change %+v, want the previous location dropped and the previous start kept", *redacted.Change)
+	}
+	if redacted.TeamsLink != private.TeamsLink {
+		t.Errorf("join link %q, want it kept", redacted.TeamsLink)
+	}
+	if private.Change.PreviousLocation != "Room 1" {
+		t.Error("RedactEvent changed the original event's change")
+	}
+
+	public := calendar.Event{Subject: "Standup", Location: "Room 2"}
+	if got := RedactEvent(public); got.Subject != public.Subject || got.Location != public.Location {
+		t.Errorf("got %q at %q, want a normal event unchanged", got.Subject, got.Location)
+	}
+}
@@ -0,0 +1,64 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"calendar-widget/internal/cache"
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/selection"
+)
+
+// DefaultPromptWithin is how soon a meeting must start to be in the prompt
+const DefaultPromptWithin = time.Hour
+
+// DefaultPromptWidth caps the subject in the prompt, in terminal columns
+const DefaultPromptWidth = 20
+
+// RunPrompt prints a terse segment for a shell prompt, e.g. "⏰ 12m→Standup".
+// It only reads the daemon's cache and prints nothing when the cache is
+// missing or old, so it never waits for the network or asks to sign in.
+func RunPrompt(within time.Duration, width int) {
+	snapshot, err := cache.Load()
+	if err != nil || snapshot == nil || snapshot.IsStale(staleCacheAge) {
+		return
+	}
+	if segment := RenderPrompt(snapshot.UpcomingEvents, within, width); segment != "" {
+		fmt.Println(segment)
+	}
+}
+
+// RenderPrompt renders the prompt segment for the running meeting or the
+// next one starting within the given time, or "" when there is none
+func RenderPrompt(upcomingEvents []calendar.Event, within time.Duration, width int) string {
+	displayEvent := calendar.SelectNextMeeting(redactPrivate(upcomingEvents), calendar.Now(), selection.Display)
+	if displayEvent == nil {
+		return ""
+	}
+
+	var when string
+	switch timeUntil := displayEvent.GetTimeUntil(); {
+	case displayEvent.GetStatus() == calendar.StatusCurrent:
+		when = i18n.T("%s left", formatDuration(timeLeft(displayEvent)))
+	case timeUntil > within:
+		return ""
+	default:
+		when = formatDuration(max(timeUntil, 0))
+	}
+
+	return "⏰ " + when + "→" + truncate(promptSafe(displayEvent.Subject), width)
+}
+
+// promptSafe drops control characters, so a subject can't move the cursor
+// or send escape sequences to the terminal from inside a prompt
+func promptSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
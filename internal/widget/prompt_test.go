@@ -0,0 +1,64 @@
+package widget
+
+import (
+	"calendar-widget/internal/calendar"
+	"testing"
+	"time"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	calendar.SetClock(calendar.FixedClock(now))
+	defer calendar.SetClock(nil)
+
+	meeting := func(subject string, start, minutes int) calendar.Event {
+		return calendar.Event{
+			ID:      subject,
+			Subject: subject,
+			Start:   now.Add(time.Duration(start) * time.Minute),
+			End:     now.Add(time.Duration(start+minutes) * time.Minute),
+			ShowAs:  "busy",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []calendar.Event
+		within time.Duration
+		width  int
+		want   string
+	}{
+		{name: "next meeting", events: []calendar.Event{meeting("Standup", 12, 15)}, within: time.Hour, width: 20, want: "⏰ 12m→Standup"},
+		{name: "running meeting", events: []calendar.Event{meeting("Standup", -5, 30)}, within: time.Hour, width: 20, want: "⏰ 25m left→Standup"},
+		{name: "too far out", events: []calendar.Event{meeting("Review", 90, 30)}, within: time.Hour, width: 20, want: ""},
+		{name: "no meetings", events: nil, within: time.Hour, width: 20, want: ""},
+		{name: "truncated", events: []calendar.Event{meeting("Quarterly business review", 5, 60)}, within: time.Hour, width: 10, want: "⏰ 5m→Quarter..."},
+		{name: "control characters dropped", events: []calendar.Event{meeting("Stand\x1b[2Jup", 5, 15)}, within: time.Hour, width: 20, want: "⏰ 5m→Stand[2Jup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderPrompt(tt.events, tt.within, tt.width); got != tt.want {
+				t.Errorf("RenderPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkRenderPrompt measures the work the prompt does on top of
+// starting the binary and reading the cache
+func BenchmarkRenderPrompt(b *testing.B) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	calendar.SetClock(calendar.FixedClock(now))
+	defer calendar.SetClock(nil)
+
+	var events []calendar.Event
+	for i := range 20 {
+		start := now.Add(time.Duration(i*30) * time.Minute)
+		events = append(events, calendar.Event{ID: "e", Subject: "Planning", Start: start, End: start.Add(25 * time.Minute), ShowAs: "busy"})
+	}
+
+	for b.Loop() {
+		RenderPrompt(events, DefaultPromptWithin, DefaultPromptWidth)
+	}
+}
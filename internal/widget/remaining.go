@@ -0,0 +1,26 @@
+package widget
+
+import (
+	"time"
+
+	"calendar-widget/internal/calendar"
+)
+
+// endingSoonWithin is how close to its end a running meeting gets the
+// ending-soon class
+const endingSoonWithin = 5 * time.Minute
+
+// timeLeft is how long a running meeting has left, rounded up to whole
+// minutes so the last minute reads "1m left" rather than "0m left"
+func timeLeft(event *calendar.Event) time.Duration {
+	left := event.End.Sub(calendar.Now())
+	if left <= 0 {
+		return 0
+	}
+	return (left + time.Minute - 1).Truncate(time.Minute)
+}
+
+// isEndingSoon reports whether a running meeting is in its final minutes
+func isEndingSoon(event *calendar.Event) bool {
+	return event.GetStatus() == calendar.StatusCurrent && event.End.Sub(calendar.Now()) <= endingSoonWithin
+}
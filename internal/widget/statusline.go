@@ -0,0 +1,47 @@
+package widget
+
+import (
+	"html"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/snooze"
+)
+
+// statusLineTarget returns the render target of a multiplexer format
+func statusLineTarget(format string) render.Target {
+	if format == FormatTmux {
+		return render.Tmux
+	}
+	return render.TrueColor
+}
+
+// RenderStatusLine renders the next meeting as one line for a terminal
+// multiplexer's status bar, styled for t
+func RenderStatusLine(t render.Target, cfg *Config, upcomingEvents []calendar.Event) string {
+	upcomingEvents = redactPrivate(upcomingEvents)
+
+	displayEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if displayEvent == nil {
+		return t.Dim(t.Escape(i18n.T("No upcoming meetings")))
+	}
+	return generateBarOutput(t, displayEvent, cfg.Display == DisplayRemaining).Text
+}
+
+// statusLine turns an output built for waybar, e.g. an error, into a status
+// line styled for t
+func statusLine(t render.Target, output WaybarOutput) string {
+	text := strings.Join(strings.Fields(html.UnescapeString(output.Text)), " ")
+	return t.Status(calendar.StatusUrgent, t.Escape(text))
+}
+
+// applyStatusLineSnooze marks a snoozed status line with a muted bell
+func applyStatusLineSnooze(t render.Target, line string) string {
+	if _, ok := snooze.Active(); !ok {
+		return line
+	}
+	return t.Dim("🔕") + " " + line
+}
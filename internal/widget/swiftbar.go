@@ -0,0 +1,84 @@
+package widget
+
+import (
+	"html"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/snooze"
+)
+
+// swiftBarEscaper keeps calendar text from being read as SwiftBar syntax:
+// "|" starts the parameters of a line
+var swiftBarEscaper = strings.NewReplacer("|", "¦", "\n", " ")
+
+// RenderSwiftBar renders the output of a SwiftBar or xbar plugin: the bar
+// line, then a menu with a join item for the shown meeting and today's
+// schedule, where each meeting opens its link
+func RenderSwiftBar(cfg *Config, todaysEvents, upcomingEvents []calendar.Event) string {
+	todaysEvents = redactPrivate(todaysEvents)
+	upcomingEvents = redactPrivate(upcomingEvents)
+
+	var lines []string
+	displayEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
+	if displayEvent == nil {
+		lines = append(lines, swiftBarEscaper.Replace(i18n.T("No upcoming meetings")), "---")
+	} else {
+		output := generateBarOutput(render.Plain, displayEvent, cfg.Display == DisplayRemaining)
+		lines = append(lines, swiftBarEscaper.Replace(output.Text), "---")
+		if link, err := MeetingLink(*displayEvent); err == nil {
+			lines = append(lines, swiftBarItem(i18n.T("Join %s", displayEvent.Subject), link), "---")
+		}
+	}
+
+	lines = append(lines, "📅 "+swiftBarEscaper.Replace(i18n.T("Today's Schedule")))
+	if len(todaysEvents) == 0 {
+		lines = append(lines, swiftBarEscaper.Replace(i18n.T("No meetings today")))
+	}
+	conflicts := calendar.ConflictsWithPrevious(todaysEvents)
+	for i, event := range todaysEvents {
+		if conflicts[i] {
+			lines = append(lines, swiftBarEscaper.Replace(render.ConflictMarker(render.Plain)))
+		}
+		link, _ := MeetingLink(event)
+		lines = append(lines, swiftBarItem(render.EventLine(render.Plain, event, render.TimeRange(event)), link))
+	}
+
+	lines = append(lines, "---", i18n.T("Refresh")+" | refresh=true")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// swiftBarItem renders a menu line that opens link when clicked, or plain
+// text without a link
+func swiftBarItem(text, link string) string {
+	text = swiftBarEscaper.Replace(text)
+	if link == "" {
+		return text
+	}
+	return text + " | href=" + link
+}
+
+// swiftBarOutput renders an output built for waybar, e.g. an error, as a
+// SwiftBar plugin's bar line and menu
+func swiftBarOutput(output WaybarOutput) string {
+	lines := []string{swiftBarEscaper.Replace(html.UnescapeString(output.Text))}
+	if output.Tooltip != "" {
+		lines = append(lines, "---")
+		for _, line := range strings.Split(html.UnescapeString(output.Tooltip), "\n") {
+			lines = append(lines, swiftBarEscaper.Replace(line))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// applySwiftBarSnooze adds the snooze notice to a SwiftBar plugin's menu
+func applySwiftBarSnooze(out string) string {
+	until, ok := snooze.Active()
+	if !ok {
+		return out
+	}
+	return out + "---\n🔕 " + swiftBarEscaper.Replace(i18n.T("Snoozed until %s", i18n.FormatTime(until))) + "\n"
+}
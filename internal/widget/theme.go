@@ -0,0 +1,318 @@
+package widget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/render"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme colors by role. The *_text roles color the text on the status
+// badges of the same name.
+const (
+	ColorAccent       = "accent"
+	ColorMuted        = "muted"
+	ColorDim          = "dim"
+	ColorError        = "error"
+	ColorUrgent       = "urgent"
+	ColorUrgentText   = "urgent_text"
+	ColorSoon         = "soon"
+	ColorSoonText     = "soon_text"
+	ColorUpcoming     = "upcoming"
+	ColorUpcomingText = "upcoming_text"
+	ColorCurrent      = "current"
+	ColorCurrentText  = "current_text"
+)
+
+// ColorRoles lists every role a theme colors, for validating settings
+var ColorRoles = []string{
+	ColorAccent, ColorMuted, ColorDim, ColorError,
+	ColorUrgent, ColorUrgentText, ColorSoon, ColorSoonText,
+	ColorUpcoming, ColorUpcomingText, ColorCurrent, ColorCurrentText,
+}
+
+// Theme maps each color role to a color lipgloss understands: "#RRGGBB" or
+// an ANSI color number
+type Theme map[string]string
+
+// Built-in themes
+var (
+	DarkTheme = Theme{
+		ColorAccent:       "#0078D4",
+		ColorMuted:        "#888888",
+		ColorDim:          "#666666",
+		ColorError:        "#FF0000",
+		ColorUrgent:       "#FF0000",
+		ColorUrgentText:   "#FFFFFF",
+		ColorSoon:         "#FFA500",
+		ColorSoonText:     "#000000",
+		ColorUpcoming:     "#0080FF",
+		ColorUpcomingText: "#FFFFFF",
+		ColorCurrent:      "#00FF00",
+		ColorCurrentText:  "#FFFFFF",
+	}
+	LightTheme = Theme{
+		ColorAccent:       "#005A9E",
+		ColorMuted:        "#5C5C5C",
+		ColorDim:          "#8A8A8A",
+		ColorError:        "#C50F1F",
+		ColorUrgent:       "#C50F1F",
+		ColorUrgentText:   "#FFFFFF",
+		ColorSoon:         "#FFB900",
+		ColorSoonText:     "#000000",
+		ColorUpcoming:     "#0063B1",
+		ColorUpcomingText: "#FFFFFF",
+		ColorCurrent:      "#107C10",
+		ColorCurrentText:  "#FFFFFF",
+	}
+)
+
+// Theme names
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+	// ThemeAuto picks dark or light from the terminal's background
+	ThemeAuto = "auto"
+)
+
+var (
+	errorStyle          lipgloss.Style
+	noMeetingStyle      lipgloss.Style
+	urgentStyle         lipgloss.Style
+	soonStyle           lipgloss.Style
+	upcomingStyle       lipgloss.Style
+	currentStyle        lipgloss.Style
+	pastStyle           lipgloss.Style
+	timeStyle           lipgloss.Style
+	titleStyle          lipgloss.Style
+	teamsIndicatorStyle lipgloss.Style
+	dayHeaderStyle      lipgloss.Style
+	locationStyle       lipgloss.Style
+	sectionStyle        lipgloss.Style
+	linkStyle           lipgloss.Style
+
+	// statusStyles are the TUI's status badges
+	statusStyles map[string]lipgloss.Style
+)
+
+func init() {
+	applyTheme(DarkTheme)
+}
+
+// SetTheme builds the terminal styles from settings: a base theme, colors
+// imported from a pywal or base16 palette on top of it, then single overrides
+func SetTheme(settings config.ThemeConfig) error {
+	theme := Theme{}
+	switch settings.Name {
+	case "", ThemeDark:
+		theme.merge(DarkTheme)
+	case ThemeLight:
+		theme.merge(LightTheme)
+	case ThemeAuto:
+		if lipgloss.HasDarkBackground() {
+			theme.merge(DarkTheme)
+		} else {
+			theme.merge(LightTheme)
+		}
+	default:
+		applyTheme(DarkTheme)
+		return fmt.Errorf("unknown theme %q, expected dark, light or auto", settings.Name)
+	}
+
+	var errs []error
+	if settings.Palette != "" {
+		palette, err := LoadPalette(settings.Palette)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		theme.merge(palette)
+	}
+
+	for role, color := range settings.Colors {
+		if !slices.Contains(ColorRoles, role) {
+			errs = append(errs, fmt.Errorf("unknown theme color %q, expected one of %s", role, strings.Join(ColorRoles, ", ")))
+			continue
+		}
+		theme[role] = color
+	}
+
+	applyTheme(theme)
+	return errors.Join(errs...)
+}
+
+func (t Theme) merge(other Theme) {
+	for role, color := range other {
+		t[role] = color
+	}
+}
+
+func applyTheme(t Theme) {
+	color := func(role string) lipgloss.Color { return lipgloss.Color(t[role]) }
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(color(ColorError)).
+		Bold(true)
+
+	noMeetingStyle = lipgloss.NewStyle().
+		Foreground(color(ColorDim)).
+		Italic(true)
+
+	urgentStyle = lipgloss.NewStyle().
+		Foreground(color(ColorUrgentText)).
+		Background(color(ColorUrgent)).
+		Bold(true).
+		Padding(0, 1)
+
+	soonStyle = lipgloss.NewStyle().
+		Foreground(color(ColorSoonText)).
+		Background(color(ColorSoon)).
+		Bold(true).
+		Padding(0, 1)
+
+	upcomingStyle = lipgloss.NewStyle().
+		Foreground(color(ColorUpcomingText)).
+		Background(color(ColorUpcoming)).
+		Padding(0, 1)
+
+	currentStyle = lipgloss.NewStyle().
+		Foreground(color(ColorCurrentText)).
+		Background(color(ColorCurrent)).
+		Bold(true).
+		Padding(0, 1)
+
+	pastStyle = lipgloss.NewStyle().
+		Foreground(color(ColorDim)).
+		Strikethrough(true)
+
+	timeStyle = lipgloss.NewStyle().
+		Foreground(color(ColorMuted)).
+		MarginRight(1)
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true)
+
+	teamsIndicatorStyle = lipgloss.NewStyle().
+		Foreground(color(ColorAccent)).
+		Bold(true)
+
+	dayHeaderStyle = lipgloss.NewStyle().
+		Foreground(color(ColorAccent)).
+		Bold(true).
+		Underline(true)
+
+	locationStyle = lipgloss.NewStyle().
+		Foreground(color(ColorMuted)).
+		Italic(true)
+
+	sectionStyle = lipgloss.NewStyle().
+		Foreground(color(ColorAccent)).
+		Bold(true)
+
+	linkStyle = lipgloss.NewStyle().
+		Foreground(color(ColorMuted)).
+		Underline(true)
+
+	statusStyles = map[string]lipgloss.Style{
+		calendar.StatusUrgent:   urgentStyle,
+		calendar.StatusSoon:     soonStyle,
+		calendar.StatusCurrent:  currentStyle,
+		calendar.StatusUpcoming: upcomingStyle,
+		calendar.StatusPast:     pastStyle,
+	}
+
+	foreground := func(role string) lipgloss.Style { return lipgloss.NewStyle().Foreground(color(role)) }
+	render.SetStyles(render.Styles{
+		Bold: titleStyle,
+		Time: timeStyle,
+		Dim:  foreground(ColorMuted),
+		Status: map[string]lipgloss.Style{
+			calendar.StatusUrgent:   foreground(ColorUrgent),
+			calendar.StatusSoon:     foreground(ColorSoon),
+			calendar.StatusCurrent:  foreground(ColorCurrent),
+			calendar.StatusUpcoming: foreground(ColorUpcoming),
+			calendar.StatusPast:     foreground(ColorDim),
+		},
+	})
+}
+
+// base16Regex matches "base0D: "7cafc2"" lines of a base16 scheme
+var base16Regex = regexp.MustCompile(`(?m)^\s*(base0[0-9A-Fa-f])\s*:\s*["']?#?([0-9A-Fa-f]{6})["']?`)
+
+// LoadPalette reads a pywal colors.json (e.g. ~/.cache/wal/colors.json) or
+// a base16 scheme (YAML) and maps its colors onto theme roles
+func LoadPalette(path string) (Theme, error) {
+	if strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		path = filepath.Join(homeDir, path[2:])
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read palette: %w", err)
+	}
+
+	var wal struct {
+		Special map[string]string `json:"special"`
+		Colors  map[string]string `json:"colors"`
+	}
+	if json.Unmarshal(data, &wal) == nil && len(wal.Colors) > 0 {
+		c := func(n int) string { return wal.Colors[fmt.Sprintf("color%d", n)] }
+		background := wal.Special["background"]
+		return Theme{
+			ColorAccent:       c(6),
+			ColorMuted:        c(7),
+			ColorDim:          c(8),
+			ColorError:        c(1),
+			ColorUrgent:       c(1),
+			ColorUrgentText:   background,
+			ColorSoon:         c(3),
+			ColorSoonText:     background,
+			ColorUpcoming:     c(4),
+			ColorUpcomingText: background,
+			ColorCurrent:      c(2),
+			ColorCurrentText:  background,
+		}.withoutEmpty(), nil
+	}
+
+	base := map[string]string{}
+	for _, match := range base16Regex.FindAllStringSubmatch(string(data), -1) {
+		base[strings.ToUpper(match[1][4:])] = "#" + match[2]
+	}
+	if len(base) == 0 {
+		return nil, fmt.Errorf("%s is neither a pywal colors.json nor a base16 scheme", path)
+	}
+	return Theme{
+		ColorAccent:       base["0C"],
+		ColorMuted:        base["04"],
+		ColorDim:          base["03"],
+		ColorError:        base["08"],
+		ColorUrgent:       base["08"],
+		ColorUrgentText:   base["00"],
+		ColorSoon:         base["0A"],
+		ColorSoonText:     base["00"],
+		ColorUpcoming:     base["0D"],
+		ColorUpcomingText: base["00"],
+		ColorCurrent:      base["0B"],
+		ColorCurrentText:  base["00"],
+	}.withoutEmpty(), nil
+}
+
+// withoutEmpty drops roles a palette had no color for, so the base theme
+// keeps them
+func (t Theme) withoutEmpty() Theme {
+	for role, color := range t {
+		if color == "" {
+			delete(t, role)
+		}
+	}
+	return t
+}
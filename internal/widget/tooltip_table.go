@@ -0,0 +1,156 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxTitleWidth keeps long subjects from stretching the table
+const maxTitleWidth = 32
+
+// scheduleTooltip returns the table tooltip when configured, otherwise the
+// given list tooltip
+func scheduleTooltip(settings *config.Config, listTooltip string, todaysEvents, upcomingEvents []calendar.Event) string {
+	if settings == nil || settings.Tooltip.Style != config.TooltipTable {
+		return listTooltip
+	}
+	return generateTableTooltip(todaysEvents, upcomingEvents, settings.Tooltip.MaxRows)
+}
+
+// generateTableTooltip renders today's events followed by upcoming days as
+// aligned columns (time, duration, title, location) under per-day headers.
+// The table is wrapped in <tt> so waybar draws it in a monospace font.
+func generateTableTooltip(todaysEvents, upcomingEvents []calendar.Event, maxRows int) string {
+	now := calendar.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+
+	events := append([]calendar.Event{}, todaysEvents...)
+	for _, event := range upcomingEvents {
+		if !event.Start.Before(tomorrow) {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) == 0 {
+		return "📅 " + render.Pango.Bold(i18n.T("Today's Schedule")+":") + "\n\n" + i18n.T("No meetings today")
+	}
+
+	shown := events
+	if maxRows > 0 && len(shown) > maxRows {
+		shown = shown[:maxRows]
+	}
+
+	rows := make([][4]string, len(shown))
+	var widths [4]int
+	for i, event := range shown {
+		rows[i] = tableRow(event)
+		for col, cell := range rows[i] {
+			widths[col] = max(widths[col], lipgloss.Width(cell))
+		}
+	}
+
+	conflicts := calendar.ConflictsWithPrevious(shown)
+
+	var lines []string
+	var currentDay string
+	for i, event := range shown {
+		day := event.Start.Format("2006-01-02")
+		if day != currentDay {
+			if currentDay != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, "📅 "+render.Pango.Bold(render.Pango.Escape(dayHeader(event.Start, now))))
+			currentDay = day
+		}
+
+		if conflicts[i] {
+			lines = append(lines, render.ConflictMarker(render.Pango))
+		}
+
+		cells := make([]string, len(rows[i]))
+		for col, cell := range rows[i] {
+			cells[col] = render.Pango.Escape(padRight(cell, widths[col]))
+		}
+		cells[0] = render.Pango.Dim(cells[0])
+		cells[1] = render.Pango.Dim(cells[1])
+		// Strike only the subject, not the padding after it
+		subject := render.Pango.Cancelled(&event, render.Pango.Escape(rows[i][2]))
+		cells[2] = render.Pango.Category(event.Color, render.Pango.Bold(subject)) + strings.Repeat(" ", widths[2]-lipgloss.Width(rows[i][2]))
+
+		line := render.Pango.Icon(event.GetStatus()) + " " + strings.Join(cells, "  ")
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+
+	if len(events) > len(shown) {
+		lines = append(lines, "", i18n.T("... and %d more events", len(events)-len(shown)))
+	}
+
+	return "<tt>" + strings.Join(lines, "\n") + "</tt>"
+}
+
+// tableRow returns the raw (unescaped) cells for an event
+func tableRow(event calendar.Event) [4]string {
+	timeStr := fmt.Sprintf("%s-%s", i18n.FormatTime(event.Start), i18n.FormatTime(event.End))
+	if event.IsAllDay {
+		timeStr = i18n.T("All day")
+	}
+
+	title := truncate(event.Subject, maxTitleWidth)
+	if details := render.Details(event); details != "" {
+		title = title + " " + details
+	}
+	if event.HasDocuments() {
+		title = title + " " + render.AttachmentIndicator
+	}
+	if event.Change != nil {
+		title = title + " " + render.UpdatedBadge()
+	}
+
+	location := event.Location
+	if event.IsTeams {
+		location = "Teams"
+	}
+
+	return [4]string{timeStr, formatDuration(event.End.Sub(event.Start)), title, location}
+}
+
+// dayHeader names the day an event falls on relative to now
+func dayHeader(t, now time.Time) string {
+	switch t.Format("2006-01-02") {
+	case now.Format("2006-01-02"):
+		return i18n.T("Today's Schedule")
+	case now.AddDate(0, 0, 1).Format("2006-01-02"):
+		return i18n.T("Tomorrow")
+	default:
+		return i18n.FormatDate(t)
+	}
+}
+
+// formatDuration renders a meeting length as "30m", "1h" or "1h30m"
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+func padRight(s string, width int) string {
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
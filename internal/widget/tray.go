@@ -0,0 +1,297 @@
+//go:build linux || windows || (darwin && cgo)
+
+package widget
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+
+	"fyne.io/systray"
+)
+
+// trayRefreshInterval is how often the tray re-reads the events; the
+// countdown in between is computed from the events it has
+const trayRefreshInterval = time.Minute
+
+// trayIconSize is the edge of the generated tray icon in pixels
+const trayIconSize = 32
+
+// RunTray shows the next meeting as a tray icon colored by its status, with
+// the countdown as tooltip and today's meetings in its menu; clicking one
+// joins it. On Linux the icon is a StatusNotifierItem, shown by KDE and by
+// GNOME with the AppIndicator extension. It blocks until Quit is clicked.
+// Events come from the daemon's cache when it is fresh.
+func (w *Widget) RunTray() error {
+	systray.Run(func() {
+		t := &trayState{
+			widget:   w,
+			settings: w.config.Settings,
+			refresh:  make(chan struct{}, 1),
+		}
+		if t.settings == nil {
+			t.settings = config.Default()
+		}
+		go t.run()
+	}, nil)
+	return nil
+}
+
+// trayState is only touched by the run goroutine; menu clicks reach it
+// through the refresh channel or open meetings themselves
+type trayState struct {
+	widget   *Widget
+	settings *config.Config
+
+	todaysEvents   []calendar.Event
+	upcomingEvents []calendar.Event
+	err            error
+
+	// refresh asks for events from the calendar rather than the cache
+	refresh chan struct{}
+	// menuKey identifies the current menu, so it is only rebuilt when it
+	// changes; menuDone stops the click listeners of the current menu
+	menuKey  string
+	menuDone chan struct{}
+	// iconColor and tooltip are what the tray shows now
+	iconColor string
+	tooltip   string
+}
+
+func (t *trayState) run() {
+	t.load(false)
+	t.update()
+
+	// The countdown shows seconds in its last minutes
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	lastLoad := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			if time.Since(lastLoad) >= trayRefreshInterval {
+				t.load(false)
+				lastLoad = time.Now()
+			}
+		case <-t.refresh:
+			t.load(true)
+			lastLoad = time.Now()
+		}
+		t.update()
+	}
+}
+
+// load fetches the events, from the daemon's cache unless forceRefresh is
+// set or the cache is missing or old
+func (t *trayState) load(forceRefresh bool) {
+	if !forceRefresh {
+		if todaysEvents, upcomingEvents, ok := cachedEvents(); ok {
+			t.todaysEvents, t.upcomingEvents, t.err = redactPrivate(todaysEvents), redactPrivate(upcomingEvents), nil
+			return
+		}
+	}
+
+	service, err := t.widget.provider()
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	upcomingEvents, err := service.GetUpcomingEvents(ctx)
+	if err != nil {
+		t.fail(fmt.Errorf("failed to get upcoming events: %w", err))
+		return
+	}
+	todaysEvents, _ := service.GetTodaysEvents(ctx)
+	t.todaysEvents, t.upcomingEvents, t.err = redactPrivate(todaysEvents), redactPrivate(upcomingEvents), nil
+}
+
+// fail keeps the events already shown, so a blip doesn't empty the menu
+func (t *trayState) fail(err error) {
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	t.err = err
+}
+
+// update shows the next meeting in the icon and tooltip and rebuilds the
+// menu when today's meetings changed
+func (t *trayState) update() {
+	displayEvent := calendar.SelectNextMeeting(t.upcomingEvents, calendar.Now(), selection.Display)
+
+	status := calendar.StatusPast
+	tooltip := i18n.T("No upcoming meetings")
+	if displayEvent != nil {
+		status = displayEvent.GetStatus()
+		tooltip = formatCountdown(displayEvent, t.settings.Countdown)
+	}
+	if t.err != nil && t.upcomingEvents == nil {
+		tooltip = i18n.T("Calendar Error")
+	}
+
+	if hex, _ := render.StatusColor(status); hex != t.iconColor {
+		systray.SetIcon(trayIcon(render.StatusColor(status)))
+		t.iconColor = hex
+	}
+	if tooltip != t.tooltip {
+		systray.SetTooltip(tooltip)
+		t.tooltip = tooltip
+	}
+
+	t.updateMenu(displayEvent)
+}
+
+// trayItem is a menu line, opening event when it is set
+type trayItem struct {
+	title string
+	event *calendar.Event
+}
+
+func (t *trayState) updateMenu(displayEvent *calendar.Event) {
+	var items []trayItem
+	if displayEvent != nil {
+		items = append(items, trayItem{i18n.T("Join %s", displayEvent.Subject), displayEvent}, trayItem{})
+	}
+	items = append(items, trayItem{title: "📅 " + i18n.T("Today's Schedule")})
+	if len(t.todaysEvents) == 0 {
+		items = append(items, trayItem{title: i18n.T("No meetings today")})
+	}
+	conflicts := calendar.ConflictsWithPrevious(t.todaysEvents)
+	for i := range t.todaysEvents {
+		if conflicts[i] {
+			items = append(items, trayItem{title: render.ConflictMarker(render.Plain)})
+		}
+		event := &t.todaysEvents[i]
+		items = append(items, trayItem{render.EventLine(render.Plain, *event, render.TimeRange(*event)), event})
+	}
+
+	var key strings.Builder
+	for _, item := range items {
+		key.WriteString(item.title + "\n")
+	}
+	if key.String() == t.menuKey {
+		return
+	}
+	t.menuKey = key.String()
+	t.buildMenu(items)
+}
+
+// buildMenu replaces the menu with items, a separator for each empty one,
+// followed by refresh and quit
+func (t *trayState) buildMenu(items []trayItem) {
+	if t.menuDone != nil {
+		close(t.menuDone)
+	}
+	systray.ResetMenu()
+	done := make(chan struct{})
+	t.menuDone = done
+
+	for _, item := range items {
+		if item.title == "" {
+			systray.AddSeparator()
+			continue
+		}
+		menuItem := systray.AddMenuItem(item.title, "")
+		if item.event == nil {
+			menuItem.Disable()
+			continue
+		}
+		if _, err := MeetingLink(*item.event); err != nil {
+			menuItem.Disable()
+			continue
+		}
+		go openOnClick(menuItem, *item.event, done)
+	}
+
+	systray.AddSeparator()
+	refreshItem := systray.AddMenuItem(i18n.T("Refresh"), "")
+	quitItem := systray.AddMenuItem(i18n.T("Quit"), "")
+	go func() {
+		for {
+			select {
+			case <-refreshItem.ClickedCh:
+				select {
+				case t.refresh <- struct{}{}:
+				default:
+				}
+			case <-quitItem.ClickedCh:
+				systray.Quit()
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// openOnClick opens event each time item is clicked, until done is closed
+func openOnClick(item *systray.MenuItem, event calendar.Event, done <-chan struct{}) {
+	for {
+		select {
+		case <-item.ClickedCh:
+			if err := OpenMeeting(event); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to open meeting: %v\n", err)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// trayIcon draws a filled circle in a "#rrggbb" color. Windows wants the
+// icon as an ICO file; the other platforms take the PNG itself.
+func trayIcon(hex string, ok bool) []byte {
+	fill := color.RGBA{0x6c, 0x70, 0x86, 0xff}
+	if ok && len(hex) == 7 {
+		if v, err := strconv.ParseUint(hex[1:], 16, 32); err == nil {
+			fill = color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+	center := float64(trayIconSize-1) / 2
+	radius := float64(trayIconSize)/2 - 1
+	for y := 0; y < trayIconSize; y++ {
+		for x := 0; x < trayIconSize; x++ {
+			dx, dy := float64(x)-center, float64(y)-center
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(x, y, fill)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	if runtime.GOOS != "windows" {
+		return buf.Bytes()
+	}
+	return pngToICO(buf.Bytes(), trayIconSize)
+}
+
+// pngToICO wraps a square PNG in an ICO file, which may hold PNG data as is
+func pngToICO(data []byte, size int) []byte {
+	var ico bytes.Buffer
+	// ICONDIR: reserved, type 1 (icon), one image
+	binary.Write(&ico, binary.LittleEndian, [3]uint16{0, 1, 1})
+	// ICONDIRENTRY: width, height, palette size, reserved, color planes,
+	// bits per pixel, data size and the offset of the data after the
+	// 6 byte header and this 16 byte entry
+	ico.Write([]byte{byte(size), byte(size), 0, 0})
+	binary.Write(&ico, binary.LittleEndian, [2]uint16{1, 32})
+	binary.Write(&ico, binary.LittleEndian, [2]uint32{uint32(len(data)), 22})
+	ico.Write(data)
+	return ico.Bytes()
+}
@@ -0,0 +1,19 @@
+//go:build !linux && !windows && !(darwin && cgo)
+
+package widget
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// RunTray reports that there is no tray icon in this build. It needs the
+// StatusNotifierItem D-Bus API on Linux, the Windows shell, or Cocoa through
+// cgo on macOS.
+func (w *Widget) RunTray() error {
+	if runtime.GOOS == "darwin" {
+		return errors.New("tray needs a cgo build on macOS")
+	}
+	return fmt.Errorf("tray isn't supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,40 @@
+package widget
+
+import "github.com/mattn/go-runewidth"
+
+// DefaultMaxSubjectWidth is how many columns of a subject the bar shows
+const DefaultMaxSubjectWidth = 45
+
+// maxCompactTitle keeps subjects short in the TUI's compact view
+const maxCompactTitle = 30
+
+// DefaultEllipsis marks a truncated subject
+const DefaultEllipsis = "..."
+
+var (
+	maxSubjectWidth = DefaultMaxSubjectWidth
+	ellipsis        = DefaultEllipsis
+)
+
+// SetTruncation sets the width subjects in the bar are cut to, ellipsis
+// included, and the ellipsis itself (e.g. "…", or "" for none). Zero or less
+// keeps the default width.
+func SetTruncation(maxWidth int, mark string) {
+	if maxWidth <= 0 {
+		maxWidth = DefaultMaxSubjectWidth
+	}
+	maxSubjectWidth = maxWidth
+	ellipsis = mark
+}
+
+// truncate cuts s to at most width terminal columns, ending in the
+// ellipsis. It never splits a character, and wide characters such as emoji
+// and CJK count as two columns.
+func truncate(s string, width int) string {
+	return runewidth.Truncate(s, width, ellipsis)
+}
+
+// textWidth is the number of terminal columns s takes up
+func textWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
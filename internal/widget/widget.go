@@ -1,28 +1,105 @@
 package widget
 
 import (
+	"calendar-widget/internal/cache"
 	"calendar-widget/internal/calendar"
+	"calendar-widget/internal/clipboard"
+	"calendar-widget/internal/config"
+	"calendar-widget/internal/i18n"
+	"calendar-widget/internal/notes"
+	"calendar-widget/internal/opener"
+	"calendar-widget/internal/render"
+	"calendar-widget/internal/selection"
+	"calendar-widget/internal/snooze"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
-	"runtime"
+	"os"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 type Config struct {
 	RefreshInterval int
-	Compact         bool
-	Debug           bool
+	// Layout is the TUI's layout preset (micro, compact, full or "" for standard)
+	Layout      string
+	Debug       bool
+	Display     string
+	MinFreeSlot time.Duration
+	// ChipsWithin and ChipSlot configure the chips display mode. A slot
+	// below zero prints every chip as a JSON array.
+	ChipsWithin time.Duration
+	ChipSlot    int
+	// Format is the output format of the waybar command, FormatWaybar
+	// when empty
+	Format   string
+	Settings *config.Config
+}
+
+// maxEvents returns the configured per-query event cap (0 keeps the default)
+func (c *Config) maxEvents() int {
+	if c.Settings == nil {
+		return 0
+	}
+	return c.Settings.MaxEvents
+}
+
+// notes returns the configured meeting notes template
+func (c *Config) notes() config.NotesConfig {
+	if c.Settings == nil {
+		return config.NotesConfig{}
+	}
+	return c.Settings.Notes
+}
+
+// Waybar display modes
+const (
+	DisplayNext      = "next"
+	DisplayFreeSlot  = "freeslot"
+	DisplayCountdown = "countdown"
+	DisplayMulti     = "multi"
+	DisplayCount     = "count"
+	DisplayChips     = "chips"
+	// DisplayRemaining is next, but a running meeting shows its time left
+	DisplayRemaining = "remaining"
+)
+
+// Output formats of the waybar command
+const (
+	FormatWaybar = "waybar"
+	// FormatSwiftBar is the plugin output of SwiftBar and xbar on macOS
+	FormatSwiftBar = "swiftbar"
+	// FormatEww is JSON with today's events for eww widgets
+	FormatEww = "eww"
+	// FormatTmux is one line with tmux styles for its status bar
+	FormatTmux = "tmux"
+	// FormatZellij is one line with ANSI colors for zellij's zjstatus
+	FormatZellij = "zellij"
+)
+
+// ValidateFormat checks an output format and the display mode it is used
+// with. SwiftBar plugins, eww widgets and multiplexer status lines get the
+// next meeting; the other displays are waybar's.
+func ValidateFormat(format, display string) error {
+	switch format {
+	case FormatWaybar:
+		return nil
+	case FormatSwiftBar, FormatEww, FormatTmux, FormatZellij:
+		if display != DisplayNext && display != DisplayRemaining {
+			return fmt.Errorf("--format %s supports --display next and remaining, not %s", format, display)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q, expected waybar, swiftbar, eww, tmux or zellij", format)
 }
 
 type Widget struct {
-	config          *Config
-	calendarService *calendar.CalendarService
+	config           *Config
+	allowInteractive bool
+	// calendarService is created on first use, see provider
+	calendarService calendar.CalendarProvider
 }
 
 type model struct {
@@ -31,7 +108,12 @@ type model struct {
 	lastUpdate  time.Time
 	err         error
 	config      *Config
-	service     *calendar.CalendarService
+	service     calendar.CalendarProvider
+	// detail shows the next meeting's detail pane, scrolled down by detailOffset lines
+	detail       bool
+	detailOffset int
+	width        int
+	height       int
 }
 
 type tickMsg time.Time
@@ -39,47 +121,67 @@ type eventsMsg []calendar.Event
 type meetingMsg *calendar.Event
 type errMsg error
 
-func NewWidget(config *Config) (*Widget, error) {
+func NewWidget(config *Config) *Widget {
 	return NewWidgetWithOptions(config, true)
 }
 
-func NewWidgetWithOptions(config *Config, allowInteractive bool) (*Widget, error) {
-	calendarService, err := calendar.NewCalendarServiceWithOptions(allowInteractive)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create calendar service: %w", err)
+// NewWidgetWithOptions creates a widget. The calendar provider is only
+// created once events have to be fetched, so answering from the daemon's
+// cache doesn't pay for loading the token cache and building the Graph client.
+func NewWidgetWithOptions(config *Config, allowInteractive bool) *Widget {
+	return &Widget{
+		config:           config,
+		allowInteractive: allowInteractive,
 	}
+}
 
-	return &Widget{
-		config:          config,
-		calendarService: calendarService,
-	}, nil
+// provider returns the calendar provider, creating it on first use
+func (w *Widget) provider() (calendar.CalendarProvider, error) {
+	if w.calendarService == nil {
+		calendarService, err := calendar.NewProvider(w.allowInteractive, false, w.config.maxEvents())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create calendar service: %w", err)
+		}
+		w.calendarService = calendarService
+	}
+	return w.calendarService, nil
 }
 
-func (w *Widget) GetCalendarService() *calendar.CalendarService {
-	return w.calendarService
+func (w *Widget) GetCalendarService() (calendar.CalendarProvider, error) {
+	return w.provider()
 }
 
 func (w *Widget) Run() error {
-	p := tea.NewProgram(initialModel(w.config, w.calendarService), tea.WithAltScreen())
-	_, err := p.Run()
+	service, err := w.provider()
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(initialModel(w.config, service), tea.WithAltScreen())
+	_, err = p.Run()
 	return err
 }
 
 func (w *Widget) ShowTooltip() error {
 	ctx := context.Background()
 
+	service, err := w.provider()
+	if err != nil {
+		return err
+	}
+
 	// Get both today's events and upcoming events
-	todaysEvents, err := w.calendarService.GetTodaysEvents(ctx)
+	todaysEvents, err := service.GetTodaysEvents(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get today's events: %w", err)
 	}
 
-	upcomingEvents, err := w.calendarService.GetUpcomingEvents(ctx)
+	upcomingEvents, err := service.GetUpcomingEvents(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get upcoming events: %w", err)
 	}
 
-	fmt.Print(renderExtendedTooltip(todaysEvents, upcomingEvents))
+	fmt.Print(RenderTooltip(todaysEvents, upcomingEvents))
 	return nil
 }
 
@@ -92,49 +194,67 @@ func (w *Widget) RunWaybarWithRefresh(forceRefresh bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// A recent snapshot from the daemon answers without building a client
+	if !forceRefresh {
+		if todaysEvents, upcomingEvents, ok := cachedEvents(); ok {
+			w.printEvents(todaysEvents, upcomingEvents)
+			return nil
+		}
+	}
+
 	// Use service with force refresh if requested
-	service := w.calendarService
+	var service calendar.CalendarProvider
+	var err error
 	if forceRefresh {
 		// Create a new service with force refresh enabled
-		refreshService, err := calendar.NewCalendarServiceWithRefresh(true, true)
-		if err != nil {
-			output := WaybarOutput{
-				Text:    "Auth Error",
-				Class:   "error",
-				Alt:     "auth-error",
-				Tooltip: "Failed to create calendar service",
-			}
-			jsonBytes, _ := json.Marshal(output)
-			fmt.Println(string(jsonBytes))
-			return nil
+		service, err = calendar.NewProvider(true, true, w.config.maxEvents())
+	} else {
+		service, err = w.provider()
+	}
+	if err != nil {
+		output := WaybarOutput{
+			Text:    i18n.T("Auth Error"),
+			Class:   "error",
+			Alt:     "auth-error",
+			Tooltip: i18n.T("Failed to create calendar service"),
 		}
-		service = refreshService
+		w.printOutput(output)
+		return nil
 	}
 
 	// Get upcoming events for main display
 	upcomingEvents, err := service.GetUpcomingEvents(ctx)
 	if err != nil {
+		if calendar.IsRateLimited(err) {
+			output := WaybarOutput{
+				Text:    i18n.T("Rate Limited"),
+				Class:   "rate-limited",
+				Alt:     "rate-limited",
+				Tooltip: i18n.T("Microsoft Graph is throttling requests, will retry on the next refresh"),
+			}
+			w.printOutput(output)
+			return nil
+		}
+
 		// Check if this is an authentication error
 		if strings.Contains(err.Error(), "authentication") ||
 			strings.Contains(err.Error(), "token") ||
 			strings.Contains(err.Error(), "login") {
 			output := WaybarOutput{
-				Text:    "Auth Required",
+				Text:    i18n.T("Auth Required"),
 				Class:   "error",
 				Alt:     "auth-required",
-				Tooltip: "Click to authenticate",
+				Tooltip: i18n.T("Click to authenticate"),
 			}
-			jsonBytes, _ := json.Marshal(output)
-			fmt.Println(string(jsonBytes))
+			w.printOutput(output)
 		} else {
 			output := WaybarOutput{
-				Text:    "Calendar Error",
+				Text:    i18n.T("Calendar Error"),
 				Class:   "error",
 				Alt:     "error",
-				Tooltip: err.Error(),
+				Tooltip: render.Pango.Escape(err.Error()),
 			}
-			jsonBytes, _ := json.Marshal(output)
-			fmt.Println(string(jsonBytes))
+			w.printOutput(output)
 		}
 		return nil
 	}
@@ -142,29 +262,133 @@ func (w *Widget) RunWaybarWithRefresh(forceRefresh bool) error {
 	// Get today's events for tooltip
 	todaysEvents, _ := service.GetTodaysEvents(ctx)
 
+	w.printEvents(todaysEvents, upcomingEvents)
+	return nil
+}
+
+// cachedEvents returns today's and the upcoming events from the daemon's
+// snapshot when it is recent enough to answer from. Fake data always comes
+// from its provider.
+func cachedEvents() ([]calendar.Event, []calendar.Event, bool) {
+	if os.Getenv(calendar.FakeDataEnv) != "" {
+		return nil, nil, false
+	}
+	snapshot, err := cache.Load()
+	if err != nil || snapshot == nil {
+		return nil, nil, false
+	}
+	return snapshot.Events(calendar.Now(), staleCacheAge)
+}
+
+// printEvents prints the waybar output for the configured display mode
+func (w *Widget) printEvents(todaysEvents, upcomingEvents []calendar.Event) {
+	if w.config.Format == FormatSwiftBar {
+		fmt.Print(applySwiftBarSnooze(RenderSwiftBar(w.config, todaysEvents, upcomingEvents)))
+		return
+	}
+	if w.config.Format == FormatEww {
+		jsonBytes, _ := json.Marshal(applyEwwSnooze(RenderEww(w.config, todaysEvents, upcomingEvents)))
+		fmt.Println(string(jsonBytes))
+		return
+	}
+	if w.config.Format == FormatTmux || w.config.Format == FormatZellij {
+		t := statusLineTarget(w.config.Format)
+		fmt.Println(applyStatusLineSnooze(t, RenderStatusLine(t, w.config, upcomingEvents)))
+		return
+	}
+	if w.config.Display == DisplayChips {
+		if w.config.ChipSlot >= 0 {
+			w.printOutput(applySnooze(RenderChip(upcomingEvents, w.config.ChipsWithin, w.config.ChipSlot)))
+			return
+		}
+		chips := RenderChips(upcomingEvents, w.config.ChipsWithin)
+		for i := range chips {
+			chips[i] = applySnooze(chips[i])
+		}
+		jsonBytes, _ := json.Marshal(chips)
+		fmt.Println(string(jsonBytes))
+		return
+	}
+
+	w.printOutput(applySnooze(RenderWaybar(w.config, todaysEvents, upcomingEvents)))
+}
+
+// printOutput prints one waybar output. In the chips mode without a slot,
+// errors are wrapped in an array so wrappers always get the same shape.
+func (w *Widget) printOutput(output WaybarOutput) {
+	if w.config.Format == FormatSwiftBar {
+		fmt.Print(swiftBarOutput(output))
+		return
+	}
+	if w.config.Format == FormatEww {
+		jsonBytes, _ := json.Marshal(ewwOutput(output))
+		fmt.Println(string(jsonBytes))
+		return
+	}
+	if w.config.Format == FormatTmux || w.config.Format == FormatZellij {
+		fmt.Println(statusLine(statusLineTarget(w.config.Format), output))
+		return
+	}
+	var jsonBytes []byte
+	if w.config.Display == DisplayChips && w.config.ChipSlot < 0 {
+		jsonBytes, _ = json.Marshal([]WaybarOutput{output})
+	} else {
+		jsonBytes, _ = json.Marshal(output)
+	}
+	fmt.Println(string(jsonBytes))
+}
+
+// RenderWaybar builds the waybar output for the configured display mode from
+// already fetched events. It does no I/O, so the result only depends on the
+// events, settings and the calendar package clock.
+func RenderWaybar(cfg *Config, todaysEvents, upcomingEvents []calendar.Event) WaybarOutput {
+	todaysEvents = redactPrivate(todaysEvents)
+	upcomingEvents = redactPrivate(upcomingEvents)
+
+	settings := cfg.Settings
+	if settings == nil {
+		settings = config.Default()
+	}
+
+	switch cfg.Display {
+	case DisplayFreeSlot:
+		output := generateFreeSlotOutput(todaysEvents, cfg.MinFreeSlot)
+		output.Tooltip = scheduleTooltip(settings, output.Tooltip, todaysEvents, upcomingEvents)
+		return output
+	case DisplayMulti:
+		output := generateMultiOutput(upcomingEvents, todaysEvents, settings.NextMeetings)
+		output.Tooltip = scheduleTooltip(settings, output.Tooltip, todaysEvents, upcomingEvents)
+		return output
+	case DisplayCount:
+		output := generateCountOutput(todaysEvents)
+		output.Tooltip = scheduleTooltip(settings, output.Tooltip, todaysEvents, upcomingEvents)
+		return output
+	}
+
 	// Find the most relevant upcoming meeting to display with blocking priority
-	displayEvent := selectBestEvent(upcomingEvents)
+	displayEvent := calendar.SelectNextMeeting(upcomingEvents, calendar.Now(), selection.Display)
 
 	if displayEvent == nil {
-		output := WaybarOutput{
-			Text:    "No upcoming meetings",
+		return WaybarOutput{
+			Text:    i18n.T("No upcoming meetings"),
 			Class:   "no-meeting",
 			Alt:     "no-meeting",
-			Tooltip: generateTooltipForSchedule(todaysEvents),
+			Tooltip: scheduleTooltip(settings, generateTooltipForSchedule(todaysEvents), todaysEvents, upcomingEvents),
 		}
-		jsonBytes, _ := json.Marshal(output)
-		fmt.Println(string(jsonBytes))
-		return nil
 	}
 
-	output := generateWaybarOutputForSchedule(displayEvent, todaysEvents)
-	jsonBytes, _ := json.Marshal(output)
-	fmt.Println(string(jsonBytes))
+	output := generateWaybarOutputForSchedule(displayEvent, todaysEvents, cfg.Display == DisplayRemaining)
+	output.Tooltip = scheduleTooltip(settings, output.Tooltip, todaysEvents, upcomingEvents)
+	markBackToBack(&output, displayEvent, upcomingEvents)
+	return output
+}
 
-	return nil
+// RenderTooltip renders the extended tooltip shown by the tooltip command
+func RenderTooltip(todaysEvents, upcomingEvents []calendar.Event) string {
+	return renderExtendedTooltip(redactPrivate(todaysEvents), redactPrivate(upcomingEvents))
 }
 
-func initialModel(config *Config, service *calendar.CalendarService) model {
+func initialModel(config *Config, service calendar.CalendarProvider) model {
 	return model{
 		config:  config,
 		service: service,
@@ -181,17 +405,43 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.detail && m.nextMeeting != nil {
+			return m.updateDetail(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
-		case "enter", " ":
+		case "enter":
+			if m.nextMeeting != nil {
+				m.detail = true
+				m.detailOffset = 0
+			}
+		case " ":
 			if m.nextMeeting != nil {
 				return m, openMeetingCmd(*m.nextMeeting)
 			}
 		case "r":
 			return m, fetchEventsCmd(m.service)
+		case "y":
+			if m.nextMeeting != nil {
+				return m, copyLinkCmd(*m.nextMeeting)
+			}
+		case "o":
+			if m.nextMeeting != nil {
+				if link := m.nextMeeting.OutlookLink(); link != "" {
+					return m, openURLCmd(link)
+				}
+			}
+		case "n":
+			if m.nextMeeting != nil {
+				return m, openNotesCmd(*m.nextMeeting, m.config.notes())
+			}
 		}
 
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
 	case tea.MouseMsg:
 		if msg.Button == tea.MouseButtonLeft && m.nextMeeting != nil {
 			return m, openMeetingCmd(*m.nextMeeting)
@@ -205,10 +455,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case eventsMsg:
 		m.events = []calendar.Event(msg)
-		m.lastUpdate = time.Now()
+		m.lastUpdate = calendar.Now()
 
+		// The TUI acts on real meetings only: no all-day, free or declined events
 		ctx := context.Background()
-		nextMeeting, _ := m.service.GetNextMeeting(ctx)
+		nextMeeting, _ := m.service.GetNextMeeting(ctx, selection.Meetings)
 		m.nextMeeting = nextMeeting
 
 		return m, nil
@@ -227,14 +478,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	if m.err != nil {
-		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+		return errorStyle.Render(i18n.T("Error: %v", m.err))
 	}
 
 	if m.nextMeeting == nil {
-		return noMeetingStyle.Render("No upcoming meetings")
+		return noMeetingStyle.Render(i18n.T("No upcoming meetings"))
+	}
+
+	if m.detail {
+		return m.viewDetail()
 	}
 
-	return renderMeeting(*m.nextMeeting, m.config.Compact)
+	return renderMeeting(*m.nextMeeting, m.config.Layout)
 }
 
 func tickCmd() tea.Cmd {
@@ -243,7 +498,7 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func fetchEventsCmd(service *calendar.CalendarService) tea.Cmd {
+func fetchEventsCmd(service calendar.CalendarProvider) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -259,145 +514,102 @@ func fetchEventsCmd(service *calendar.CalendarService) tea.Cmd {
 
 func openMeetingCmd(event calendar.Event) tea.Cmd {
 	return func() tea.Msg {
-		if err := openMeeting(event); err != nil {
+		if err := OpenMeeting(event); err != nil {
 			return errMsg(err)
 		}
 		return nil
 	}
 }
 
-func openMeeting(event calendar.Event) error {
-	var url string
-	if event.IsTeams && event.TeamsLink != "" {
-		url = event.TeamsLink
-	} else if event.WebLink != "" {
-		url = event.WebLink
-	} else {
-		return fmt.Errorf("no link available for meeting")
+func copyLinkCmd(event calendar.Event) tea.Cmd {
+	return func() tea.Msg {
+		link := event.GetJoinLink()
+		if link == "" {
+			return errMsg(fmt.Errorf("no join link for %s", event.Subject))
+		}
+		if err := clipboard.Copy(link); err != nil {
+			return errMsg(err)
+		}
+		return nil
 	}
+}
 
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "linux":
-		cmd = exec.Command("xdg-open", url)
-	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-	case "darwin":
-		cmd = exec.Command("open", url)
-	default:
-		return fmt.Errorf("unsupported platform")
+func openNotesCmd(event calendar.Event, notesConfig config.NotesConfig) tea.Cmd {
+	return func() tea.Msg {
+		if err := notes.Open(event, notesConfig, OpenURL); err != nil {
+			return errMsg(err)
+		}
+		return nil
 	}
-
-	return cmd.Start()
 }
 
-var (
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true)
-
-	noMeetingStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Italic(true)
-
-	urgentStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#FF0000")).
-			Bold(true).
-			Padding(0, 1)
-
-	soonStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#000000")).
-			Background(lipgloss.Color("#FFA500")).
-			Bold(true).
-			Padding(0, 1)
-
-	upcomingStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#0080FF")).
-			Padding(0, 1)
-
-	currentStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#00FF00")).
-			Bold(true).
-			Padding(0, 1)
-
-	pastStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#666666")).
-			Strikethrough(true)
+// MeetingLink returns the meeting's join link, or its Outlook page if it has none
+func MeetingLink(event calendar.Event) (string, error) {
+	if joinLink := event.GetJoinLink(); joinLink != "" {
+		return joinLink, nil
+	}
+	if event.WebLink != "" {
+		return event.WebLink, nil
+	}
+	return "", fmt.Errorf("no link available for meeting")
+}
 
-	timeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			MarginRight(1)
+// OpenMeeting opens the meeting's join link, or its Outlook page if it has none
+func OpenMeeting(event calendar.Event) error {
+	url, err := MeetingLink(event)
+	if err != nil {
+		return err
+	}
 
-	titleStyle = lipgloss.NewStyle().
-			Bold(true)
+	return OpenURL(url)
+}
 
-	teamsIndicatorStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#0078D4")).
-				Bold(true)
-)
+// OpenURL opens a URL or file with the command configured for its kind of
+// link, or the desktop's default handler
+func OpenURL(url string) error {
+	return opener.Open(url)
+}
 
-func renderMeeting(event calendar.Event, compact bool) string {
+// renderMeeting renders the TUI line for event in the given layout preset
+func renderMeeting(event calendar.Event, layout string) string {
 	status := event.GetStatus()
 	timeUntil := event.GetTimeUntil()
 
-	var statusIndicator string
-	var style lipgloss.Style
-
-	switch status {
-	case "urgent":
-		style = urgentStyle
-		statusIndicator = "🔴"
-	case "soon":
-		style = soonStyle
-		statusIndicator = "🟡"
-	case "current":
-		style = currentStyle
-		statusIndicator = "🟢"
-	case "upcoming":
-		style = upcomingStyle
-		statusIndicator = "🔵"
-	case "past":
-		style = pastStyle
-		statusIndicator = "⚫"
-	}
-
 	title := event.Subject
-	if len(title) > 30 && compact {
-		title = title[:27] + "..."
+	if layout == LayoutCompact {
+		title = truncate(title, maxCompactTitle)
 	}
+	title = titleStyle.Strikethrough(event.IsCancelled).Render(title)
 
-	timeStr := event.Start.Format("15:04")
-	if status == "current" {
-		endTime := event.End.Format("15:04")
+	timeStr := i18n.FormatTime(event.Start)
+	if status == calendar.StatusCurrent {
+		endTime := i18n.FormatTime(event.End)
 		timeStr = fmt.Sprintf("%s-%s", timeStr, endTime)
-	} else if status == "upcoming" || status == "soon" || status == "urgent" {
+	} else if status == calendar.StatusUpcoming || status == calendar.StatusSoon || status == calendar.StatusUrgent {
 		if timeUntil < time.Hour {
-			timeStr = fmt.Sprintf("in %dm", int(timeUntil.Minutes()))
+			timeStr = i18n.T("in %dm", int(timeUntil.Minutes()))
 		} else {
-			timeStr = fmt.Sprintf("in %dh%dm", int(timeUntil.Hours()), int(timeUntil.Minutes())%60)
+			timeStr = i18n.T("in %dh%dm", int(timeUntil.Hours()), int(timeUntil.Minutes())%60)
 		}
 	}
 
-	var parts []string
-	parts = append(parts, statusIndicator)
-
-	if event.IsTeams {
-		parts = append(parts, teamsIndicatorStyle.Render("Teams"))
-	}
-
-	parts = append(parts, timeStyle.Render(timeStr))
-	parts = append(parts, titleStyle.Render(title))
-
-	content := strings.Join(parts, " ")
-
-	if compact {
-		return style.Render(content)
+	parts := []string{render.StatusIcon(status)}
+	switch layout {
+	case LayoutMicro:
+		parts = append(parts, microTime(&event))
+	case LayoutCompact:
+		parts = append(parts, title)
+	default:
+		if event.IsTeams {
+			parts = append(parts, teamsIndicatorStyle.Render("Teams"))
+		}
+		parts = append(parts, timeStyle.Render(timeStr), title)
+		if layout == LayoutFull && event.IsInPerson() {
+			parts = append(parts, locationStyle.Render("@ "+event.Location))
+		}
 	}
 
-	return style.Render(content)
+	return statusStyles[status].Render(strings.Join(parts, " "))
 }
 
 type WaybarOutput struct {
@@ -405,12 +617,52 @@ type WaybarOutput struct {
 	Tooltip string `json:"tooltip,omitempty"`
 	Class   string `json:"class,omitempty"`
 	Alt     string `json:"alt,omitempty"`
+	// Percentage is how far the shown meeting has run, or how close it is
+	// to starting; see meetingPercentage
+	Percentage int `json:"percentage,omitempty"`
+	// ExtraClasses are added after Class, e.g. "cat-red" for category colors
+	ExtraClasses []string `json:"-"`
 }
 
-func generateWaybarOutput(meeting *calendar.Event) WaybarOutput {
+var singleClass bool
+
+// SetSingleClass makes outputs carry only their status class, as a string,
+// for styles written before classes came as a list
+func SetSingleClass(enabled bool) {
+	singleClass = enabled
+}
+
+// MarshalJSON emits class as an array when there are extra classes; waybar
+// accepts either a single class or a list
+func (o WaybarOutput) MarshalJSON() ([]byte, error) {
+	type plain WaybarOutput
+	if len(o.ExtraClasses) == 0 || singleClass {
+		return json.Marshal(plain(o))
+	}
+
+	var classes []string
+	if o.Class != "" {
+		classes = append(classes, o.Class)
+	}
+	classes = append(classes, o.ExtraClasses...)
+
+	return json.Marshal(struct {
+		plain
+		Class []string `json:"class"`
+	}{plain(o), classes})
+}
+
+// generateWaybarOutput renders the bar for meeting. With showRemaining a
+// running meeting shows how long it has left.
+func generateWaybarOutput(meeting *calendar.Event, showRemaining bool) WaybarOutput {
+	return generateBarOutput(render.Pango, meeting, showRemaining)
+}
+
+// generateBarOutput renders the bar for meeting with the markup of t
+func generateBarOutput(t render.Target, meeting *calendar.Event, showRemaining bool) WaybarOutput {
 	if meeting == nil {
 		return WaybarOutput{
-			Text:  "No meetings",
+			Text:  i18n.T("No meetings"),
 			Class: "no-meeting",
 			Alt:   "no-meeting",
 		}
@@ -418,326 +670,204 @@ func generateWaybarOutput(meeting *calendar.Event) WaybarOutput {
 
 	status := meeting.GetStatus()
 	timeUntil := meeting.GetTimeUntil()
+	travel := meeting.TravelTime()
+	icon := render.StatusIcon(status)
 
 	var text, class, alt string
 
-	subject := escapePangoMarkup(meeting.Subject)
+	subject := meeting.Subject
 
 	switch status {
-	case "urgent":
-		text = fmt.Sprintf("🔴 %s", subject)
-		if len(text) > 50 {
-			text = fmt.Sprintf("🔴 %s...", subject[:45])
-		}
-		class = "urgent"
-		alt = "urgent"
-	case "soon":
-		text = fmt.Sprintf("🟡 %s", subject)
-		if len(text) > 50 {
-			text = fmt.Sprintf("🟡 %s...", subject[:45])
+	case calendar.StatusUrgent, calendar.StatusSoon, calendar.StatusCurrent, calendar.StatusPast:
+		subject = truncate(subject, maxSubjectWidth)
+		var suffix string
+		switch {
+		case travel > 0 && (status == calendar.StatusUrgent || status == calendar.StatusSoon):
+			suffix = "(" + i18n.T("leave by %s", i18n.FormatTime(meeting.LeaveBy())) + ")"
+		case showRemaining && status == calendar.StatusCurrent:
+			suffix = "· " + i18n.T("%s left", formatDuration(timeLeft(meeting)))
 		}
-		class = "soon"
-		alt = "soon"
-	case "current":
-		text = fmt.Sprintf("🟢 %s", subject)
-		if len(text) > 50 {
-			text = fmt.Sprintf("🟢 %s...", subject[:45])
+		text = formatBarText(t, icon, status, subject, suffix)
+		class = status
+		alt = status
+	case calendar.StatusUpcoming:
+		var until string
+		switch {
+		case travel > 0:
+			untilLeave := timeUntil - travel
+			if untilLeave < time.Hour {
+				until = i18n.T("leave in %dm", int(untilLeave.Minutes()))
+			} else {
+				until = i18n.T("leave in %dh%dm", int(untilLeave.Hours()), int(untilLeave.Minutes())%60)
+			}
+		case timeUntil < time.Hour:
+			until = i18n.T("in %dm", int(timeUntil.Minutes()))
+		default:
+			until = i18n.T("in %dh%dm", int(timeUntil.Hours()), int(timeUntil.Minutes())%60)
 		}
-		class = "current"
-		alt = "current"
-	case "upcoming":
-		if timeUntil < time.Hour {
-			text = fmt.Sprintf("🔵 %s (in %dm)", subject, int(timeUntil.Minutes()))
+		// A long subject leaves no room for the countdown, so it is cut
+		// shorter and the countdown dropped
+		if textWidth(fmt.Sprintf("%s %s (%s)", icon, subject, until)) > maxSubjectWidth+5 && textWidth(subject) > maxSubjectWidth-5 {
+			text = formatBarText(t, icon, status, truncate(subject, maxSubjectWidth-5), "")
 		} else {
-			text = fmt.Sprintf("🔵 %s (in %dh%dm)", subject, int(timeUntil.Hours()), int(timeUntil.Minutes())%60)
-		}
-		if len(text) > 50 {
-			text = fmt.Sprintf("🔵 %s...", subject[:40])
+			text = formatBarText(t, icon, status, subject, "("+until+")")
 		}
-		class = "upcoming"
-		alt = "upcoming"
-	case "past":
-		text = fmt.Sprintf("⚫ %s", subject)
-		if len(text) > 50 {
-			text = fmt.Sprintf("⚫ %s...", subject[:45])
-		}
-		class = "past"
-		alt = "past"
+		class = calendar.StatusUpcoming
+		alt = calendar.StatusUpcoming
+	}
+
+	if layoutText := layoutBarText(t, meeting, icon, status); layoutText != "" {
+		text = layoutText
 	}
 
+	text = t.Cancelled(meeting, text)
 	if meeting.IsTeams {
 		text = "[T] " + text
 	}
 
-	return WaybarOutput{
-		Text:  text,
-		Class: class,
-		Alt:   alt,
+	output := WaybarOutput{
+		Text:         text,
+		Class:        class,
+		Alt:          alt,
+		Percentage:   meetingPercentage(meeting),
+		ExtraClasses: meetingClasses(meeting),
 	}
+
+	return output
 }
 
-func escapePangoMarkup(s string) string {
-	s = strings.ReplaceAll(s, "&", "&amp;")
-	s = strings.ReplaceAll(s, "<", "&lt;")
-	s = strings.ReplaceAll(s, ">", "&gt;")
-	return s
+// formatBarText builds the bar label from raw (unescaped) parts
+func formatBarText(t render.Target, icon, status, subject, suffix string) string {
+	text := t.Status(status, icon) + " " + t.Bold(t.Escape(subject))
+	if suffix != "" {
+		text += " " + t.Dim(t.Escape(suffix))
+	}
+	return text
 }
 
-func generateWaybarOutputForSchedule(displayEvent *calendar.Event, allEvents []calendar.Event) WaybarOutput {
+func generateFreeSlotOutput(todaysEvents []calendar.Event, minDuration time.Duration) WaybarOutput {
+	now := calendar.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	slot := calendar.NextFreeSlot(todaysEvents, now, endOfDay, minDuration)
+
+	output := WaybarOutput{
+		Tooltip: generateTooltipForSchedule(todaysEvents),
+	}
+
+	switch {
+	case slot == nil:
+		output.Text = i18n.T("No free time today")
+		output.Class = "busy"
+		output.Alt = "busy"
+	case slot.IsNow() && !slot.End.Before(endOfDay):
+		output.Text = i18n.T("Free rest of day")
+		output.Class = "free"
+		output.Alt = "free"
+	case slot.IsNow():
+		output.Text = i18n.T("Free until %s", i18n.FormatTime(slot.End))
+		output.Class = "free"
+		output.Alt = "free"
+	default:
+		output.Text = i18n.T("Next free: %s–%s", i18n.FormatTime(slot.Start), i18n.FormatTime(slot.End))
+		output.Class = "busy"
+		output.Alt = "busy"
+	}
+
+	return output
+}
+
+// applySnooze swaps meeting status classes for "muted" while snoozed
+func applySnooze(output WaybarOutput) WaybarOutput {
+	until, ok := snooze.Active()
+	if !ok {
+		return output
+	}
+
+	if calendar.IsPending(output.Class) {
+		output.Class = "muted"
+	}
+	output.Tooltip += "\n\n🔕 " + i18n.T("Snoozed until %s", i18n.FormatTime(until))
+
+	return output
+}
+
+func generateWaybarOutputForSchedule(displayEvent *calendar.Event, allEvents []calendar.Event, showRemaining bool) WaybarOutput {
 	if displayEvent == nil {
 		return WaybarOutput{
-			Text:    "No meetings today",
+			Text:    i18n.T("No meetings today"),
 			Class:   "no-meeting",
 			Alt:     "no-meeting",
-			Tooltip: "No meetings scheduled for today",
+			Tooltip: i18n.T("No meetings scheduled for today"),
 		}
 	}
 
 	// Generate the main display text
-	baseOutput := generateWaybarOutput(displayEvent)
+	baseOutput := generateWaybarOutput(displayEvent, showRemaining)
 
 	// Generate tooltip with full day schedule
-	var tooltipLines []string
-	tooltipLines = append(tooltipLines, "📅 Today's Schedule:")
-	tooltipLines = append(tooltipLines, "")
-
-	if len(allEvents) == 0 {
-		tooltipLines = append(tooltipLines, "No meetings today")
-	} else {
-		for _, event := range allEvents {
-			timeStr := fmt.Sprintf("%s-%s",
-				event.Start.Format("15:04"),
-				event.End.Format("15:04"))
-
-			status := event.GetStatus()
-			var indicator string
-			switch status {
-			case "current":
-				indicator = "🟢"
-			case "urgent":
-				indicator = "🔴"
-			case "soon":
-				indicator = "🟡"
-			case "upcoming":
-				indicator = "🔵"
-			case "past":
-				indicator = "⚫"
-			default:
-				indicator = "📅"
-			}
-
-			title := escapePangoMarkup(event.Subject)
-			if event.IsTeams {
-				title = title + " (Teams)"
-			}
-
-			if event.Location != "" && !event.IsTeams {
-				title = title + " @ " + escapePangoMarkup(event.Location)
-			}
-
-			line := fmt.Sprintf("%s %s %s", indicator, timeStr, title)
-			tooltipLines = append(tooltipLines, line)
-		}
-
+	tooltipLines := []string{generateTooltipForSchedule(allEvents)}
+	if len(allEvents) > 0 {
 		tooltipLines = append(tooltipLines, "")
-		tooltipLines = append(tooltipLines, "💡 Click to open meeting link")
+		tooltipLines = append(tooltipLines, "💡 "+i18n.T("Click to open meeting link"))
 		if displayEvent.IsTeams {
-			tooltipLines = append(tooltipLines, "🔗 Teams meeting - will open directly in Teams")
+			tooltipLines = append(tooltipLines, "🔗 "+i18n.T("Teams meeting - will open directly in Teams"))
 		} else {
-			tooltipLines = append(tooltipLines, "🌐 Will open in browser")
+			tooltipLines = append(tooltipLines, "🌐 "+i18n.T("Will open in browser"))
+		}
+		if displayEvent.DialIn != nil {
+			tooltipLines = append(tooltipLines, "☎ "+render.Pango.Escape(i18n.T("Dial-in: %s", formatDialIn(displayEvent.DialIn))))
 		}
 	}
 
 	baseOutput.Tooltip = strings.Join(tooltipLines, "\n")
-	return baseOutput
-}
-
-func generateTooltipForSchedule(todaysEvents []calendar.Event) string {
-	var tooltipLines []string
-	tooltipLines = append(tooltipLines, "📅 Today's Schedule:")
-	tooltipLines = append(tooltipLines, "")
-
-	if len(todaysEvents) == 0 {
-		tooltipLines = append(tooltipLines, "No meetings today")
-	} else {
-		for _, event := range todaysEvents {
-			timeStr := fmt.Sprintf("%s-%s",
-				event.Start.Format("15:04"),
-				event.End.Format("15:04"))
-
-			status := event.GetStatus()
-			var indicator string
-			switch status {
-			case "current":
-				indicator = "🟢"
-			case "urgent":
-				indicator = "🔴"
-			case "soon":
-				indicator = "🟡"
-			case "upcoming":
-				indicator = "🔵"
-			case "past":
-				indicator = "⚫"
-			default:
-				indicator = "📅"
-			}
-
-			title := escapePangoMarkup(event.Subject)
-			if event.IsTeams {
-				title = title + " (Teams)"
-			}
-
-			if event.Location != "" && !event.IsTeams {
-				title = title + " @ " + escapePangoMarkup(event.Location)
-			}
-
-			line := fmt.Sprintf("%s %s %s", indicator, timeStr, title)
-			tooltipLines = append(tooltipLines, line)
-		}
+	if calendar.HasConflict(*displayEvent, allEvents) {
+		baseOutput.ExtraClasses = append(baseOutput.ExtraClasses, "conflict")
 	}
-
-	return strings.Join(tooltipLines, "\n")
+	return baseOutput
 }
 
-func selectBestEvent(events []calendar.Event) *calendar.Event {
-	if len(events) == 0 {
-		return nil
+// formatDialIn renders dial-in details, e.g. "+45 32 72 66 19, ID 123 456 789#"
+func formatDialIn(dialIn *calendar.DialIn) string {
+	parts := []string{dialIn.Number}
+	if dialIn.ConferenceID != "" {
+		parts = append(parts, i18n.T("ID %s#", dialIn.ConferenceID))
 	}
-
-	now := time.Now()
-	statusPriority := []string{"current", "urgent", "soon", "upcoming"}
-
-	// For each status level, first look for blocking events, then fall back to any event
-	for _, targetStatus := range statusPriority {
-		// First pass: find blocking events with this status
-		for _, event := range events {
-			status := event.GetStatus()
-			if status == targetStatus && event.IsBlockingEvent() {
-				if targetStatus == "upcoming" && !event.Start.After(now) {
-					continue
-				}
-				return &event
-			}
-		}
-
-		// Second pass: find any event with this status (fallback for all-day/long events)
-		for _, event := range events {
-			status := event.GetStatus()
-			if status == targetStatus {
-				if targetStatus == "upcoming" && !event.Start.After(now) {
-					continue
-				}
-				return &event
-			}
-		}
+	if dialIn.Passcode != "" {
+		parts = append(parts, i18n.T("passcode %s#", dialIn.Passcode))
 	}
+	return strings.Join(parts, ", ")
+}
 
-	return nil
+func generateTooltipForSchedule(todaysEvents []calendar.Event) string {
+	header := []string{"📅 " + render.Pango.Bold(i18n.T("Today's Schedule")+":"), ""}
+	return strings.Join(append(header, render.Schedule(render.Pango, todaysEvents)...), "\n")
 }
 
 func renderExtendedTooltip(todaysEvents []calendar.Event, upcomingEvents []calendar.Event) string {
 	var lines []string
 
 	// Today's events
-	lines = append(lines, titleStyle.Render("📅 Today's Schedule"))
+	lines = append(lines, titleStyle.Render("📅 "+i18n.T("Today's Schedule")))
 	lines = append(lines, "")
+	lines = append(lines, render.Schedule(render.ANSI, todaysEvents)...)
 
-	if len(todaysEvents) == 0 {
-		lines = append(lines, "No meetings today")
-	} else {
-		for _, event := range todaysEvents {
-			timeStr := fmt.Sprintf("%s-%s",
-				event.Start.Format("15:04"),
-				event.End.Format("15:04"))
-
-			status := event.GetStatus()
-			var indicator string
-			switch status {
-			case "current":
-				indicator = "🟢"
-			case "urgent":
-				indicator = "🔴"
-			case "soon":
-				indicator = "🟡"
-			case "upcoming":
-				indicator = "🔵"
-			case "past":
-				indicator = "⚫"
-			default:
-				indicator = "📅"
-			}
-
-			title := event.Subject
-			if event.IsTeams {
-				title = title + " (Teams)"
-			}
-
-			if event.Location != "" && !event.IsTeams {
-				title = title + " @ " + event.Location
-			}
-
-			line := fmt.Sprintf("%s %s %s", indicator, timeStyle.Render(timeStr), title)
-			lines = append(lines, line)
-		}
-	}
-
-	// Upcoming events (next 7 days)
+	// Upcoming events (lookahead window)
 	lines = append(lines, "")
-	lines = append(lines, titleStyle.Render("🔮 Upcoming Events"))
+	lines = append(lines, titleStyle.Render("🔮 "+i18n.T("Upcoming Events")))
 	lines = append(lines, "")
 
 	if len(upcomingEvents) == 0 {
-		lines = append(lines, "No upcoming meetings")
+		lines = append(lines, i18n.T("No upcoming meetings"))
 	} else {
-		now := time.Now()
 		for i, event := range upcomingEvents {
 			// Show only next 5 events to keep tooltip manageable
 			if i >= 5 {
-				lines = append(lines, fmt.Sprintf("... and %d more events", len(upcomingEvents)-5))
+				lines = append(lines, i18n.T("... and %d more events", len(upcomingEvents)-5))
 				break
 			}
-
-			// Format date and time
-			var dateTimeStr string
-			if event.Start.Format("2006-01-02") == now.Format("2006-01-02") {
-				// Today - just show time
-				dateTimeStr = event.Start.Format("15:04")
-			} else if event.Start.Format("2006-01-02") == now.AddDate(0, 0, 1).Format("2006-01-02") {
-				// Tomorrow - show "Tomorrow 15:04"
-				dateTimeStr = "Tomorrow " + event.Start.Format("15:04")
-			} else {
-				// Other days - show "Mon 24/9 15:04"
-				dateTimeStr = event.Start.Format("Mon 2/1 15:04")
-			}
-
-			status := event.GetStatus()
-			var indicator string
-			switch status {
-			case "current":
-				indicator = "🟢"
-			case "urgent":
-				indicator = "🔴"
-			case "soon":
-				indicator = "🟡"
-			case "upcoming":
-				indicator = "🔵"
-			case "past":
-				indicator = "⚫"
-			default:
-				indicator = "📅"
-			}
-
-			title := event.Subject
-			if event.IsTeams {
-				title = title + " (Teams)"
-			}
-
-			if event.Location != "" && !event.IsTeams {
-				title = title + " @ " + event.Location
-			}
-
-			line := fmt.Sprintf("%s %s %s", indicator, timeStyle.Render(dateTimeStr), title)
-			lines = append(lines, line)
+			lines = append(lines, render.EventLine(render.ANSI, event, render.StartDay(event)))
 		}
 	}
 
@@ -0,0 +1,88 @@
+// Package xdg places the widget's files per the XDG base directory
+// specification: settings in XDG_CONFIG_HOME, the event cache in
+// XDG_CACHE_HOME and tokens and runtime state in XDG_STATE_HOME.
+//
+// Older versions kept everything in ~/.config/calendar-widget. Files found
+// there are moved to their new place the first time they are looked up.
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// appName is the directory the widget's files live in under each base
+const appName = "calendar-widget"
+
+// ConfigPath returns the path of a configuration file, e.g. settings.json
+func ConfigPath(name string) string {
+	return resolve(baseDir("XDG_CONFIG_HOME", ".config"), name)
+}
+
+// CachePath returns the path of a file that can be rebuilt, e.g. the event
+// cache
+func CachePath(name string) string {
+	return resolve(baseDir("XDG_CACHE_HOME", ".cache"), name)
+}
+
+// StatePath returns the path of a file that should survive restarts but
+// isn't configuration, e.g. tokens or the snooze state
+func StatePath(name string) string {
+	return resolve(baseDir("XDG_STATE_HOME", filepath.Join(".local", "state")), name)
+}
+
+// baseDir returns $env, or fallback under the home directory when it is
+// unset or not absolute, as the specification asks
+func baseDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, fallback)
+}
+
+// legacyDir is where all files lived before the XDG directories were used
+func legacyDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", appName)
+}
+
+// migrated remembers the files already looked up by this process, so the
+// legacy directory is only checked once per file
+var migrated sync.Map
+
+// resolve returns name's path under base, first moving the file there from
+// the legacy directory if only that has it. When the move fails, e.g.
+// across file systems, the legacy file keeps being used.
+func resolve(base, name string) string {
+	path := filepath.Join(base, appName, name)
+	if cached, ok := migrated.Load(path); ok {
+		return cached.(string)
+	}
+
+	resolved := path
+	legacy := filepath.Join(legacyDir(), name)
+	if legacy != path && exists(legacy) && !exists(path) {
+		if err := move(legacy, path); err != nil && !exists(path) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to move %s to %s, still using the old location: %v\n", legacy, path, err)
+			resolved = legacy
+		}
+	}
+
+	migrated.Store(path, resolved)
+	return resolved
+}
+
+func move(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}